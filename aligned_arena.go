@@ -0,0 +1,58 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+// AlignedArena carves aligned allocations out of large page-aligned
+// regions.
+//
+// Each AlignedMem call over-allocates by up to pageSize-1 bytes to find an
+// aligned start, so thousands of small aligned blocks waste roughly a page
+// each. An AlignedArena pays that cost once per region: allocations are
+// rounded up to the alignment and placed back to back. When a region is
+// exhausted the arena reserves another one; memory is reclaimed by the
+// garbage collector once the arena and every allocation are unreachable.
+//
+// An AlignedArena must not be used from multiple goroutines concurrently.
+type AlignedArena struct {
+	align      uintptr
+	regionSize int
+	region     []byte // Unused tail of the current region
+}
+
+// NewAlignedArena returns an arena that reserves regions of regionSize
+// bytes and aligns every allocation to align, which must be a power of two,
+// e.g., PageSize. Panics if regionSize < 1 or align is not a power of two.
+func NewAlignedArena(regionSize int, align uintptr) *AlignedArena {
+	if regionSize < 1 {
+		panic("iobuf.NewAlignedArena: region size must be positive")
+	}
+	if align == 0 || align&(align-1) != 0 {
+		panic("iobuf.NewAlignedArena: alignment must be a power of two")
+	}
+	return &AlignedArena{align: align, regionSize: regionSize}
+}
+
+// Alloc returns a zeroed byte slice of the given size whose start is
+// aligned to the arena's alignment. Sizes larger than the region size get
+// a dedicated region. The slice is capped at size.
+// Panics if size < 1.
+func (a *AlignedArena) Alloc(size int) []byte {
+	if size < 1 {
+		panic("iobuf.AlignedArena.Alloc: size must be positive")
+	}
+	span := int((uintptr(size) + a.align - 1) &^ (a.align - 1))
+	if span > len(a.region) {
+		if span > a.regionSize {
+			return AlignedMem(span, a.align)[:size:size]
+		}
+		a.region = AlignedMem(int((uintptr(a.regionSize)+a.align-1)&^(a.align-1)), a.align)
+	}
+	b := a.region[:size:size]
+	a.region = a.region[span:]
+	return b
+}
+
+// Remaining returns the number of bytes left in the current region.
+func (a *AlignedArena) Remaining() int { return len(a.region) }
@@ -0,0 +1,62 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestAlignedArena_Alloc(t *testing.T) {
+	a := iobuf.NewAlignedArena(16*4096, 4096)
+	var prev uintptr
+	for i := range 16 {
+		b := a.Alloc(4096)
+		addr := uintptr(unsafe.Pointer(unsafe.SliceData(b)))
+		if addr%4096 != 0 {
+			t.Fatalf("block %d not page-aligned", i)
+		}
+		if i > 0 && addr != prev+4096 {
+			t.Errorf("block %d not packed after the previous one", i)
+		}
+		prev = addr
+	}
+	if a.Remaining() != 0 {
+		t.Errorf("Remaining() = %d, want 0", a.Remaining())
+	}
+
+	// Odd sizes are rounded so the next block stays aligned.
+	b := a.Alloc(100)
+	if len(b) != 100 || cap(b) != 100 {
+		t.Errorf("len=%d cap=%d, want 100", len(b), cap(b))
+	}
+	c := a.Alloc(1)
+	if uintptr(unsafe.Pointer(unsafe.SliceData(c)))-uintptr(unsafe.Pointer(unsafe.SliceData(b))) != 4096 {
+		t.Error("allocation after odd size not aligned to next page")
+	}
+}
+
+func TestAlignedArena_Oversized(t *testing.T) {
+	a := iobuf.NewAlignedArena(4096, 64)
+	a.Alloc(64)
+	big := a.Alloc(10000)
+	if len(big) != 10000 || uintptr(unsafe.Pointer(unsafe.SliceData(big)))%64 != 0 {
+		t.Errorf("oversized allocation len=%d misaligned", len(big))
+	}
+	if a.Remaining() != 4096-64 {
+		t.Errorf("oversized allocation consumed the current region: Remaining() = %d", a.Remaining())
+	}
+}
+
+func TestAlignedArena_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewAlignedArena with non-power-of-two alignment should panic")
+		}
+	}()
+	iobuf.NewAlignedArena(4096, 3000)
+}
@@ -0,0 +1,69 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "unsafe"
+
+// AlignedBlockPool recycles page-aligned blocks, replacing repeated
+// AlignedMemBlock calls that each allocate a fresh page.
+//
+// All blocks are carved from one contiguous allocation by AlignedMemBlocks
+// and managed by a BoundedPool of indices. Get hands out a block and Put
+// takes it back; the index is recovered from the block's address, so
+// callers deal only in slices. Blocking behavior follows the underlying
+// pool's SetNonblock mode.
+type AlignedBlockPool struct {
+	_ noCopy
+
+	pool      *BoundedPool[[]byte]
+	base      uintptr
+	blockSize uintptr
+}
+
+// NewAlignedBlockPool returns a filled pool of capacity blocks of pageSize
+// bytes, each aligned to pageSize. Capacity is rounded up to a power of
+// two. Panics if capacity < 1.
+func NewAlignedBlockPool(capacity int, pageSize uintptr) *AlignedBlockPool {
+	pool := NewBoundedPool[[]byte](capacity)
+	blocks := AlignedMemBlocks(pool.Cap(), pageSize)
+	i := 0
+	pool.Fill(func() []byte {
+		b := blocks[i][:pageSize:pageSize]
+		i++
+		return b
+	})
+	return &AlignedBlockPool{
+		pool:      pool,
+		base:      uintptr(unsafe.Pointer(unsafe.SliceData(blocks[0]))),
+		blockSize: pageSize,
+	}
+}
+
+// Pool returns the BoundedPool of block indices.
+func (p *AlignedBlockPool) Pool() *BoundedPool[[]byte] { return p.pool }
+
+// Cap returns the number of blocks managed by the pool.
+func (p *AlignedBlockPool) Cap() int { return p.pool.Cap() }
+
+// Get acquires a block. Its contents are whatever the previous holder left.
+// Returns iox.ErrWouldBlock if the pool is non-blocking and empty.
+func (p *AlignedBlockPool) Get() ([]byte, error) {
+	indirect, err := p.pool.Get()
+	if err != nil {
+		return nil, err
+	}
+	return p.pool.Value(indirect), nil
+}
+
+// Put returns a block obtained from Get. The block may have been resliced
+// but must still start at its original address.
+// Panics if block was not handed out by this pool.
+func (p *AlignedBlockPool) Put(block []byte) error {
+	off := uintptr(unsafe.Pointer(unsafe.SliceData(block))) - p.base
+	if cap(block) == 0 || off%p.blockSize != 0 || off/p.blockSize >= uintptr(p.pool.Cap()) {
+		panic("iobuf.AlignedBlockPool.Put: block not from this pool")
+	}
+	return p.pool.Put(int(off / p.blockSize))
+}
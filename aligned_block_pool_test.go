@@ -0,0 +1,60 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"errors"
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+	"code.hybscloud.com/iox"
+)
+
+func TestAlignedBlockPool_GetPut(t *testing.T) {
+	p := iobuf.NewAlignedBlockPool(3, iobuf.PageSize)
+	if p.Cap() != 4 {
+		t.Fatalf("Cap() = %d, want 4", p.Cap())
+	}
+	p.Pool().SetNonblock(true)
+
+	seen := make(map[*byte]bool)
+	var blocks [][]byte
+	for range p.Cap() {
+		b, err := p.Get()
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		if len(b) != int(iobuf.PageSize) || uintptr(unsafe.Pointer(unsafe.SliceData(b)))%iobuf.PageSize != 0 {
+			t.Errorf("block len=%d not a page-aligned page", len(b))
+		}
+		if seen[&b[0]] {
+			t.Fatal("block handed out twice")
+		}
+		seen[&b[0]] = true
+		blocks = append(blocks, b)
+	}
+	if _, err := p.Get(); !errors.Is(err, iox.ErrWouldBlock) {
+		t.Errorf("Get() on empty pool = %v, want ErrWouldBlock", err)
+	}
+	for _, b := range blocks {
+		if err := p.Put(b[:1]); err != nil {
+			t.Fatalf("Put() failed: %v", err)
+		}
+	}
+	if b, err := p.Get(); err != nil || !seen[&b[0]] {
+		t.Errorf("Get() after Put = %v, want a recycled block", err)
+	}
+}
+
+func TestAlignedBlockPool_PutForeign(t *testing.T) {
+	p := iobuf.NewAlignedBlockPool(1, iobuf.PageSize)
+	defer func() {
+		if recover() == nil {
+			t.Error("Put of a foreign block should panic")
+		}
+	}()
+	_ = p.Put(iobuf.AlignedMemBlock())
+}
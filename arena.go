@@ -0,0 +1,69 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+// arenaAlign is the alignment of every Arena allocation.
+const arenaAlign = 8
+
+// Arena is a bump-pointer allocator for request-scoped byte slices.
+//
+// Allocations are carved from pre-reserved page-aligned regions by
+// advancing an offset, and Reset recycles every allocation at once in
+// constant time while keeping the regions for the next request. Parsing a
+// request into an Arena and resetting it afterwards makes the temporary
+// allocations effectively free.
+//
+// Slices returned by Alloc must not be used after Reset. An Arena must not
+// be used from multiple goroutines concurrently.
+type Arena struct {
+	regionSize int
+	regions    [][]byte
+	cur        int // Index of the region being carved
+	off        int // Offset within regions[cur]
+}
+
+// NewArena returns an Arena that reserves regions of regionSize bytes,
+// rounded up to PageSize. Panics if regionSize < 1.
+func NewArena(regionSize int) *Arena {
+	if regionSize < 1 {
+		panic("iobuf.NewArena: region size must be positive")
+	}
+	ps := int(PageSize)
+	return &Arena{regionSize: (regionSize + ps - 1) / ps * ps}
+}
+
+// Alloc returns a byte slice of length and capacity n aligned to 8 bytes.
+// The contents are undefined after a Reset; clear the slice if zeroed
+// memory is required. Requests larger than the region size are served
+// from the heap and are not recycled. Panics if n is negative.
+func (a *Arena) Alloc(n int) []byte {
+	if n < 0 {
+		panic("iobuf.Arena.Alloc: negative size")
+	}
+	if n > a.regionSize {
+		return make([]byte, n)
+	}
+	span := (n + arenaAlign - 1) &^ (arenaAlign - 1)
+	for {
+		if a.cur == len(a.regions) {
+			a.regions = append(a.regions, AlignedMem(a.regionSize, PageSize))
+		}
+		if a.off+n <= len(a.regions[a.cur]) {
+			b := a.regions[a.cur][a.off : a.off+n : a.off+n]
+			a.off = min(a.off+span, len(a.regions[a.cur]))
+			return b
+		}
+		a.cur++
+		a.off = 0
+	}
+}
+
+// Reset recycles every allocation at once, keeping the reserved regions.
+func (a *Arena) Reset() {
+	a.cur, a.off = 0, 0
+}
+
+// Reserved returns the total size of the regions held by the arena.
+func (a *Arena) Reserved() int { return len(a.regions) * a.regionSize }
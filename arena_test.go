@@ -0,0 +1,61 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestArena_AllocAndReset(t *testing.T) {
+	a := iobuf.NewArena(int(iobuf.PageSize))
+	first := a.Alloc(3)
+	second := a.Alloc(100)
+	if len(first) != 3 || cap(first) != 3 || len(second) != 100 {
+		t.Fatalf("len/cap mismatch: %d/%d, %d", len(first), cap(first), len(second))
+	}
+	if uintptr(unsafe.Pointer(unsafe.SliceData(second)))%8 != 0 {
+		t.Error("allocation not 8-byte aligned")
+	}
+	copy(second, "payload")
+
+	// Overflow into a second region.
+	fill := int(iobuf.PageSize) / 104
+	for range fill {
+		a.Alloc(100)
+	}
+	reserved := a.Reserved()
+	if reserved != 2*int(iobuf.PageSize) {
+		t.Errorf("Reserved() = %d, want two regions", reserved)
+	}
+
+	a.Reset()
+	again := a.Alloc(3)
+	if unsafe.SliceData(again) != unsafe.SliceData(first) {
+		t.Error("Reset did not recycle the first region")
+	}
+	for range fill + 1 {
+		a.Alloc(100)
+	}
+	if a.Reserved() != reserved {
+		t.Errorf("Reserved() grew from %d to %d after Reset", reserved, a.Reserved())
+	}
+}
+
+func TestArena_Oversized(t *testing.T) {
+	a := iobuf.NewArena(64)
+	big := a.Alloc(1 << 20)
+	if len(big) != 1<<20 {
+		t.Fatalf("len = %d", len(big))
+	}
+	if a.Reserved() != 0 {
+		t.Errorf("oversized allocation reserved a region: %d", a.Reserved())
+	}
+	if b := a.Alloc(0); len(b) != 0 {
+		t.Errorf("Alloc(0) len = %d", len(b))
+	}
+}
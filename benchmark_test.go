@@ -68,6 +68,33 @@ func BenchmarkLargeBufferPool_GetPut(b *testing.B) {
 	})
 }
 
+func BenchmarkBoundedPool_SingleProducerSingleConsumer(b *testing.B) {
+	pool := iobuf.NewSmallBufferPool(16)
+	pool.Fill(iobuf.NewSmallBuffer)
+	pool.SetSingleProducer(true)
+	pool.SetSingleConsumer(true)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx, _ := pool.Get()
+		_ = pool.Put(idx)
+	}
+}
+
+func BenchmarkNodePool_GetPut(b *testing.B) {
+	p, err := iobuf.NewNodePool(16, iobuf.NewSmallBuffer)
+	if err != nil {
+		b.Skipf("NUMA binding not available: %v", err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx, _ := p.Get()
+		_ = p.Put(idx)
+	}
+}
+
 // Memory allocation benchmarks
 
 func BenchmarkAlignedMemBlock(b *testing.B) {
@@ -76,6 +103,28 @@ func BenchmarkAlignedMemBlock(b *testing.B) {
 	}
 }
 
+func BenchmarkMemZero_64K(b *testing.B) {
+	buf := make([]byte, 64<<10)
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		iobuf.MemZero(buf)
+	}
+}
+
+func BenchmarkAcquireVec(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		iobuf.ReleaseVec(iobuf.AcquireVec(16))
+	}
+}
+
+func BenchmarkAlignedBlockPool_GetPut(b *testing.B) {
+	p := iobuf.NewAlignedBlockPool(16, iobuf.PageSize)
+	for i := 0; i < b.N; i++ {
+		block, _ := p.Get()
+		_ = p.Put(block)
+	}
+}
+
 func BenchmarkAlignedMem_4K(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_ = iobuf.AlignedMem(4096, iobuf.PageSize)
@@ -211,6 +260,26 @@ func BenchmarkPool_HighContention_TinyPool(b *testing.B) {
 	})
 }
 
+func BenchmarkPool_HighContention_TinyPoolElimination(b *testing.B) {
+	// Tiny pool as above, with Puts handed directly to waiting Gets
+	pool := iobuf.NewSmallBufferPool(4)
+	pool.Fill(iobuf.NewSmallBuffer)
+	pool.SetElimination(4)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			idx, err := pool.Get()
+			if err != nil {
+				b.Fatal(err)
+			}
+			// Simulate I/O latency
+			spin.Yield()
+			_ = pool.Put(idx)
+		}
+	})
+}
+
 func BenchmarkPool_Contention_MediumBuffer(b *testing.B) {
 	// Medium buffers with moderate contention
 	pool := iobuf.NewMediumBufferPool(32)
@@ -246,3 +315,14 @@ func BenchmarkPool_Contention_LargeBuffer(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkArena_Alloc(b *testing.B) {
+	a := iobuf.NewArena(1 << 16)
+	b.ReportAllocs()
+	for i := range b.N {
+		_ = a.Alloc(48)
+		if i%1000 == 999 {
+			a.Reset()
+		}
+	}
+}
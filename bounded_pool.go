@@ -5,6 +5,7 @@
 package iobuf
 
 import (
+	"fmt"
 	"math"
 	"sync/atomic"
 	"unsafe"
@@ -113,6 +114,57 @@ func NewTitanBufferPool(capacity int) *TitanBufferBoundedPool {
 	return NewBoundedPool[TitanBuffer](capacity)
 }
 
+// NewBufferPoolFor creates a filled pool of the smallest tier whose buffers
+// can hold size bytes, and returns it together with the chosen tier.
+//
+// This lets callers size pools from protocol constants (e.g., a 1500-byte
+// MTU selects TierSmall) without hard-coding tier names. Sizes larger than
+// BufferSizeTitan select TierTitan. Size classes registered with
+// RegisterBufferSize are considered alongside the built-in tiers; their
+// pools hold []byte buffers. The pool is filled with zeroed buffers and is
+// ready for Get/Put.
+//
+// The capacity must be between 1 and math.MaxUint32 and will be rounded up to the next power of two.
+func NewBufferPoolFor(size int, capacity int) (BufferPool, BufferTier) {
+	tier := TierFor(size)
+	if tier.IsCustom() {
+		return newCustomPool(tier.Size(), capacity), tier
+	}
+	switch tier {
+	case TierPico:
+		return newFilledPool(capacity, NewPicoBuffer), tier
+	case TierNano:
+		return newFilledPool(capacity, NewNanoBuffer), tier
+	case TierMicro:
+		return newFilledPool(capacity, NewMicroBuffer), tier
+	case TierSmall:
+		return newFilledPool(capacity, NewSmallBuffer), tier
+	case TierMedium:
+		return newFilledPool(capacity, NewMediumBuffer), tier
+	case TierBig:
+		return newFilledPool(capacity, NewBigBuffer), tier
+	case TierLarge:
+		return newFilledPool(capacity, NewLargeBuffer), tier
+	case TierGreat:
+		return newFilledPool(capacity, NewGreatBuffer), tier
+	case TierHuge:
+		return newFilledPool(capacity, NewHugeBuffer), tier
+	case TierVast:
+		return newFilledPool(capacity, NewVastBuffer), tier
+	case TierGiant:
+		return newFilledPool(capacity, NewGiantBuffer), tier
+	default:
+		return newFilledPool(capacity, NewTitanBuffer), tier
+	}
+}
+
+// newFilledPool creates a bounded pool and fills it using newFunc.
+func newFilledPool[T BufferType](capacity int, newFunc func() T) *BoundedPool[T] {
+	pool := NewBoundedPool[T](capacity)
+	pool.Fill(newFunc)
+	return pool
+}
+
 // BoundedPoolItem is a type constraint for items stored in a BoundedPool.
 //
 // Any type can satisfy this interface. The constraint exists to make the
@@ -157,6 +209,13 @@ func NewBoundedPool[ItemType BoundedPoolItem](capacity int) *BoundedPool[ItemTyp
 
 		nonblocking: false,
 	}
+	if OwnershipTracking {
+		ret.owners.init(capacity)
+		ret.debug |= poolDebugOwner
+	}
+	if debugEnv != (debugSettings{}) {
+		ret.applyDebugEnv(debugEnv)
+	}
 	return &ret
 }
 
@@ -167,7 +226,17 @@ func NewBoundedPool[ItemType BoundedPoolItem](capacity int) *BoundedPool[ItemTyp
 // If the pool is full and the non-blocking mode is not set,
 // Put() calls would block until the BoundedPool is no longer full.
 // BoundedPool is safe for concurrent use.
-// The Get() and Put() methods ensure that at least one of the goroutines makes progress.
+//
+// Get and Put claim their ring position with a fetch-and-add that never
+// fails, so concurrent callers do not retry against each other. The price
+// is a weaker progress guarantee than a compare-and-swap ring: a Get that
+// has reserved an item waits for the Put that claimed the same position to
+// store its entry, and a Put likewise waits for the Get still taking the
+// slot's previous entry. If that partner is preempted between its claim
+// and its store, the waiting call spins and then yields until the partner
+// is scheduled again, even in non-blocking mode. Such waits last as long
+// as the partner's few instructions and involve at most one partner per
+// call.
 // The implementation of BoundedPool is based on the algorithms in the following paper:
 //
 //	https://nikitakoval.org/publications/ppopp20-queues.pdf
@@ -184,16 +253,42 @@ func NewBoundedPool[ItemType BoundedPoolItem](capacity int) *BoundedPool[ItemTyp
 type BoundedPool[T BoundedPoolItem] struct {
 	_ noCopy
 
-	items      []T
-	capacity   uint32
-	mask       uint32
-	entries    []atomic.Uint64
-	remapM     uint32
-	remapN     uint32
-	remapMask  uint32
-	head, tail atomic.Uint32
-
-	nonblocking bool
+	// Read-mostly configuration, shared by producers and consumers.
+	items     []T
+	capacity  uint32
+	mask      uint32
+	entries   []atomic.Uint64
+	remapM    uint32
+	remapN    uint32
+	remapMask uint32
+
+	nonblocking    bool
+	singleProducer bool // Put has one caller, see SetSingleProducer
+	singleConsumer bool // Get has one caller, see SetSingleConsumer
+
+	debug  poolDebug
+	sums   []uint64        // Checksums recorded on Put, see SetDebugChecksum
+	stats  *poolStats      // Activity counters, see SetStats
+	hooks  *PoolHooks      // Event callbacks, see SetHooks
+	log    *poolLog        // Diagnostic logger, see SetLogger
+	stall  *poolStall      // Stall detector, see SetStallHandler
+	hold   *poolHold       // Hold-time histogram, see SetHoldTime
+	owners ownerTable      // Buffer holders, see Holders
+	states []atomic.Uint32 // Index states, see SetDebugDoublePut
+	elim   []elimCell      // Rendezvous cells, see SetElimination
+	notify *poolNotify     // Availability descriptors, see SetNotify
+	waiter *poolWaiter     // Blocking policy, see SetWaitStrategy
+
+	// The cursors and counters are written by every Get and Put. Padding
+	// keeps them off the configuration's cache lines and off each other's,
+	// so consumers and producers do not invalidate each other's lines.
+	_     [internal.CacheLineSize]byte
+	head  atomic.Uint32 // Next position to dequeue, claimed by Get
+	_     [internal.CacheLineSize]byte
+	tail  atomic.Uint32 // Next position to enqueue, claimed by Put
+	_     [internal.CacheLineSize]byte
+	count atomic.Int64 // Items reserved by Put and not yet by Get
+	_     [internal.CacheLineSize]byte
 }
 
 // Fill initializes and fills the BoundedPool with a newFunc function, which is used to create new items.
@@ -208,6 +303,12 @@ type BoundedPool[T BoundedPoolItem] struct {
 //
 //	newFunc - a function that returns an instance of an item to be added to the pool.
 func (pool *BoundedPool[T]) Fill(newFunc func() T) {
+	if size := unsafe.Sizeof(*new(T)); size >= BufferSizeHuge {
+		// Advise before the items are written so the first touch can
+		// fault in transparent huge pages. Failure is harmless.
+		items := pool.items[:cap(pool.items)]
+		_ = MadviseHuge(unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(items))), uintptr(len(items))*size))
+	}
 	for range pool.capacity {
 		pool.items = append(pool.items, newFunc())
 	}
@@ -216,10 +317,13 @@ func (pool *BoundedPool[T]) Fill(newFunc func() T) {
 		pool.entries[i].Store(uint64(i))
 	}
 	pool.tail.Store(pool.capacity)
+	pool.count.Store(int64(pool.capacity))
 }
 
 // SetNonblock enables or disables the non-blocking mode of the pool.
-// When nonblocking is set to true, Get() and Put() calls will not block and return immediately.
+// When nonblocking is set to true, Get() and Put() calls do not wait for an empty or full pool
+// and return iox.ErrWouldBlock instead; they may still briefly wait for a preempted partner,
+// as described on BoundedPool.
 // When nonblocking is set to false, Get() calls will block until an item is available,
 // and Put() calls will block until the pool is no longer full.
 //
@@ -267,32 +371,81 @@ func (pool *BoundedPool[T]) SetValue(indirect int, value T) {
 	pool.items[indirect] = value
 }
 
+// Bytes returns a byte view of the buffer at the specified indirect index.
+//
+// Unlike Value, which returns a copy for array buffer types, the view
+// aliases pool memory: writes through it are visible to later holders of
+// the same index. For []byte items the view is the referenced slice.
+// The given indirect index must not be marked as empty and must be within the valid range.
+func (pool *BoundedPool[T]) Bytes(indirect int) []byte {
+	if len(pool.items) != int(pool.capacity) {
+		panic("must Fill the pool before using it")
+	}
+	if indirect&boundedPoolEntryEmpty == boundedPoolEntryEmpty {
+		panic("invalid bounded pool indirect")
+	}
+	if indirect < 0 || indirect >= int(pool.capacity) {
+		panic("invalid bounded pool indirect")
+	}
+
+	return pool.itemBytes(indirect)
+}
+
 // Get retrieves an item from the pool and returns its indirect index.
 // If an item is available, its indirect index and a nil error are returned.
 // Returns iox.ErrWouldBlock if the pool is empty and nonblocking mode is set.
 //
-// In blocking mode, Get uses adaptive waiting (iox.Backoff) when the
-// pool is empty. This acknowledges that buffer exhaustion is an external I/O
-// event—buffers are released when the kernel/network finishes processing—
-// requiring OS-level sleep rather than hardware-level spin.
+// In blocking mode, Get waits with the pool's WaitStrategy when the pool
+// is empty, by default adaptive waiting (iox.Backoff). This acknowledges
+// that buffer exhaustion is an external I/O event—buffers are released
+// when the kernel/network finishes processing—requiring OS-level sleep
+// rather than hardware-level spin.
+//
+// Get returns iox.ErrWouldBlock only if the pool holds no item. Once it
+// has reserved one, it completes even in nonblocking mode, which may mean
+// waiting for a preempted Put to store that item; see BoundedPool.
 func (pool *BoundedPool[T]) Get() (indirect int, err error) {
 	if len(pool.items) != int(pool.capacity) {
 		panic("must Fill the pool before using it")
 	}
 	var aw iox.Backoff
-	for {
+	var wait poolWait
+	for attempt := 1; ; attempt++ {
 		entry, err := pool.tryGet()
+		if err != nil && pool.elim != nil && !pool.nonblocking {
+			entry, err = pool.eliminateGet()
+		}
 		if err == nil {
-			return int(entry & uint64(pool.mask)), nil
+			indirect = int(entry & uint64(pool.mask))
+			if pool.debug != 0 {
+				if wait.waiting() {
+					pool.endWait(PoolGet, &wait)
+				}
+				pool.onGet(indirect)
+			}
+			return indirect, nil
 		}
 		// tryGet only returns ErrWouldBlock on empty pool
 		if pool.nonblocking {
+			if pool.debug&poolDebugStats != 0 {
+				pool.stats.wouldBlocks.Add(1)
+			}
+			if pool.debug&poolDebugLog != 0 {
+				pool.logExhausted()
+			}
 			return boundedPoolEntryEmpty, err
 		}
+		if !wait.waiting() && pool.debug&poolDebugWait != 0 {
+			wait = pool.beginWait(PoolGet)
+		} else if pool.debug&poolDebugWatch != 0 {
+			pool.watchWait(PoolGet, &wait)
+		}
+		if pool.debug&poolDebugStats != 0 {
+			pool.stats.backoffs.Add(1)
+		}
 		// Buffer exhaustion: external I/O scale event.
-		// Use adaptive waiting to yield CPU while waiting for
-		// network/disk completion to release buffers.
-		aw.Wait()
+		// Wait for network/disk completion to release buffers.
+		pool.wait(PoolGet, &aw, attempt)
 	}
 }
 
@@ -302,28 +455,66 @@ func (pool *BoundedPool[T]) Get() (indirect int, err error) {
 // would block until the item can be put into the pool or return
 // iox.ErrWouldBlock if the pool is nonblocking.
 //
-// In blocking mode, Put uses adaptive waiting (iox.Backoff) when the
-// pool is full. This acknowledges that pool capacity is freed by external
-// consumers completing their I/O operations.
+// In blocking mode, Put waits with the pool's WaitStrategy when the pool
+// is full, by default adaptive waiting (iox.Backoff). This acknowledges
+// that pool capacity is freed by external consumers completing their I/O
+// operations.
+//
+// Put returns iox.ErrWouldBlock only if the pool is full. Once it has
+// reserved room, it completes even in nonblocking mode, which may mean
+// waiting for a preempted Get to empty the slot; see BoundedPool.
 func (pool *BoundedPool[T]) Put(indirect int) error {
 	if len(pool.items) != int(pool.capacity) {
 		panic("must Fill the pool before using it")
 	}
+	if pool.debug == 0 {
+		return pool.put(indirect)
+	}
+	if pool.putWouldBlock() {
+		return iox.ErrWouldBlock
+	}
+	pool.onPut(indirect)
+	err := pool.put(indirect)
+	if err == nil {
+		pool.onPutDone(indirect)
+	}
+	return err
+}
+
+// put is Put without the debug actions.
+func (pool *BoundedPool[T]) put(indirect int) error {
 	entry := uint64(indirect)
+	if pool.elim != nil && pool.count.Load() <= 0 && pool.eliminatePut(entry) {
+		return nil
+	}
 	var aw iox.Backoff
-	for {
+	var wait poolWait
+	for attempt := 1; ; attempt++ {
 		err := pool.tryPut(entry)
 		if err == nil {
+			if wait.waiting() {
+				pool.endWait(PoolPut, &wait)
+			}
 			return nil
 		}
 		// tryPut only returns ErrWouldBlock on full pool
 		if pool.nonblocking {
+			if pool.debug&poolDebugStats != 0 {
+				pool.stats.wouldBlocks.Add(1)
+			}
 			return err
 		}
+		if !wait.waiting() && pool.debug&poolDebugWait != 0 {
+			wait = pool.beginWait(PoolPut)
+		} else if pool.debug&poolDebugWatch != 0 {
+			pool.watchWait(PoolPut, &wait)
+		}
+		if pool.debug&poolDebugStats != 0 {
+			pool.stats.backoffs.Add(1)
+		}
 		// Pool full: external consumer scale event.
-		// Use adaptive waiting to yield CPU while waiting for
-		// consumers to complete their operations.
-		aw.Wait()
+		// Wait for consumers to complete their operations.
+		pool.wait(PoolPut, &aw, attempt)
 	}
 }
 
@@ -334,8 +525,107 @@ func (pool *BoundedPool[T]) Cap() int {
 	return int(pool.capacity)
 }
 
-// Internal constants for the lock-free FIFO algorithm.
-// Entry format: [turn:30][reserved:2][empty:1][index:31]
+// BoundedPoolConfig describes the static configuration of a BoundedPool.
+//
+// It is intended for admin endpoints and debug tools that render the pool
+// topology of a running process.
+type BoundedPoolConfig struct {
+	Capacity       int        // Actual capacity after power-of-two rounding
+	ItemSize       int        // Size in bytes of one item's buffer memory
+	Tier           BufferTier // Built-in or registered tier matching ItemSize, or TierEnd if none
+	Filled         bool       // Whether Fill has been called
+	Nonblocking    bool       // Whether Get/Put return iox.ErrWouldBlock instead of blocking
+	SingleProducer bool       // Whether Put has one caller, see SetSingleProducer
+	SingleConsumer bool       // Whether Get has one caller, see SetSingleConsumer
+	RemapM         int        // Entries per cache line in the remapped entry array
+	RemapN         int        // Number of cache-line groups in the remapped entry array
+}
+
+// String returns a one-line human-readable description of the configuration.
+func (c BoundedPoolConfig) String() string {
+	tier := "none"
+	if c.Tier != TierEnd {
+		tier = c.Tier.String()
+	}
+	return fmt.Sprintf("capacity=%d item_size=%d tier=%s filled=%t nonblocking=%t remap=%dx%d",
+		c.Capacity, c.ItemSize, tier, c.Filled, c.Nonblocking, c.RemapN, c.RemapM)
+}
+
+// Config returns the configuration of the pool.
+//
+// For []byte items of a filled pool, ItemSize reports the length of the
+// first item's slice; otherwise it is the in-memory size of the item type.
+func (pool *BoundedPool[T]) Config() BoundedPoolConfig {
+	filled := len(pool.items) == int(pool.capacity)
+	var zero T
+	size := int(unsafe.Sizeof(zero))
+	if filled {
+		size = len(pool.itemBytes(0))
+	}
+	tier := TierFor(size)
+	if tier.Size() != size {
+		tier = TierEnd
+	}
+	return BoundedPoolConfig{
+		Capacity:       int(pool.capacity),
+		ItemSize:       size,
+		Tier:           tier,
+		Filled:         filled,
+		Nonblocking:    pool.nonblocking,
+		SingleProducer: pool.singleProducer,
+		SingleConsumer: pool.singleConsumer,
+		RemapM:         int(pool.remapM),
+		RemapN:         int(pool.remapN),
+	}
+}
+
+// Warmup walks every pooled item, touching each memory page so it is
+// faulted in, and then calls fn (if non-nil) with the item's indirect
+// index and a byte view of its memory.
+//
+// Freshly allocated pool memory is typically backed by untouched pages;
+// the first write to each page costs a page fault, which shows up as a
+// latency spike right after startup. Call Warmup after Fill and before the
+// pool serves traffic. Warmup preserves item contents.
+//
+// For []byte items the view is the referenced slice; for other item types
+// it is the raw memory of the item itself.
+//
+// Warmup is not safe for concurrent use with Get, Put or SetValue.
+func (pool *BoundedPool[T]) Warmup(fn func(indirect int, buf []byte)) {
+	if len(pool.items) != int(pool.capacity) {
+		panic("must Fill the pool before using it")
+	}
+	for i := range pool.items {
+		buf := pool.itemBytes(i)
+		for off := 0; off < len(buf); off += int(PageSize) {
+			v := buf[off]
+			buf[off] = v
+		}
+		if fn != nil {
+			fn(i, buf)
+		}
+	}
+}
+
+// itemBytes returns a byte view of the item at index i.
+// A []byte item yields the slice it references.
+func (pool *BoundedPool[T]) itemBytes(i int) []byte {
+	p := &pool.items[i]
+	if b, ok := any(p).(*[]byte); ok {
+		return *b
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(p)), unsafe.Sizeof(*p))
+}
+
+// Internal constants for the FIFO ring protocol.
+//
+// Every slot alternates between full and empty, stamped with the turn
+// (cursor / capacity) of the position it serves, so an operation can tell
+// its own turn from a previous one still in flight:
+//
+//	full:  [0:2][turn:30][index:32]
+//	empty: [0:1][1:1][reserved:32][turn:30]
 const (
 	boundedPoolEntryEmpty    = 1 << 62                       // Marks slot as empty
 	boundedPoolEntryTurnMask = boundedPoolEntryEmpty>>32 - 1 // Mask for turn counter
@@ -344,58 +634,93 @@ const (
 // tryGet attempts a single non-blocking dequeue from the pool.
 // Returns the entry value and nil on success, or boundedPoolEntryEmpty
 // and ErrWouldBlock if the pool is empty.
+//
+// Get reserves one of the counted items first, so it only claims a
+// position with fetch-and-add once a Put is bound to store there.
+// Claiming never fails, unlike a compare-and-swap on the cursor, so
+// concurrent callers do not retry against each other. The only wait left
+// is for that Put if it has claimed the position but not yet stored its
+// entry; it is spun out with spin.Wait, which escalates to yielding the
+// processor if the Put was preempted. Under contention a Get may
+// transiently see the pool empty while another caller's reservation is
+// being rolled back.
 func (pool *BoundedPool[T]) tryGet() (entry uint64, err error) {
+	if pool.singleConsumer {
+		return pool.tryGetSingle()
+	}
+	if pool.count.Load() <= 0 {
+		return boundedPoolEntryEmpty, iox.ErrWouldBlock
+	}
+	n := pool.count.Add(-1)
+	if n < 0 {
+		// Rolling back may complete an empty to non-empty transition a
+		// concurrent Put made while this reservation was taken.
+		if pool.count.Add(1) == 1 && pool.debug&poolSignal != 0 {
+			pool.signal(PoolGet)
+		}
+		return boundedPoolEntryEmpty, iox.ErrWouldBlock
+	}
+	h := pool.head.Add(1) - 1
+	slot := &pool.entries[pool.remap(h&pool.mask)]
+	turn := (h / pool.capacity) & boundedPoolEntryTurnMask
 	sw := spin.Wait{}
+	retries := 0
 	for {
-		h, t := pool.head.Load(), pool.tail.Load()
-		hi := pool.remap(h & pool.mask)
-		e := pool.entries[hi].Load()
-
-		if h != pool.head.Load() {
-			sw.Once()
-			continue
-		}
-
-		if h == t {
-			return boundedPoolEntryEmpty, iox.ErrWouldBlock
-		}
-
-		nextTurn := (h/pool.capacity + 1) & boundedPoolEntryTurnMask
-		if e == pool.empty(nextTurn) {
-			pool.head.CompareAndSwap(h, h+1)
-			sw.Once()
-			continue
-		}
-		ok := pool.entries[hi].CompareAndSwap(e, pool.empty(nextTurn))
-		pool.head.CompareAndSwap(h, h+1)
-		if ok {
+		e := slot.Load()
+		if e&boundedPoolEntryEmpty == 0 && uint32(e>>32) == turn {
+			// The next turn is computed from the wrapped cursor, as the
+			// Put claiming this slot next will.
+			slot.Store(pool.empty((h + pool.capacity) / pool.capacity))
+			if retries != 0 && pool.debug&poolDebugStats != 0 {
+				pool.stats.getRetries.Add(uint64(retries))
+			}
+			if n == int64(pool.capacity)-1 && pool.debug&poolSignal != 0 {
+				pool.signal(PoolPut)
+			}
 			return e, nil
 		}
+		retries++
 		sw.Once()
 	}
 }
 
 // tryPut attempts a single non-blocking enqueue into the pool.
 // Returns nil on success, or ErrWouldBlock if the pool is full.
+//
+// Put mirrors Get: it reserves room below the capacity, claims a position
+// with fetch-and-add and waits only for a Get still taking the previous
+// entry out of the same slot.
 func (pool *BoundedPool[T]) tryPut(e uint64) error {
-	sw := spin.Wait{}
-	for {
-		h, t := pool.head.Load(), pool.tail.Load()
-		if t != pool.tail.Load() {
-			sw.Once()
-			continue
-		}
-		if t == h+pool.capacity {
-			return iox.ErrWouldBlock
-		}
-		turn, ti := (t/pool.capacity)&boundedPoolEntryTurnMask, pool.remap(t)
-		ok := pool.entries[ti].CompareAndSwap(pool.empty(turn), e)
-		pool.tail.CompareAndSwap(t, t+1)
-		if ok {
-			return nil
+	if pool.singleProducer {
+		return pool.tryPutSingle(e)
+	}
+	if pool.count.Load() >= int64(pool.capacity) {
+		return iox.ErrWouldBlock
+	}
+	n := pool.count.Add(1)
+	if n > int64(pool.capacity) {
+		if pool.count.Add(-1) == int64(pool.capacity)-1 && pool.debug&poolSignal != 0 {
+			pool.signal(PoolPut)
 		}
+		return iox.ErrWouldBlock
+	}
+	t := pool.tail.Add(1) - 1
+	slot := &pool.entries[pool.remap(t&pool.mask)]
+	turn := (t / pool.capacity) & boundedPoolEntryTurnMask
+	sw := spin.Wait{}
+	retries := 0
+	for slot.Load() != pool.empty(turn) {
+		retries++
 		sw.Once()
 	}
+	slot.Store(uint64(turn)<<32 | e)
+	if retries != 0 && pool.debug&poolDebugStats != 0 {
+		pool.stats.putRetries.Add(uint64(retries))
+	}
+	if n == 1 && pool.debug&poolSignal != 0 {
+		pool.signal(PoolGet)
+	}
+	return nil
 }
 
 // remap converts a logical cursor position to a physical array index.
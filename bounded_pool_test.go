@@ -557,3 +557,160 @@ func TestNewTierBufferPools(t *testing.T) {
 		}
 	})
 }
+
+func TestBoundedPool_Warmup(t *testing.T) {
+	const capacity = 8
+	pool := iobuf.NewSmallBufferPool(capacity)
+	pool.Fill(func() iobuf.SmallBuffer {
+		var b iobuf.SmallBuffer
+		b[0] = 0x5A
+		return b
+	})
+
+	visited := make([]bool, capacity)
+	pool.Warmup(func(indirect int, buf []byte) {
+		if len(buf) != iobuf.BufferSizeSmall {
+			t.Errorf("Warmup buf len = %d, want %d", len(buf), iobuf.BufferSizeSmall)
+		}
+		if buf[0] != 0x5A {
+			t.Errorf("Warmup must preserve contents, got %#x", buf[0])
+		}
+		visited[indirect] = true
+	})
+	for i, ok := range visited {
+		if !ok {
+			t.Errorf("Warmup did not visit index %d", i)
+		}
+	}
+
+	// Nil callback only touches pages.
+	pool.Warmup(nil)
+}
+
+func TestBoundedPool_WarmupByteSlices(t *testing.T) {
+	pool := iobuf.NewBoundedPool[[]byte](4)
+	pool.Fill(func() []byte { return make([]byte, 100) })
+
+	pool.Warmup(func(indirect int, buf []byte) {
+		if len(buf) != 100 {
+			t.Errorf("Warmup buf len = %d, want 100", len(buf))
+		}
+	})
+}
+
+func TestBoundedPool_WarmupBeforeFill(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Warmup before Fill should panic")
+		}
+	}()
+	iobuf.NewBoundedPool[int](4).Warmup(nil)
+}
+
+func TestBoundedPool_Config(t *testing.T) {
+	pool := iobuf.NewSmallBufferPool(100)
+	cfg := pool.Config()
+	if cfg.Capacity != 128 {
+		t.Errorf("Capacity = %d, want 128", cfg.Capacity)
+	}
+	if cfg.ItemSize != iobuf.BufferSizeSmall {
+		t.Errorf("ItemSize = %d, want %d", cfg.ItemSize, iobuf.BufferSizeSmall)
+	}
+	if cfg.Tier != iobuf.TierSmall {
+		t.Errorf("Tier = %v, want TierSmall", cfg.Tier)
+	}
+	if cfg.Filled || cfg.Nonblocking {
+		t.Errorf("Filled/Nonblocking = %t/%t, want false/false", cfg.Filled, cfg.Nonblocking)
+	}
+	if cfg.RemapM*cfg.RemapN != cfg.Capacity {
+		t.Errorf("remap layout %dx%d does not cover capacity %d", cfg.RemapN, cfg.RemapM, cfg.Capacity)
+	}
+
+	pool.Fill(iobuf.NewSmallBuffer)
+	pool.SetNonblock(true)
+	cfg = pool.Config()
+	if !cfg.Filled || !cfg.Nonblocking {
+		t.Errorf("Filled/Nonblocking = %t/%t, want true/true", cfg.Filled, cfg.Nonblocking)
+	}
+	want := "capacity=128 item_size=2048 tier=Small filled=true nonblocking=true remap=16x8"
+	if got := cfg.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestBoundedPool_ConfigNonTier(t *testing.T) {
+	pool := iobuf.NewBoundedPool[[]byte](4)
+	pool.Fill(func() []byte { return make([]byte, 1500) })
+	cfg := pool.Config()
+	if cfg.ItemSize != 1500 {
+		t.Errorf("ItemSize = %d, want 1500", cfg.ItemSize)
+	}
+	if cfg.Tier != iobuf.TierEnd {
+		t.Errorf("Tier = %v, want TierEnd", cfg.Tier)
+	}
+}
+
+func TestNewBufferPoolFor(t *testing.T) {
+	tests := []struct {
+		size int
+		tier iobuf.BufferTier
+	}{
+		{1, iobuf.TierPico},
+		{100, iobuf.TierNano},
+		{1500, iobuf.TierSmall},
+		{iobuf.BufferSizeMedium, iobuf.TierMedium},
+		{9000, iobuf.TierBig},
+		{iobuf.BufferSizeLarge, iobuf.TierLarge},
+	}
+	for _, tt := range tests {
+		pool, tier := iobuf.NewBufferPoolFor(tt.size, 4)
+		if tier != tt.tier {
+			t.Errorf("NewBufferPoolFor(%d) tier = %v, want %v", tt.size, tier, tt.tier)
+			continue
+		}
+		if pool.Cap() != 4 {
+			t.Errorf("NewBufferPoolFor(%d) Cap() = %d, want 4", tt.size, pool.Cap())
+		}
+		idx, err := pool.Get()
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		buf := pool.Bytes(idx)
+		if len(buf) != tier.Size() || len(buf) < tt.size {
+			t.Errorf("NewBufferPoolFor(%d) buffer len = %d, want %d", tt.size, len(buf), tier.Size())
+		}
+		if err := pool.Put(idx); err != nil {
+			t.Fatalf("Put() failed: %v", err)
+		}
+	}
+}
+
+func TestBoundedPool_BytesAliasesPoolMemory(t *testing.T) {
+	pool := iobuf.NewMicroBufferPool(2)
+	pool.Fill(iobuf.NewMicroBuffer)
+
+	idx, err := pool.Get()
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	pool.Bytes(idx)[7] = 0xEE
+	if v := pool.Value(idx); v[7] != 0xEE {
+		t.Errorf("write through Bytes not visible via Value: got %#x", v[7])
+	}
+
+	var bp iobuf.BufferPool = pool
+	if len(bp.Bytes(idx)) != iobuf.BufferSizeMicro {
+		t.Errorf("BufferPool.Bytes len = %d, want %d", len(bp.Bytes(idx)), iobuf.BufferSizeMicro)
+	}
+
+	for _, bad := range []int{-1, 2} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Bytes(%d) should panic", bad)
+				}
+			}()
+			pool.Bytes(bad)
+		}()
+	}
+}
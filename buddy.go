@@ -0,0 +1,180 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"math/bits"
+	"sync"
+	"unsafe"
+)
+
+// BuddyAllocator satisfies power-of-two sized requests from one large
+// reserved region by splitting and merging buddy blocks.
+//
+// A request is rounded up to the next power-of-two multiple of the minimum
+// block size. Larger free blocks are split in halves until one of the right
+// size remains, and a freed block is merged with its buddy whenever the
+// buddy is free too. All buffers share one mapping, so the memory footprint
+// is a single region instead of one pool per size. The region is mapped
+// outside the Go heap where the platform allows and must be released with
+// Close.
+//
+// A BuddyAllocator is safe for concurrent use.
+type BuddyAllocator struct {
+	mu       sync.Mutex
+	mem      []byte
+	minShift uint
+	maxOrder int
+
+	heads []int32 // First free block per order, -1 if none
+	next  []int32 // Free list links per minimum block
+	prev  []int32
+	order []int8 // Order of the block starting at each minimum block
+	free  []bool // Whether a free block starts at each minimum block
+	inUse []bool // Whether an allocated block starts at each minimum block
+	used  int    // Allocated bytes, in whole blocks
+}
+
+// NewBuddyAllocator reserves a region of size bytes managed in blocks of at
+// least minBlock bytes. Both must be powers of two with size >= minBlock.
+// Panics if they are not.
+func NewBuddyAllocator(size int, minBlock int) (*BuddyAllocator, error) {
+	if minBlock < 1 || minBlock&(minBlock-1) != 0 || size < minBlock || size&(size-1) != 0 {
+		panic("iobuf.NewBuddyAllocator: sizes must be powers of two with size >= minBlock")
+	}
+	if size/minBlock > 1<<31 {
+		panic("iobuf.NewBuddyAllocator: too many blocks")
+	}
+	mem, err := mapRegion(size, 0)
+	if err != nil {
+		return nil, err
+	}
+	units := size / minBlock
+	a := &BuddyAllocator{
+		mem:      mem,
+		minShift: uint(bits.TrailingZeros(uint(minBlock))),
+		maxOrder: bits.TrailingZeros(uint(units)),
+		next:     make([]int32, units),
+		prev:     make([]int32, units),
+		order:    make([]int8, units),
+		free:     make([]bool, units),
+		inUse:    make([]bool, units),
+	}
+	a.heads = make([]int32, a.maxOrder+1)
+	for i := range a.heads {
+		a.heads[i] = -1
+	}
+	a.push(0, a.maxOrder)
+	return a, nil
+}
+
+// Size returns the size of the managed region.
+func (a *BuddyAllocator) Size() int { return len(a.mem) }
+
+// Used returns the number of bytes currently allocated, counting whole
+// blocks.
+func (a *BuddyAllocator) Used() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.used
+}
+
+// Alloc returns a block of at least n bytes, sliced to length n with the
+// capacity of the whole block. Blocks are aligned to their size relative
+// to the start of the region. Returns nil if no block large enough is
+// free. Panics if n < 1.
+func (a *BuddyAllocator) Alloc(n int) []byte {
+	if n < 1 {
+		panic("iobuf.BuddyAllocator.Alloc: size must be positive")
+	}
+	units := (n + 1<<a.minShift - 1) >> a.minShift
+	want := bits.Len(uint(units - 1))
+	if want > a.maxOrder {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	k := want
+	for k <= a.maxOrder && a.heads[k] < 0 {
+		k++
+	}
+	if k > a.maxOrder {
+		return nil
+	}
+	idx := int(a.heads[k])
+	a.remove(idx, k)
+	for k > want {
+		k--
+		a.push(idx+1<<k, k)
+	}
+	a.order[idx] = int8(want)
+	a.inUse[idx] = true
+	size := 1 << (uint(want) + a.minShift)
+	a.used += size
+	off := idx << a.minShift
+	return a.mem[off : off+n : off+size]
+}
+
+// Free returns a block obtained from Alloc and merges it with its free
+// buddies. Panics if b was not allocated by a or was already freed.
+func (a *BuddyAllocator) Free(b []byte) {
+	off := uintptr(unsafe.Pointer(unsafe.SliceData(b))) - uintptr(unsafe.Pointer(unsafe.SliceData(a.mem)))
+	if off >= uintptr(len(a.mem)) || off&(1<<a.minShift-1) != 0 {
+		panic("iobuf.BuddyAllocator.Free: memory not allocated by this allocator")
+	}
+	idx := int(off >> a.minShift)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.inUse[idx] {
+		panic("iobuf.BuddyAllocator.Free: double free or not a block start")
+	}
+	a.inUse[idx] = false
+	k := int(a.order[idx])
+	a.used -= 1 << (uint(k) + a.minShift)
+	for k < a.maxOrder {
+		buddy := idx ^ 1<<k
+		if !a.free[buddy] || int(a.order[buddy]) != k {
+			break
+		}
+		a.remove(buddy, k)
+		idx = min(idx, buddy)
+		k++
+	}
+	a.push(idx, k)
+}
+
+// Close releases the region. Blocks must not be used afterwards.
+func (a *BuddyAllocator) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	mem := a.mem
+	a.mem = nil
+	return unmapRegion(mem)
+}
+
+// push adds the block at idx to the free list of order k.
+func (a *BuddyAllocator) push(idx int, k int) {
+	a.order[idx] = int8(k)
+	a.free[idx] = true
+	a.prev[idx] = -1
+	a.next[idx] = a.heads[k]
+	if a.heads[k] >= 0 {
+		a.prev[a.heads[k]] = int32(idx)
+	}
+	a.heads[k] = int32(idx)
+}
+
+// remove unlinks the block at idx from the free list of order k.
+func (a *BuddyAllocator) remove(idx int, k int) {
+	a.free[idx] = false
+	if p := a.prev[idx]; p >= 0 {
+		a.next[p] = a.next[idx]
+	} else {
+		a.heads[k] = a.next[idx]
+	}
+	if n := a.next[idx]; n >= 0 {
+		a.prev[n] = a.prev[idx]
+	}
+}
@@ -0,0 +1,122 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func newTestBuddy(t *testing.T, size, minBlock int) *iobuf.BuddyAllocator {
+	t.Helper()
+	a, err := iobuf.NewBuddyAllocator(size, minBlock)
+	if err != nil {
+		t.Fatalf("NewBuddyAllocator() failed: %v", err)
+	}
+	t.Cleanup(func() { _ = a.Close() })
+	return a
+}
+
+func TestBuddyAllocator_SplitAndMerge(t *testing.T) {
+	a := newTestBuddy(t, 1<<16, 1<<10)
+	base := a.Alloc(1)
+	if len(base) != 1 || cap(base) != 1<<10 {
+		t.Fatalf("Alloc(1) len=%d cap=%d, want a 1 KiB block", len(base), cap(base))
+	}
+	b := a.Alloc(3000) // rounds to 4 KiB
+	if cap(b) != 4096 {
+		t.Errorf("Alloc(3000) cap = %d, want 4096", cap(b))
+	}
+	off := uintptr(unsafe.Pointer(unsafe.SliceData(b))) - uintptr(unsafe.Pointer(unsafe.SliceData(base)))
+	if off%4096 != 0 {
+		t.Errorf("4 KiB block at offset %d, want block-size aligned", off)
+	}
+	if a.Used() != 1<<10+4096 {
+		t.Errorf("Used() = %d", a.Used())
+	}
+	if a.Alloc(1<<16) != nil {
+		t.Error("whole-region allocation should fail while blocks are held")
+	}
+
+	a.Free(base)
+	a.Free(b)
+	if a.Used() != 0 {
+		t.Errorf("Used() after Free = %d, want 0", a.Used())
+	}
+	whole := a.Alloc(1 << 16)
+	if whole == nil {
+		t.Fatal("buddies were not merged back into the whole region")
+	}
+	a.Free(whole)
+}
+
+func TestBuddyAllocator_Exhaust(t *testing.T) {
+	a := newTestBuddy(t, 1<<14, 1<<10)
+	var blocks [][]byte
+	for {
+		b := a.Alloc(1 << 10)
+		if b == nil {
+			break
+		}
+		blocks = append(blocks, b)
+	}
+	if len(blocks) != 16 {
+		t.Fatalf("allocated %d minimum blocks, want 16", len(blocks))
+	}
+	for _, i := range rand.Perm(len(blocks)) {
+		a.Free(blocks[i])
+	}
+	if a.Alloc(1<<14) == nil {
+		t.Error("region not fully merged after freeing in random order")
+	}
+}
+
+func TestBuddyAllocator_Panics(t *testing.T) {
+	a := newTestBuddy(t, 1<<14, 1<<10)
+	b := a.Alloc(1 << 11)
+	a.Free(b)
+	for name, f := range map[string]func(){
+		"double free": func() { a.Free(b) },
+		"foreign":     func() { a.Free(make([]byte, 1)) },
+		"bad sizes":   func() { _, _ = iobuf.NewBuddyAllocator(3000, 1024) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s should panic", name)
+				}
+			}()
+			f()
+		})
+	}
+}
+
+func TestBuddyAllocator_Concurrent(t *testing.T) {
+	a := newTestBuddy(t, 1<<20, 1<<8)
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Go(func() {
+			for i := range 300 {
+				b := a.Alloc(1 << (8 + (g+i)%6))
+				if b == nil {
+					continue
+				}
+				b[0] = byte(g)
+				if b[0] != byte(g) {
+					t.Error("block shared between goroutines")
+				}
+				a.Free(b)
+			}
+		})
+	}
+	wg.Wait()
+	if a.Used() != 0 {
+		t.Errorf("Used() = %d after all frees", a.Used())
+	}
+}
@@ -0,0 +1,200 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "io"
+
+// Buf is a length-tracking cursor pair over a pooled buffer.
+//
+// Tier buffers are fixed-size arrays, so every consumer otherwise has to
+// carry its own (buf, n) pair. Buf keeps a write offset marking the end of
+// valid data and a read offset marking how much of it has been consumed:
+//
+//	+-----------+------------------+------------------+
+//	| consumed  | unread data      | available space  |
+//	+-----------+------------------+------------------+
+//	0           r                  w                  len
+//
+// Buf implements io.Reader, io.Writer and io.Closer, so pooled buffers can
+// flow into standard library code (json.Decoder, io.Copy) without copying.
+// Close returns the buffer to its pool. A Buf must not be used from
+// multiple goroutines concurrently, and copies of a Buf must not be closed
+// more than once in total.
+type Buf struct {
+	pool     BufferPool
+	indirect int
+	buf      []byte
+	r, w     int
+	wipe     bool
+}
+
+// NewBuf returns an empty Buf over the buffer at the given indirect index
+// of pool. The caller must have acquired the index via Get; ownership of
+// the index passes to the Buf, and Close returns it to the pool.
+func NewBuf(pool BufferPool, indirect int) Buf {
+	return Buf{pool: pool, indirect: indirect, buf: pool.Bytes(indirect)}
+}
+
+// AcquireBuf acquires a buffer from pool and returns an empty Buf over it.
+// Returns iox.ErrWouldBlock if the pool is non-blocking and empty.
+func AcquireBuf(pool BufferPool) (Buf, error) {
+	indirect, err := pool.Get()
+	if err != nil {
+		return Buf{}, err
+	}
+	return NewBuf(pool, indirect), nil
+}
+
+// SetWipe enables or disables scrubbing of the used prefix.
+//
+// With wipe enabled, Reset and Close zero the bytes written since the last
+// Reset before the buffer can be reused. Only the written prefix is
+// cleared, which keeps scrubbing affordable for buffers carrying secrets
+// (TLS keys, tokens) even in 64 KiB and larger tiers.
+func (b *Buf) SetWipe(wipe bool) { b.wipe = wipe }
+
+// Pool returns the pool the underlying buffer belongs to.
+func (b *Buf) Pool() BufferPool { return b.pool }
+
+// Indirect returns the pool index of the underlying buffer.
+func (b *Buf) Indirect() int { return b.indirect }
+
+// Cap returns the size of the underlying buffer.
+func (b *Buf) Cap() int { return len(b.buf) }
+
+// Remaining returns the number of written bytes not yet read.
+func (b *Buf) Remaining() int { return b.w - b.r }
+
+// Available returns the number of bytes that can still be written.
+func (b *Buf) Available() int { return len(b.buf) - b.w }
+
+// Bytes returns the unread portion of the buffer.
+// The slice aliases the buffer and is valid until the next modification.
+func (b *Buf) Bytes() []byte { return b.buf[b.r:b.w] }
+
+// Write appends p to the buffer.
+//
+// If p does not fit, Write copies as much as fits and returns
+// io.ErrShortWrite along with the number of bytes written.
+func (b *Buf) Write(p []byte) (n int, err error) {
+	n = copy(b.buf[b.w:], p)
+	b.w += n
+	if n < len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+// Read reads up to len(p) unread bytes into p.
+// It returns io.EOF when no unread data remains and len(p) > 0.
+func (b *Buf) Read(p []byte) (n int, err error) {
+	if b.r == b.w {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	n = copy(p, b.buf[b.r:b.w])
+	b.r += n
+	return n, nil
+}
+
+// ReadFrom reads data from r into the available space until r returns
+// io.EOF or another error, or the buffer is full. It implements
+// io.ReaderFrom so io.Copy reads straight into pooled memory instead of
+// allocating an intermediate buffer.
+//
+// io.EOF is not reported as an error. Semantic errors such as
+// iox.ErrWouldBlock are returned as-is with the bytes read so far. A full
+// buffer is not an error either: r may or may not hold more data, and the
+// caller decides by checking Available, then draining and reading again.
+func (b *Buf) ReadFrom(r io.Reader) (n int64, err error) {
+	for b.w < len(b.buf) {
+		m, err := r.Read(b.buf[b.w:])
+		if m < 0 || m > len(b.buf)-b.w {
+			panic("iobuf: reader returned invalid count")
+		}
+		b.w += m
+		n += int64(m)
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// WriteTo writes the unread data to w until it is drained or an error
+// occurs. It implements io.WriterTo so io.Copy pushes pooled memory to the
+// destination directly.
+//
+// Semantic errors such as iox.ErrWouldBlock are returned as-is with the
+// bytes written so far; those bytes are consumed and the rest stays unread.
+func (b *Buf) WriteTo(w io.Writer) (n int64, err error) {
+	for b.r < b.w {
+		m, err := w.Write(b.buf[b.r:b.w])
+		if m < 0 || m > b.w-b.r {
+			panic("iobuf: writer returned invalid count")
+		}
+		b.r += m
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+		if m == 0 {
+			return n, io.ErrShortWrite
+		}
+	}
+	return n, nil
+}
+
+// ReadN consumes and returns up to n unread bytes.
+// The slice aliases the buffer and is valid until the next modification.
+func (b *Buf) ReadN(n int) []byte {
+	n = max(0, min(n, b.w-b.r))
+	p := b.buf[b.r : b.r+n]
+	b.r += n
+	return p
+}
+
+// Unread moves the read offset back by n bytes, making them unread again.
+// Unread never moves before the start of the buffer.
+func (b *Buf) Unread(n int) {
+	b.r = max(0, b.r-max(0, n))
+}
+
+// Reset discards all data, making the whole buffer available for writing.
+// The buffer contents are not cleared unless wipe is enabled.
+func (b *Buf) Reset() {
+	if b.wipe {
+		MemZero(b.buf[:b.w])
+	}
+	b.r, b.w = 0, 0
+}
+
+// Close returns the underlying buffer to its pool.
+//
+// After Close the Buf is empty and must not be used. Closing a closed Buf
+// is a no-op. If the pool is non-blocking and full, Close returns
+// iox.ErrWouldBlock and the Buf remains open with its offsets unchanged.
+//
+// With wipe enabled, the used prefix is zeroed first: once the buffer is
+// back in the pool another goroutine may own it, so it cannot be wiped
+// afterwards. A Close that fails therefore leaves the data zeroed.
+func (b *Buf) Close() error {
+	if b.pool == nil {
+		return nil
+	}
+	if b.wipe {
+		MemZero(b.buf[:b.w])
+	}
+	if err := b.pool.Put(b.indirect); err != nil {
+		return err
+	}
+	*b = Buf{}
+	return nil
+}
@@ -0,0 +1,310 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+	"code.hybscloud.com/iox"
+)
+
+func newTestBuf(t *testing.T) (*iobuf.PicoBufferBoundedPool, iobuf.Buf) {
+	t.Helper()
+	pool := iobuf.NewPicoBufferPool(2)
+	pool.Fill(iobuf.NewPicoBuffer)
+	idx, err := pool.Get()
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	return pool, iobuf.NewBuf(pool, idx)
+}
+
+func TestBuf_WriteRead(t *testing.T) {
+	pool, b := newTestBuf(t)
+
+	if b.Cap() != iobuf.BufferSizePico || b.Available() != iobuf.BufferSizePico || b.Remaining() != 0 {
+		t.Fatalf("new Buf: Cap=%d Available=%d Remaining=%d", b.Cap(), b.Available(), b.Remaining())
+	}
+	if b.Pool() != iobuf.BufferPool(pool) {
+		t.Error("Pool() mismatch")
+	}
+
+	n, err := b.Write([]byte("hello, "))
+	if n != 7 || err != nil {
+		t.Fatalf("Write() = %d, %v", n, err)
+	}
+	_, _ = b.Write([]byte("world"))
+	if b.Remaining() != 12 || b.Available() != iobuf.BufferSizePico-12 {
+		t.Fatalf("Remaining=%d Available=%d", b.Remaining(), b.Available())
+	}
+
+	if got := b.ReadN(5); string(got) != "hello" {
+		t.Errorf("ReadN(5) = %q, want %q", got, "hello")
+	}
+	if got := string(b.Bytes()); got != ", world" {
+		t.Errorf("Bytes() = %q, want %q", got, ", world")
+	}
+
+	b.Unread(3)
+	if got := b.ReadN(100); string(got) != "llo, world" {
+		t.Errorf("ReadN after Unread = %q, want %q", got, "llo, world")
+	}
+	if got := b.ReadN(1); len(got) != 0 {
+		t.Errorf("ReadN on drained Buf = %q, want empty", got)
+	}
+
+	// Data is written into pool memory.
+	if !bytes.HasPrefix(pool.Bytes(b.Indirect()), []byte("hello, world")) {
+		t.Error("Buf does not write through to pool memory")
+	}
+
+	b.Reset()
+	if b.Remaining() != 0 || b.Available() != b.Cap() {
+		t.Errorf("after Reset: Remaining=%d Available=%d", b.Remaining(), b.Available())
+	}
+}
+
+func TestBuf_ShortWrite(t *testing.T) {
+	_, b := newTestBuf(t)
+
+	p := bytes.Repeat([]byte{'x'}, iobuf.BufferSizePico+10)
+	n, err := b.Write(p)
+	if n != iobuf.BufferSizePico || err != io.ErrShortWrite {
+		t.Fatalf("Write() = %d, %v; want %d, ErrShortWrite", n, err, iobuf.BufferSizePico)
+	}
+	if b.Available() != 0 {
+		t.Errorf("Available() = %d, want 0", b.Available())
+	}
+}
+
+func TestBuf_UnreadClamp(t *testing.T) {
+	_, b := newTestBuf(t)
+	_, _ = b.Write([]byte("abc"))
+	b.ReadN(2)
+	b.Unread(10)
+	if got := string(b.Bytes()); got != "abc" {
+		t.Errorf("Bytes() after over-Unread = %q, want %q", got, "abc")
+	}
+	b.Unread(-1)
+	b.ReadN(-1)
+	if b.Remaining() != 3 {
+		t.Errorf("negative counts must be no-ops, Remaining() = %d", b.Remaining())
+	}
+}
+
+func TestBuf_ReaderWriterAdapters(t *testing.T) {
+	pool := iobuf.NewSmallBufferPool(1)
+	pool.Fill(iobuf.NewSmallBuffer)
+	pool.SetNonblock(true)
+
+	b, err := iobuf.AcquireBuf(pool)
+	if err != nil {
+		t.Fatalf("AcquireBuf() failed: %v", err)
+	}
+	if _, err := iobuf.AcquireBuf(pool); err == nil {
+		t.Fatal("AcquireBuf() on exhausted pool should fail")
+	}
+
+	var w io.WriteCloser = &b
+	if _, err := io.WriteString(w, `{"k":"v"}`); err != nil {
+		t.Fatalf("WriteString() failed: %v", err)
+	}
+	var r io.Reader = &b
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() failed: %v", err)
+	}
+	if string(got) != `{"k":"v"}` {
+		t.Errorf("ReadAll() = %q", got)
+	}
+	if n, err := b.Read(nil); n != 0 || err != nil {
+		t.Errorf("Read(nil) = %d, %v; want 0, nil", n, err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("second Close() failed: %v", err)
+	}
+	idx, err := pool.Get()
+	if err != nil {
+		t.Fatalf("buffer not returned to pool on Close: %v", err)
+	}
+	_ = pool.Put(idx)
+}
+
+func TestBuf_ReadFromWriteTo(t *testing.T) {
+	pool := iobuf.NewMicroBufferPool(1)
+	pool.Fill(iobuf.NewMicroBuffer)
+	b, err := iobuf.AcquireBuf(pool)
+	if err != nil {
+		t.Fatalf("AcquireBuf() failed: %v", err)
+	}
+	defer b.Close()
+
+	src := bytes.Repeat([]byte("0123456789"), 30)
+	n, err := b.ReadFrom(bytes.NewReader(src))
+	if n != int64(len(src)) || err != nil {
+		t.Fatalf("ReadFrom() = %d, %v", n, err)
+	}
+
+	var dst bytes.Buffer
+	n, err = b.WriteTo(&dst)
+	if n != int64(len(src)) || err != nil {
+		t.Fatalf("WriteTo() = %d, %v", n, err)
+	}
+	if !bytes.Equal(dst.Bytes(), src) {
+		t.Error("WriteTo() output mismatch")
+	}
+	if b.Remaining() != 0 {
+		t.Errorf("Remaining() after WriteTo = %d, want 0", b.Remaining())
+	}
+}
+
+func TestBuf_ReadFromFull(t *testing.T) {
+	_, b := newTestBuf(t)
+	src := bytes.Repeat([]byte{'z'}, iobuf.BufferSizePico*2)
+	r := bytes.NewReader(src)
+	n, err := b.ReadFrom(r)
+	if n != iobuf.BufferSizePico || err != nil {
+		t.Fatalf("ReadFrom() = %d, %v; want %d, nil", n, err, iobuf.BufferSizePico)
+	}
+	if b.Available() != 0 {
+		t.Errorf("Available() = %d, want 0", b.Available())
+	}
+	if r.Len() != iobuf.BufferSizePico {
+		t.Errorf("reader has %d bytes left, want %d", r.Len(), iobuf.BufferSizePico)
+	}
+}
+
+func TestBuf_ReadFromExactFit(t *testing.T) {
+	_, b := newTestBuf(t)
+	src := bytes.Repeat([]byte{'z'}, iobuf.BufferSizePico)
+	n, err := io.Copy(&b, bytes.NewReader(src))
+	if n != iobuf.BufferSizePico || err != nil {
+		t.Fatalf("io.Copy() = %d, %v; want %d, nil", n, err, iobuf.BufferSizePico)
+	}
+	if !bytes.Equal(b.Bytes(), src) {
+		t.Error("Bytes() mismatch after exact-fit copy")
+	}
+}
+
+func TestBuf_ReadFromSemanticError(t *testing.T) {
+	_, b := newTestBuf(t)
+	r := io.MultiReader(bytes.NewReader([]byte("abc")), wouldBlockReader{})
+	n, err := b.ReadFrom(r)
+	if n != 3 || err != iox.ErrWouldBlock {
+		t.Fatalf("ReadFrom() = %d, %v; want 3, ErrWouldBlock", n, err)
+	}
+	if string(b.Bytes()) != "abc" {
+		t.Errorf("Bytes() = %q, want %q", b.Bytes(), "abc")
+	}
+}
+
+func TestBuf_WriteToShortWrite(t *testing.T) {
+	_, b := newTestBuf(t)
+	_, _ = b.Write([]byte("abcdef"))
+	n, err := b.WriteTo(&limitWriter{n: 4})
+	if n != 4 || err != iox.ErrWouldBlock {
+		t.Fatalf("WriteTo() = %d, %v; want 4, ErrWouldBlock", n, err)
+	}
+	if string(b.Bytes()) != "ef" {
+		t.Errorf("unwritten data = %q, want %q", b.Bytes(), "ef")
+	}
+}
+
+func TestBuf_IoCopy(t *testing.T) {
+	pool := iobuf.NewSmallBufferPool(1)
+	pool.Fill(iobuf.NewSmallBuffer)
+	b, _ := iobuf.AcquireBuf(pool)
+	defer b.Close()
+
+	if _, err := io.Copy(&b, strings.NewReader("copied via ReaderFrom")); err != nil {
+		t.Fatalf("io.Copy into Buf failed: %v", err)
+	}
+	var sb strings.Builder
+	if _, err := io.Copy(&sb, &b); err != nil {
+		t.Fatalf("io.Copy from Buf failed: %v", err)
+	}
+	if sb.String() != "copied via ReaderFrom" {
+		t.Errorf("round trip = %q", sb.String())
+	}
+}
+
+type wouldBlockReader struct{}
+
+func (wouldBlockReader) Read([]byte) (int, error) { return 0, iox.ErrWouldBlock }
+
+type limitWriter struct{ n int }
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	if len(p) <= w.n {
+		w.n -= len(p)
+		return len(p), nil
+	}
+	n := w.n
+	w.n = 0
+	return n, iox.ErrWouldBlock
+}
+
+func TestBuf_Wipe(t *testing.T) {
+	pool := iobuf.NewSmallBufferPool(1)
+	pool.Fill(iobuf.NewSmallBuffer)
+
+	b, _ := iobuf.AcquireBuf(pool)
+	idx := b.Indirect()
+	mem := pool.Bytes(idx)
+	mem[100] = 0x77 // beyond the used prefix
+
+	b.SetWipe(true)
+	_, _ = b.Write([]byte("secret-key"))
+	b.Reset()
+	if !bytes.Equal(mem[:10], make([]byte, 10)) {
+		t.Errorf("Reset with wipe left %q", mem[:10])
+	}
+
+	_, _ = b.Write([]byte("token"))
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if !bytes.Equal(mem[:5], make([]byte, 5)) {
+		t.Errorf("Close with wipe left %q", mem[:5])
+	}
+	if mem[100] != 0x77 {
+		t.Error("wipe must only clear the used prefix")
+	}
+}
+
+func TestBuf_CloseWouldBlockKeepsOffsets(t *testing.T) {
+	pool := iobuf.NewPicoBufferPool(1)
+	pool.Fill(iobuf.NewPicoBuffer)
+	pool.SetNonblock(true)
+
+	// The pool is full, so returning the index would block.
+	b := iobuf.NewBuf(pool, 0)
+	b.SetWipe(true)
+	_, _ = b.Write([]byte("abcdef"))
+	_ = b.ReadN(2)
+	if err := b.Close(); err != iox.ErrWouldBlock {
+		t.Fatalf("Close() = %v, want ErrWouldBlock", err)
+	}
+	if b.Pool() == nil || b.Remaining() != 4 || b.Available() != iobuf.BufferSizePico-6 {
+		t.Errorf("after failed Close: Remaining %d, Available %d", b.Remaining(), b.Available())
+	}
+}
+
+func TestBuf_NoWipeByDefault(t *testing.T) {
+	pool, b := newTestBuf(t)
+	_, _ = b.Write([]byte("kept"))
+	b.Reset()
+	if string(pool.Bytes(b.Indirect())[:4]) != "kept" {
+		t.Error("Reset without wipe must not clear contents")
+	}
+}
@@ -0,0 +1,192 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"math"
+	"slices"
+	"unsafe"
+)
+
+// BufferGroup is an io_uring provided-buffer group fed from a tier pool.
+//
+// With provided buffers the kernel picks a buffer for each receive from a
+// group identified by its buffer group ID (bgid) and reports the buffer ID
+// (bid) in the upper 16 bits of the completion flags. The buffer ID of a
+// pooled buffer is its indirect index. Implementations differ only in how
+// buffers reach the kernel, so callers can switch between them freely.
+type BufferGroup interface {
+	// GroupID returns the buffer group ID.
+	GroupID() uint16
+
+	// Completed takes over the buffer the kernel reported with buffer ID
+	// bid for a completion of n bytes. It returns the pool indirect index,
+	// now held by the caller, and the received data. Put the index back
+	// into the pool when done; Replenish hands it to the kernel again.
+	Completed(bid uint16, n int) (indirect int, data []byte)
+
+	// Replenish gives every buffer available in the pool to the kernel
+	// and returns how many were added.
+	Replenish() (int, error)
+
+	// Close withdraws the group's buffers from the kernel and returns
+	// them to the pool.
+	Close() error
+}
+
+// NewBufferGroup returns the best BufferGroup the kernel supports for group
+// bgid of the io_uring instance ringFd, fed from pool: a BufRing if buffer
+// rings are available (see KernelFeatures), and a ProvidedBuffers using
+// submit otherwise. Call Replenish to provide the initial buffers.
+// Panics if the pool is not filled or has more than 65536 buffers.
+func NewBufferGroup[T BufferType](ringFd int, pool *BoundedPool[T], bgid uint16, submit func(op ProvideBuffersOp) error) (BufferGroup, error) {
+	if f, err := KernelFeatures(); err == nil && f.BufRing {
+		return NewBufRing(ringFd, pool, bgid, 0, false)
+	}
+	return NewProvidedBuffers(pool, bgid, submit), nil
+}
+
+// ProvideBuffersOp describes one IORING_OP_PROVIDE_BUFFERS submission:
+// Nbufs buffers of Len bytes each, laid out back to back from Addr, added
+// to group Bgid with IDs starting at Bid. With Remove set it describes an
+// IORING_OP_REMOVE_BUFFERS submission instead, which only uses Nbufs and
+// Bgid.
+type ProvideBuffersOp struct {
+	Addr   uintptr
+	Len    uint32
+	Nbufs  uint16
+	Bgid   uint16
+	Bid    uint16
+	Remove bool
+}
+
+// ProvidedBuffers is a BufferGroup for kernels without buffer rings
+// (before Linux 5.19), managed with IORING_OP_PROVIDE_BUFFERS and
+// IORING_OP_REMOVE_BUFFERS.
+//
+// The package does not own the ring: Replenish and Close describe the
+// operations and hand them to the submit function supplied by the caller,
+// which prepares the SQEs. Buffers with consecutive IDs are adjacent in a
+// tier pool, so Replenish batches each run of them into one operation.
+//
+// A ProvidedBuffers must not be used from multiple goroutines concurrently.
+type ProvidedBuffers[T BufferType] struct {
+	pool     *BoundedPool[T]
+	bgid     uint16
+	submit   func(op ProvideBuffersOp) error
+	inKernel []bool // Whether each buffer ID is with the kernel
+	provided int
+	ids      []int // Scratch for Replenish
+}
+
+var _ BufferGroup = (*ProvidedBuffers[SmallBuffer])(nil)
+
+// NewProvidedBuffers returns a buffer group bgid fed from pool, which must
+// be filled. submit is called for each operation to enqueue; it should
+// prepare the matching SQE. Call Replenish to provide the initial buffers.
+// Panics if the pool is not filled or has more than 65536 buffers.
+func NewProvidedBuffers[T BufferType](pool *BoundedPool[T], bgid uint16, submit func(op ProvideBuffersOp) error) *ProvidedBuffers[T] {
+	if len(pool.items) != int(pool.capacity) {
+		panic("must Fill the pool before using it")
+	}
+	if pool.capacity > math.MaxUint16+1 {
+		panic("iobuf.NewProvidedBuffers: too many buffers for 16-bit buffer IDs")
+	}
+	return &ProvidedBuffers[T]{
+		pool:     pool,
+		bgid:     bgid,
+		submit:   submit,
+		inKernel: make([]bool, pool.capacity),
+	}
+}
+
+// GroupID returns the buffer group ID.
+func (g *ProvidedBuffers[T]) GroupID() uint16 { return g.bgid }
+
+// Provided returns the number of buffers currently with the kernel.
+func (g *ProvidedBuffers[T]) Provided() int { return g.provided }
+
+// Completed takes over the buffer with ID bid from the kernel.
+// Panics if the buffer is not with the kernel or n exceeds its size.
+func (g *ProvidedBuffers[T]) Completed(bid uint16, n int) (indirect int, data []byte) {
+	indirect = int(bid)
+	if indirect >= len(g.inKernel) || !g.inKernel[indirect] {
+		panic("iobuf.ProvidedBuffers.Completed: buffer not provided")
+	}
+	g.inKernel[indirect] = false
+	g.provided--
+	return indirect, g.pool.itemBytes(indirect)[:n]
+}
+
+// Replenish takes every available buffer from the pool and provides it,
+// submitting one operation per run of consecutive buffer IDs. If submit
+// fails, the buffers not yet submitted go back to the pool.
+func (g *ProvidedBuffers[T]) Replenish() (int, error) {
+	ids := g.ids[:0]
+	for {
+		e, err := g.pool.tryGet()
+		if err != nil {
+			break
+		}
+		indirect := int(e & uint64(g.pool.mask))
+		if g.pool.debug != 0 {
+			g.pool.onGet(indirect)
+		}
+		ids = append(ids, indirect)
+	}
+	g.ids = ids
+	slices.Sort(ids)
+	size := unsafe.Sizeof(*new(T))
+	base := uintptr(unsafe.Pointer(unsafe.SliceData(g.pool.items)))
+	n := 0
+	for i := 0; i < len(ids); {
+		j := i + 1
+		for j < len(ids) && ids[j] == ids[j-1]+1 && j-i < math.MaxUint16 {
+			j++
+		}
+		op := ProvideBuffersOp{
+			Addr:  base + uintptr(ids[i])*size,
+			Len:   uint32(size),
+			Nbufs: uint16(j - i),
+			Bgid:  g.bgid,
+			Bid:   uint16(ids[i]),
+		}
+		if err := g.submit(op); err != nil {
+			for _, id := range ids[i:] {
+				_ = g.pool.put(id)
+			}
+			return n, err
+		}
+		for _, id := range ids[i:j] {
+			g.inKernel[id] = true
+		}
+		g.provided += j - i
+		n += j - i
+		i = j
+	}
+	return n, nil
+}
+
+// Close submits operations removing the provided buffers from the group
+// and returns the buffers to the pool. Call it when no operation selecting
+// from the group is in flight, so the kernel cannot pick a buffer that is
+// already back in the pool.
+func (g *ProvidedBuffers[T]) Close() error {
+	for rem := g.provided; rem > 0; {
+		k := min(rem, math.MaxUint16)
+		if err := g.submit(ProvideBuffersOp{Nbufs: uint16(k), Bgid: g.bgid, Remove: true}); err != nil {
+			return err
+		}
+		rem -= k
+	}
+	for id, ok := range g.inKernel {
+		if ok {
+			g.inKernel[id] = false
+			_ = g.pool.put(id)
+		}
+	}
+	g.provided = 0
+	return nil
+}
@@ -0,0 +1,97 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"errors"
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestProvidedBuffers_Replenish(t *testing.T) {
+	pool := iobuf.NewSmallBufferPool(8)
+	pool.Fill(iobuf.NewSmallBuffer)
+	var ops []iobuf.ProvideBuffersOp
+	g := iobuf.NewProvidedBuffers(pool, 7, func(op iobuf.ProvideBuffersOp) error {
+		ops = append(ops, op)
+		return nil
+	})
+
+	n, err := g.Replenish()
+	if err != nil || n != 8 || g.Provided() != 8 {
+		t.Fatalf("Replenish() = %d, %v; Provided() = %d", n, err, g.Provided())
+	}
+	base := uintptr(unsafe.Pointer(&pool.Bytes(0)[0]))
+	want := iobuf.ProvideBuffersOp{Addr: base, Len: iobuf.BufferSizeSmall, Nbufs: 8, Bgid: 7, Bid: 0}
+	if len(ops) != 1 || ops[0] != want {
+		t.Fatalf("ops = %+v, want one batch %+v", ops, want)
+	}
+
+	// The kernel consumes buffers 2, 3 and 6; the application returns them.
+	for _, bid := range []uint16{2, 6, 3} {
+		idx, data := g.Completed(bid, 10)
+		if idx != int(bid) || len(data) != 10 || &data[0] != &pool.Bytes(idx)[0] {
+			t.Errorf("Completed(%d) = %d, len %d", bid, idx, len(data))
+		}
+		_ = pool.Put(idx)
+	}
+	ops = nil
+	if n, err := g.Replenish(); err != nil || n != 3 {
+		t.Fatalf("Replenish() = %d, %v, want 3", n, err)
+	}
+	if len(ops) != 2 || ops[0].Bid != 2 || ops[0].Nbufs != 2 || ops[1].Bid != 6 || ops[1].Nbufs != 1 {
+		t.Errorf("ops = %+v, want runs {2,2} and {6,1}", ops)
+	}
+	if ops[1].Addr != base+6*iobuf.BufferSizeSmall {
+		t.Errorf("ops[1].Addr = %#x, want buffer 6", ops[1].Addr)
+	}
+
+	ops = nil
+	if err := g.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if len(ops) != 1 || !ops[0].Remove || ops[0].Nbufs != 8 || ops[0].Bgid != 7 {
+		t.Errorf("Close ops = %+v, want one removal of 8 buffers", ops)
+	}
+	if g.Provided() != 0 {
+		t.Errorf("Provided() after Close = %d", g.Provided())
+	}
+	pool.SetNonblock(true)
+	for range 8 {
+		if _, err := pool.Get(); err != nil {
+			t.Fatalf("buffer missing from pool after Close: %v", err)
+		}
+	}
+}
+
+func TestProvidedBuffers_SubmitError(t *testing.T) {
+	pool := iobuf.NewMicroBufferPool(4)
+	pool.Fill(iobuf.NewMicroBuffer)
+	errFull := errors.New("submission queue full")
+	g := iobuf.NewProvidedBuffers(pool, 1, func(iobuf.ProvideBuffersOp) error { return errFull })
+	if n, err := g.Replenish(); !errors.Is(err, errFull) || n != 0 || g.Provided() != 0 {
+		t.Fatalf("Replenish() = %d, %v", n, err)
+	}
+	pool.SetNonblock(true)
+	for range 4 {
+		if _, err := pool.Get(); err != nil {
+			t.Fatalf("buffer not returned to the pool after a failed submit: %v", err)
+		}
+	}
+}
+
+func TestProvidedBuffers_CompletedUnknownPanics(t *testing.T) {
+	pool := iobuf.NewMicroBufferPool(2)
+	pool.Fill(iobuf.NewMicroBuffer)
+	g := iobuf.NewProvidedBuffers(pool, 1, func(iobuf.ProvideBuffersOp) error { return nil })
+	defer func() {
+		if recover() == nil {
+			t.Error("Completed for a buffer not provided should panic")
+		}
+	}()
+	g.Completed(0, 1)
+}
@@ -0,0 +1,102 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "encoding/binary"
+
+// BufferView is a sub-range of a pooled buffer.
+//
+// Protocol parsers hand sub-ranges of one pooled buffer (a header, a
+// payload) to downstream stages. A BufferView carries the pool and index
+// alongside the range, so whichever stage finishes last can return the
+// buffer. Views are plain values: slicing one yields another view of the
+// same buffer, and exactly one of them must be released.
+type BufferView struct {
+	Pool     BufferPool // Pool owning the buffer
+	Indirect int        // Pool index of the buffer
+	Off      int        // Start of the view within the buffer
+	Len      int        // Length of the view
+}
+
+// ViewOf returns a view covering the whole buffer at the given indirect
+// index of pool. The caller must have acquired the index via Get.
+func ViewOf(pool BufferPool, indirect int) BufferView {
+	return BufferView{Pool: pool, Indirect: indirect, Len: len(pool.Bytes(indirect))}
+}
+
+// Bytes returns the bytes covered by the view. The slice aliases pool
+// memory and is capped at the end of the view.
+func (v BufferView) Bytes() []byte {
+	return v.Pool.Bytes(v.Indirect)[v.Off : v.Off+v.Len : v.Off+v.Len]
+}
+
+// Slice returns the view of bytes [i, j) relative to v.
+// Panics if 0 <= i <= j <= v.Len does not hold.
+func (v BufferView) Slice(i, j int) BufferView {
+	if i < 0 || j < i || j > v.Len {
+		panic("iobuf.BufferView.Slice: bounds out of range")
+	}
+	return BufferView{Pool: v.Pool, Indirect: v.Indirect, Off: v.Off + i, Len: j - i}
+}
+
+// Release returns the underlying buffer to its pool. It must be called
+// exactly once across all views of the same buffer.
+func (v BufferView) Release() error {
+	return v.Pool.Put(v.Indirect)
+}
+
+// Binary accessors read and write fixed-size integers in place at offset
+// off relative to the view, so protocol framing code can serialize headers
+// directly into pooled memory. The plain variants use network byte order
+// (big-endian); the Order variants take an explicit binary.ByteOrder.
+// All of them panic if the value does not fit within the view.
+
+// PutUint16 writes x at off in big-endian byte order.
+func (v BufferView) PutUint16(off int, x uint16) { binary.BigEndian.PutUint16(v.Bytes()[off:], x) }
+
+// PutUint32 writes x at off in big-endian byte order.
+func (v BufferView) PutUint32(off int, x uint32) { binary.BigEndian.PutUint32(v.Bytes()[off:], x) }
+
+// PutUint64 writes x at off in big-endian byte order.
+func (v BufferView) PutUint64(off int, x uint64) { binary.BigEndian.PutUint64(v.Bytes()[off:], x) }
+
+// Uint16 reads a big-endian uint16 at off.
+func (v BufferView) Uint16(off int) uint16 { return binary.BigEndian.Uint16(v.Bytes()[off:]) }
+
+// Uint32 reads a big-endian uint32 at off.
+func (v BufferView) Uint32(off int) uint32 { return binary.BigEndian.Uint32(v.Bytes()[off:]) }
+
+// Uint64 reads a big-endian uint64 at off.
+func (v BufferView) Uint64(off int) uint64 { return binary.BigEndian.Uint64(v.Bytes()[off:]) }
+
+// PutUint16Order writes x at off in the given byte order.
+func (v BufferView) PutUint16Order(order binary.ByteOrder, off int, x uint16) {
+	order.PutUint16(v.Bytes()[off:], x)
+}
+
+// PutUint32Order writes x at off in the given byte order.
+func (v BufferView) PutUint32Order(order binary.ByteOrder, off int, x uint32) {
+	order.PutUint32(v.Bytes()[off:], x)
+}
+
+// PutUint64Order writes x at off in the given byte order.
+func (v BufferView) PutUint64Order(order binary.ByteOrder, off int, x uint64) {
+	order.PutUint64(v.Bytes()[off:], x)
+}
+
+// Uint16Order reads a uint16 at off in the given byte order.
+func (v BufferView) Uint16Order(order binary.ByteOrder, off int) uint16 {
+	return order.Uint16(v.Bytes()[off:])
+}
+
+// Uint32Order reads a uint32 at off in the given byte order.
+func (v BufferView) Uint32Order(order binary.ByteOrder, off int) uint32 {
+	return order.Uint32(v.Bytes()[off:])
+}
+
+// Uint64Order reads a uint64 at off in the given byte order.
+func (v BufferView) Uint64Order(order binary.ByteOrder, off int) uint64 {
+	return order.Uint64(v.Bytes()[off:])
+}
@@ -0,0 +1,114 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestBufferView_SliceAndRelease(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(1)
+	pool.Fill(iobuf.NewNanoBuffer)
+	pool.SetNonblock(true)
+
+	idx, err := pool.Get()
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	copy(pool.Bytes(idx), "HDR:payload-bytes")
+
+	v := iobuf.ViewOf(pool, idx)
+	if v.Len != iobuf.BufferSizeNano || v.Off != 0 {
+		t.Fatalf("ViewOf = %+v", v)
+	}
+
+	hdr := v.Slice(0, 4)
+	payload := v.Slice(4, 17)
+	if string(hdr.Bytes()) != "HDR:" {
+		t.Errorf("hdr = %q", hdr.Bytes())
+	}
+	if string(payload.Bytes()) != "payload-bytes" {
+		t.Errorf("payload = %q", payload.Bytes())
+	}
+	word := payload.Slice(8, 13)
+	if string(word.Bytes()) != "bytes" || word.Off != 12 {
+		t.Errorf("nested slice = %q at %d", word.Bytes(), word.Off)
+	}
+	if cap(word.Bytes()) != word.Len {
+		t.Error("Bytes() must be capped at the end of the view")
+	}
+
+	word.Bytes()[0] = 'B'
+	if pool.Value(idx)[12] != 'B' {
+		t.Error("view does not alias pool memory")
+	}
+
+	if err := payload.Release(); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	if _, err := pool.Get(); err != nil {
+		t.Fatalf("buffer not returned by Release: %v", err)
+	}
+}
+
+func TestBufferView_SliceOutOfRange(t *testing.T) {
+	pool := iobuf.NewPicoBufferPool(1)
+	pool.Fill(iobuf.NewPicoBuffer)
+	idx, _ := pool.Get()
+	v := iobuf.ViewOf(pool, idx).Slice(0, 8)
+
+	for _, r := range [][2]int{{-1, 2}, {3, 2}, {0, 9}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Slice(%d, %d) should panic", r[0], r[1])
+				}
+			}()
+			v.Slice(r[0], r[1])
+		}()
+	}
+}
+
+func TestBufferView_Binary(t *testing.T) {
+	pool := iobuf.NewPicoBufferPool(1)
+	pool.Fill(iobuf.NewPicoBuffer)
+	idx, _ := pool.Get()
+	defer pool.Put(idx)
+
+	hdr := iobuf.ViewOf(pool, idx).Slice(2, 16)
+	hdr.PutUint16(0, 0x0102)
+	hdr.PutUint32(2, 0x03040506)
+	hdr.PutUint64(6, 0x0708090a0b0c0d0e)
+	raw := pool.Bytes(idx)
+	if raw[2] != 0x01 || raw[3] != 0x02 || raw[4] != 0x03 || raw[15] != 0x0e {
+		t.Fatalf("big-endian layout = % x", raw[2:16])
+	}
+	if hdr.Uint16(0) != 0x0102 || hdr.Uint32(2) != 0x03040506 || hdr.Uint64(6) != 0x0708090a0b0c0d0e {
+		t.Error("big-endian round trip mismatch")
+	}
+
+	hdr.PutUint32Order(binary.LittleEndian, 2, 0x03040506)
+	if raw[4] != 0x06 {
+		t.Errorf("little-endian first byte = %#x, want 0x06", raw[4])
+	}
+	if hdr.Uint32Order(binary.LittleEndian, 2) != 0x03040506 {
+		t.Error("little-endian round trip mismatch")
+	}
+	hdr.PutUint16Order(binary.LittleEndian, 0, 0xbeef)
+	hdr.PutUint64Order(binary.LittleEndian, 6, 42)
+	if hdr.Uint16Order(binary.LittleEndian, 0) != 0xbeef || hdr.Uint64Order(binary.LittleEndian, 6) != 42 {
+		t.Error("little-endian round trip mismatch")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("PutUint64 past the end of the view should panic")
+		}
+	}()
+	hdr.PutUint64(8, 1)
+}
@@ -5,6 +5,8 @@
 package iobuf
 
 import (
+	"io"
+	"strconv"
 	"unsafe"
 
 	"code.hybscloud.com/iobuf/internal"
@@ -38,6 +40,15 @@ func AlignedMem(size int, pageSize uintptr) []byte {
 	return unsafe.Slice((*byte)(unsafe.Add(base, offset)), size)
 }
 
+// AlignedMemCap is like AlignedMem but returns a slice whose capacity
+// extends to the next pageSize boundary, so the slack space that rounding
+// reserves anyway can be used, e.g., by appending. len(result) == size and
+// cap(result) is size rounded up to a multiple of pageSize.
+func AlignedMemCap(size int, pageSize uintptr) []byte {
+	rounded := int((uintptr(size) + pageSize - 1) / pageSize * pageSize)
+	return AlignedMem(rounded, pageSize)[:size:rounded]
+}
+
 // AlignedMemBlocks returns n page-aligned byte slices, each of length pageSize.
 //
 // All returned slices share a single contiguous underlying allocation,
@@ -58,6 +69,36 @@ func AlignedMemBlocks(n int, pageSize uintptr) (blocks [][]byte) {
 	return
 }
 
+// AlignedMemBlocksOf returns one page-aligned byte slice per entry of sizes,
+// all carved from a single contiguous allocation.
+//
+// Each block starts on a pageSize boundary and occupies its size rounded up
+// to a whole number of pages, so blocks of mixed sizes (e.g., io_uring
+// submission and completion rings of different lengths) need only one
+// allocation. Block i has length and capacity sizes[i].
+//
+// Panics if sizes is empty or any size is < 1.
+func AlignedMemBlocksOf(sizes []int, pageSize uintptr) (blocks [][]byte) {
+	if len(sizes) < 1 {
+		panic("bad block num")
+	}
+	total := uintptr(0)
+	for _, size := range sizes {
+		if size < 1 {
+			panic("bad block size")
+		}
+		total += (uintptr(size) + pageSize - 1) / pageSize * pageSize
+	}
+	p := AlignedMem(int(total), pageSize)
+	blocks = make([][]byte, len(sizes))
+	off := 0
+	for i, size := range sizes {
+		blocks[i] = p[off : off+size : off+size]
+		off += int((uintptr(size) + pageSize - 1) / pageSize * pageSize)
+	}
+	return
+}
+
 // AlignedMemBlock returns a single page-aligned block using the system page size.
 //
 // This is a convenience function equivalent to AlignedMemBlocks(1, PageSize)[0].
@@ -212,13 +253,45 @@ func TierBySize(size int) BufferTier {
 }
 
 // Size returns the buffer size for this tier.
+// Tiers registered with RegisterBufferSize report their registered size.
 func (t BufferTier) Size() int {
+	if size, ok := customSize(t); ok {
+		return size
+	}
 	if t < 0 || t >= TierEnd {
 		return BufferSizeTitan
 	}
 	return bufferSizes[t]
 }
 
+// bufferTierNames maps tier index to its display name.
+var bufferTierNames = [TierEnd]string{
+	TierPico:   "Pico",
+	TierNano:   "Nano",
+	TierMicro:  "Micro",
+	TierSmall:  "Small",
+	TierMedium: "Medium",
+	TierBig:    "Big",
+	TierLarge:  "Large",
+	TierGreat:  "Great",
+	TierHuge:   "Huge",
+	TierVast:   "Vast",
+	TierGiant:  "Giant",
+	TierTitan:  "Titan",
+}
+
+// String returns the tier name (e.g., "Small", or "Custom(9216)" for a
+// registered size class).
+func (t BufferTier) String() string {
+	if size, ok := customSize(t); ok {
+		return "Custom(" + strconv.Itoa(size) + ")"
+	}
+	if t < 0 || t >= TierEnd {
+		return "BufferTier(" + strconv.Itoa(int(t)) + ")"
+	}
+	return bufferTierNames[t]
+}
+
 // BufferSizeFor returns the smallest buffer size that can hold 'size' bytes.
 // This is a convenience function equivalent to TierBySize(size).Size().
 func BufferSizeFor(size int) int {
@@ -322,6 +395,92 @@ func (b VastBuffer) Reset()   {}
 func (b GiantBuffer) Reset()  {}
 func (b TitanBuffer) Reset()  {}
 
+// Bytes returns the whole buffer as a slice aliasing the array, and
+// Slice(i, j) returns b[i:j]; Slice panics if the range is invalid.
+//
+// Both methods have pointer receivers on purpose: pool.Value returns tier
+// buffers by value, so writing through buf[:] on such a value silently
+// modifies a copy. Call these methods on an addressable buffer, and use
+// pool.Bytes(indirect) to access a pooled buffer in place.
+func (b *PicoBuffer) Bytes() []byte   { return b[:] }
+func (b *NanoBuffer) Bytes() []byte   { return b[:] }
+func (b *MicroBuffer) Bytes() []byte  { return b[:] }
+func (b *SmallBuffer) Bytes() []byte  { return b[:] }
+func (b *MediumBuffer) Bytes() []byte { return b[:] }
+func (b *BigBuffer) Bytes() []byte    { return b[:] }
+func (b *LargeBuffer) Bytes() []byte  { return b[:] }
+func (b *GreatBuffer) Bytes() []byte  { return b[:] }
+func (b *HugeBuffer) Bytes() []byte   { return b[:] }
+func (b *VastBuffer) Bytes() []byte   { return b[:] }
+func (b *GiantBuffer) Bytes() []byte  { return b[:] }
+func (b *TitanBuffer) Bytes() []byte  { return b[:] }
+
+func (b *PicoBuffer) Slice(i, j int) []byte   { return b[i:j] }
+func (b *NanoBuffer) Slice(i, j int) []byte   { return b[i:j] }
+func (b *MicroBuffer) Slice(i, j int) []byte  { return b[i:j] }
+func (b *SmallBuffer) Slice(i, j int) []byte  { return b[i:j] }
+func (b *MediumBuffer) Slice(i, j int) []byte { return b[i:j] }
+func (b *BigBuffer) Slice(i, j int) []byte    { return b[i:j] }
+func (b *LargeBuffer) Slice(i, j int) []byte  { return b[i:j] }
+func (b *GreatBuffer) Slice(i, j int) []byte  { return b[i:j] }
+func (b *HugeBuffer) Slice(i, j int) []byte   { return b[i:j] }
+func (b *VastBuffer) Slice(i, j int) []byte   { return b[i:j] }
+func (b *GiantBuffer) Slice(i, j int) []byte  { return b[i:j] }
+func (b *TitanBuffer) Slice(i, j int) []byte  { return b[i:j] }
+
+// CopyInto copies src into the buffer starting at off and returns the number
+// of bytes copied. The copy is clamped to the end of the buffer, so n is less
+// than len(src) when src does not fit; an offset outside the buffer copies
+// nothing.
+//
+// WriteAt implements io.WriterAt with the same clamping and returns
+// io.ErrShortWrite when p does not fit.
+func (b *PicoBuffer) CopyInto(off int, src []byte) (n int)   { return copyAt(b[:], off, src) }
+func (b *NanoBuffer) CopyInto(off int, src []byte) (n int)   { return copyAt(b[:], off, src) }
+func (b *MicroBuffer) CopyInto(off int, src []byte) (n int)  { return copyAt(b[:], off, src) }
+func (b *SmallBuffer) CopyInto(off int, src []byte) (n int)  { return copyAt(b[:], off, src) }
+func (b *MediumBuffer) CopyInto(off int, src []byte) (n int) { return copyAt(b[:], off, src) }
+func (b *BigBuffer) CopyInto(off int, src []byte) (n int)    { return copyAt(b[:], off, src) }
+func (b *LargeBuffer) CopyInto(off int, src []byte) (n int)  { return copyAt(b[:], off, src) }
+func (b *GreatBuffer) CopyInto(off int, src []byte) (n int)  { return copyAt(b[:], off, src) }
+func (b *HugeBuffer) CopyInto(off int, src []byte) (n int)   { return copyAt(b[:], off, src) }
+func (b *VastBuffer) CopyInto(off int, src []byte) (n int)   { return copyAt(b[:], off, src) }
+func (b *GiantBuffer) CopyInto(off int, src []byte) (n int)  { return copyAt(b[:], off, src) }
+func (b *TitanBuffer) CopyInto(off int, src []byte) (n int)  { return copyAt(b[:], off, src) }
+
+func (b *PicoBuffer) WriteAt(p []byte, off int64) (int, error)   { return writeAt(b[:], p, off) }
+func (b *NanoBuffer) WriteAt(p []byte, off int64) (int, error)   { return writeAt(b[:], p, off) }
+func (b *MicroBuffer) WriteAt(p []byte, off int64) (int, error)  { return writeAt(b[:], p, off) }
+func (b *SmallBuffer) WriteAt(p []byte, off int64) (int, error)  { return writeAt(b[:], p, off) }
+func (b *MediumBuffer) WriteAt(p []byte, off int64) (int, error) { return writeAt(b[:], p, off) }
+func (b *BigBuffer) WriteAt(p []byte, off int64) (int, error)    { return writeAt(b[:], p, off) }
+func (b *LargeBuffer) WriteAt(p []byte, off int64) (int, error)  { return writeAt(b[:], p, off) }
+func (b *GreatBuffer) WriteAt(p []byte, off int64) (int, error)  { return writeAt(b[:], p, off) }
+func (b *HugeBuffer) WriteAt(p []byte, off int64) (int, error)   { return writeAt(b[:], p, off) }
+func (b *VastBuffer) WriteAt(p []byte, off int64) (int, error)   { return writeAt(b[:], p, off) }
+func (b *GiantBuffer) WriteAt(p []byte, off int64) (int, error)  { return writeAt(b[:], p, off) }
+func (b *TitanBuffer) WriteAt(p []byte, off int64) (int, error)  { return writeAt(b[:], p, off) }
+
+// copyAt copies src into dst[off:], clamped to the bounds of dst.
+func copyAt(dst []byte, off int, src []byte) int {
+	if off < 0 || off > len(dst) {
+		return 0
+	}
+	return copy(dst[off:], src)
+}
+
+// writeAt is copyAt with io.WriterAt semantics.
+func writeAt(dst []byte, p []byte, off int64) (int, error) {
+	if off < 0 || off > int64(len(dst)) {
+		return 0, io.ErrShortWrite
+	}
+	n := copy(dst[off:], p)
+	if n < len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
 // PicoArrayFromSlice returns a PicoBuffer by copying from the slice at the given offset.
 //
 // The caller must ensure offset+BufferSizePico <= len(s).
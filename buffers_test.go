@@ -5,6 +5,8 @@
 package iobuf_test
 
 import (
+	"io"
+	"os"
 	"testing"
 	"unsafe"
 
@@ -39,6 +41,23 @@ func TestAlignedMem_SmallAllocation(t *testing.T) {
 	}
 }
 
+func TestAlignedMemCap(t *testing.T) {
+	mem := iobuf.AlignedMemCap(5000, 4096)
+	if len(mem) != 5000 || cap(mem) != 8192 {
+		t.Errorf("len=%d cap=%d, want 5000 and 8192", len(mem), cap(mem))
+	}
+	if uintptr(unsafe.Pointer(unsafe.SliceData(mem)))%4096 != 0 {
+		t.Error("AlignedMemCap not page-aligned")
+	}
+	mem = append(mem, make([]byte, 3192)...)
+	if len(mem) != 8192 || cap(mem) != 8192 {
+		t.Errorf("append into slack reallocated: len=%d cap=%d", len(mem), cap(mem))
+	}
+	if exact := iobuf.AlignedMemCap(4096, 4096); cap(exact) != 4096 {
+		t.Errorf("cap for exact page multiple = %d, want 4096", cap(exact))
+	}
+}
+
 func TestAlignedMemBlocks(t *testing.T) {
 	const n = 4
 	blocks := iobuf.AlignedMemBlocks(n, iobuf.PageSize)
@@ -186,6 +205,12 @@ func TestAlignedMem_NonStandardPageSize(t *testing.T) {
 	}
 }
 
+func TestPageSize_Default(t *testing.T) {
+	if iobuf.PageSize != uintptr(os.Getpagesize()) {
+		t.Errorf("PageSize = %d, want system page size %d", iobuf.PageSize, os.Getpagesize())
+	}
+}
+
 func TestSetPageSize(t *testing.T) {
 	original := iobuf.PageSize
 	defer iobuf.SetPageSize(int(original))
@@ -866,3 +891,136 @@ func TestSliceOfArray_Panic_Extended(t *testing.T) {
 		})
 	}
 }
+
+func TestBufferTierString(t *testing.T) {
+	tests := []struct {
+		tier iobuf.BufferTier
+		want string
+	}{
+		{iobuf.TierPico, "Pico"},
+		{iobuf.TierMedium, "Medium"},
+		{iobuf.TierTitan, "Titan"},
+		{iobuf.TierEnd, "BufferTier(12)"},
+		{-1, "BufferTier(-1)"},
+	}
+	for _, tt := range tests {
+		if got := tt.tier.String(); got != tt.want {
+			t.Errorf("BufferTier(%d).String() = %q, want %q", int(tt.tier), got, tt.want)
+		}
+	}
+}
+
+func TestBufferCopyInto(t *testing.T) {
+	type copier interface {
+		CopyInto(off int, src []byte) int
+		io.WriterAt
+	}
+	tests := []struct {
+		name string
+		buf  copier
+		size int
+	}{
+		{"PicoBuffer", new(iobuf.PicoBuffer), iobuf.BufferSizePico},
+		{"NanoBuffer", new(iobuf.NanoBuffer), iobuf.BufferSizeNano},
+		{"MicroBuffer", new(iobuf.MicroBuffer), iobuf.BufferSizeMicro},
+		{"SmallBuffer", new(iobuf.SmallBuffer), iobuf.BufferSizeSmall},
+		{"MediumBuffer", new(iobuf.MediumBuffer), iobuf.BufferSizeMedium},
+		{"BigBuffer", new(iobuf.BigBuffer), iobuf.BufferSizeBig},
+	}
+	src := []byte("0123456789")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if n := tt.buf.CopyInto(0, src); n != len(src) {
+				t.Errorf("CopyInto(0) = %d, want %d", n, len(src))
+			}
+			if n := tt.buf.CopyInto(tt.size-4, src); n != 4 {
+				t.Errorf("CopyInto(size-4) = %d, want 4", n)
+			}
+			if n := tt.buf.CopyInto(tt.size, src); n != 0 {
+				t.Errorf("CopyInto(size) = %d, want 0", n)
+			}
+			if n := tt.buf.CopyInto(-1, src); n != 0 {
+				t.Errorf("CopyInto(-1) = %d, want 0", n)
+			}
+
+			if n, err := tt.buf.WriteAt(src, 2); n != len(src) || err != nil {
+				t.Errorf("WriteAt(2) = %d, %v", n, err)
+			}
+			if n, err := tt.buf.WriteAt(src, int64(tt.size-3)); n != 3 || err != io.ErrShortWrite {
+				t.Errorf("WriteAt(size-3) = %d, %v, want 3, ErrShortWrite", n, err)
+			}
+			if n, err := tt.buf.WriteAt(src, int64(tt.size+1)); n != 0 || err != io.ErrShortWrite {
+				t.Errorf("WriteAt(size+1) = %d, %v, want 0, ErrShortWrite", n, err)
+			}
+		})
+	}
+
+	var buf iobuf.PicoBuffer
+	buf.CopyInto(30, []byte("xyz"))
+	if buf[30] != 'x' || buf[31] != 'y' {
+		t.Errorf("CopyInto wrote %q at tail", buf[30:])
+	}
+}
+
+func TestBufferBytes(t *testing.T) {
+	var pico iobuf.PicoBuffer
+	b := pico.Bytes()
+	if len(b) != iobuf.BufferSizePico {
+		t.Fatalf("Bytes() len = %d, want %d", len(b), iobuf.BufferSizePico)
+	}
+	b[0] = 'P'
+	if pico[0] != 'P' {
+		t.Error("Bytes() does not alias the array")
+	}
+
+	var small iobuf.SmallBuffer
+	s := small.Slice(8, 16)
+	if len(s) != 8 || cap(s) != iobuf.BufferSizeSmall-8 {
+		t.Errorf("Slice(8, 16) len=%d cap=%d", len(s), cap(s))
+	}
+	s[0] = 'S'
+	if small[8] != 'S' {
+		t.Error("Slice() does not alias the array")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Slice() out of range should panic")
+		}
+	}()
+	j := iobuf.BufferSizeSmall + 1
+	small.Slice(0, j)
+}
+
+func TestAlignedMemBlocksOf(t *testing.T) {
+	sizes := []int{4096, 100, 3 * 4096, 5000}
+	blocks := iobuf.AlignedMemBlocksOf(sizes, 4096)
+	if len(blocks) != len(sizes) {
+		t.Fatalf("got %d blocks, want %d", len(blocks), len(sizes))
+	}
+	base := uintptr(unsafe.Pointer(unsafe.SliceData(blocks[0])))
+	wantOff := []uintptr{0, 4096, 2 * 4096, 5 * 4096}
+	for i, b := range blocks {
+		if len(b) != sizes[i] || cap(b) != sizes[i] {
+			t.Errorf("block %d len=%d cap=%d, want %d", i, len(b), cap(b), sizes[i])
+		}
+		addr := uintptr(unsafe.Pointer(unsafe.SliceData(b)))
+		if addr%4096 != 0 {
+			t.Errorf("block %d not page-aligned", i)
+		}
+		if addr-base != wantOff[i] {
+			t.Errorf("block %d at offset %d, want %d", i, addr-base, wantOff[i])
+		}
+	}
+
+	for name, sizes := range map[string][]int{"empty": nil, "zero size": {4096, 0}} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("AlignedMemBlocksOf should panic")
+				}
+			}()
+			iobuf.AlignedMemBlocksOf(sizes, 4096)
+		})
+	}
+}
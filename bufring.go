@@ -0,0 +1,227 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"math"
+	"math/bits"
+	"sync/atomic"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf/internal"
+)
+
+// bufRingEntrySize is sizeof(struct io_uring_buf).
+const bufRingEntrySize = 16
+
+// BufRing is a BufferGroup backed by a ring-mapped provided-buffer ring
+// (IORING_REGISTER_PBUF_RING, Linux 5.19+).
+//
+// Buffers reach the kernel by writing ring entries and publishing the ring
+// tail; no SQEs are needed. The ring holds up to its entry count of
+// buffers at a time, so Replenish stops early once it is full.
+//
+// In incremental mode (IOU_PBUF_RING_INC, Linux 6.12+) the kernel consumes
+// one large buffer across several receives, each completion covering the
+// next bytes of the same buffer. Report those completions with Consume:
+// the buffer stays with the kernel while the completion carries
+// IORING_CQE_F_BUF_MORE, and only the final completion hands the pool
+// index back to the caller.
+//
+// A BufRing must not be used from multiple goroutines concurrently.
+type BufRing[T BufferType] struct {
+	pool     *BoundedPool[T]
+	ringFd   int
+	bgid     uint16
+	inc      bool
+	mem      []byte   // Ring entries, shared with the kernel
+	mask     uint16   // Entry count minus one
+	tail     uint16   // Next entry to fill
+	inKernel []bool   // Whether each buffer ID is with the kernel
+	consumed []uint32 // Bytes the kernel has used per buffer ID (incremental)
+	provided int
+}
+
+var _ BufferGroup = (*BufRing[SmallBuffer])(nil)
+
+// NewBufRing registers a buffer ring of the given entry count as group
+// bgid of the io_uring instance ringFd, fed from pool, which must be
+// filled. entries must be a power of two no larger than 32768; zero picks
+// the pool capacity rounded up, capped at 32768. With incremental set the ring is
+// registered in incremental consumption mode. Call Replenish to provide
+// the initial buffers. Returns errors.ErrUnsupported on platforms without
+// io_uring.
+// Panics if the pool is not filled, has more than 65536 buffers, or
+// entries is invalid.
+func NewBufRing[T BufferType](ringFd int, pool *BoundedPool[T], bgid uint16, entries int, incremental bool) (*BufRing[T], error) {
+	if len(pool.items) != int(pool.capacity) {
+		panic("must Fill the pool before using it")
+	}
+	if pool.capacity > math.MaxUint16+1 {
+		panic("iobuf.NewBufRing: too many buffers for 16-bit buffer IDs")
+	}
+	if entries == 0 {
+		entries = min(1<<bits.Len(uint(pool.capacity-1)), 1<<15)
+	}
+	if entries < 1 || entries > 1<<15 || entries&(entries-1) != 0 {
+		panic("iobuf.NewBufRing: entries must be a power of two up to 32768")
+	}
+	size := (entries*bufRingEntrySize + int(PageSize) - 1) &^ (int(PageSize) - 1)
+	mem, err := mapRegion(size, 0)
+	if err != nil {
+		return nil, err
+	}
+	if f := internal.FakeRingOf(ringFd); f != nil {
+		err = f.RegisterBufRing(mem, entries, bgid, incremental)
+	} else {
+		err = registerBufRing(ringFd, mem, entries, bgid, incremental)
+	}
+	if err != nil {
+		_ = unmapRegion(mem)
+		return nil, err
+	}
+	r := &BufRing[T]{
+		pool:     pool,
+		ringFd:   ringFd,
+		bgid:     bgid,
+		inc:      incremental,
+		mem:      mem,
+		mask:     uint16(entries - 1),
+		inKernel: make([]bool, pool.capacity),
+	}
+	if incremental {
+		r.consumed = make([]uint32, pool.capacity)
+	}
+	return r, nil
+}
+
+// GroupID returns the buffer group ID.
+func (r *BufRing[T]) GroupID() uint16 { return r.bgid }
+
+// Incremental reports whether the ring is in incremental consumption mode.
+func (r *BufRing[T]) Incremental() bool { return r.inc }
+
+// Provided returns the number of buffers currently with the kernel.
+func (r *BufRing[T]) Provided() int { return r.provided }
+
+// Completed takes over the buffer with ID bid from the kernel for a
+// completion of n bytes. In incremental mode it is Consume for a final
+// completion.
+// Panics if the buffer is not with the kernel or n exceeds its size.
+func (r *BufRing[T]) Completed(bid uint16, n int) (indirect int, data []byte) {
+	indirect, data, _ = r.Consume(bid, n, false)
+	return indirect, data
+}
+
+// Consume reports a completion of n bytes from the buffer with ID bid.
+// more is whether the completion carried IORING_CQE_F_BUF_MORE.
+//
+// data holds the n bytes received. In incremental mode they follow the
+// bytes of earlier completions from the same buffer and stay valid while
+// the kernel fills the rest of it. While more is set the buffer remains
+// with the kernel and final is false; the caller must not Put indirect.
+// Otherwise final is true and the caller holds indirect, to be Put back
+// when done with all data received into the buffer.
+// Panics if the buffer is not with the kernel or the bytes consumed exceed
+// its size.
+func (r *BufRing[T]) Consume(bid uint16, n int, more bool) (indirect int, data []byte, final bool) {
+	indirect = int(bid)
+	if indirect >= len(r.inKernel) || !r.inKernel[indirect] {
+		panic("iobuf.BufRing.Consume: buffer not provided")
+	}
+	buf := r.pool.itemBytes(indirect)
+	off := 0
+	if r.inc {
+		off = int(r.consumed[indirect])
+	}
+	if n < 0 || n > len(buf)-off {
+		panic("iobuf.BufRing.Consume: completion exceeds buffer")
+	}
+	data = buf[off : off+n]
+	if r.inc && more {
+		r.consumed[indirect] += uint32(n)
+		return indirect, data, false
+	}
+	if r.inc {
+		r.consumed[indirect] = 0
+	}
+	r.inKernel[indirect] = false
+	r.provided--
+	return indirect, data, true
+}
+
+// Replenish takes available buffers from the pool and adds them to the
+// ring until the pool is empty or the ring is full, then publishes them to
+// the kernel.
+func (r *BufRing[T]) Replenish() (int, error) {
+	size := uint32(unsafe.Sizeof(*new(T)))
+	n := 0
+	for r.provided < int(r.mask)+1 {
+		e, err := r.pool.tryGet()
+		if err != nil {
+			break
+		}
+		indirect := int(e & uint64(r.pool.mask))
+		if r.pool.debug != 0 {
+			r.pool.onGet(indirect)
+		}
+		ent := (*[bufRingEntrySize]byte)(r.mem[int(r.tail&r.mask)*bufRingEntrySize:])
+		*(*uint64)(unsafe.Pointer(&ent[0])) = uint64(uintptr(unsafe.Pointer(&r.pool.items[indirect])))
+		*(*uint32)(unsafe.Pointer(&ent[8])) = size
+		*(*uint16)(unsafe.Pointer(&ent[12])) = uint16(indirect)
+		r.tail++
+		r.inKernel[indirect] = true
+		r.provided++
+		n++
+	}
+	if n > 0 {
+		r.publish()
+	}
+	return n, nil
+}
+
+// publish stores the ring tail with release semantics. The tail occupies
+// the reserved field of the first entry, next to that entry's buffer ID;
+// both are written together in one 32-bit store, as Go has no 16-bit
+// atomics.
+func (r *BufRing[T]) publish() {
+	var w [2]uint16
+	w[0] = *(*uint16)(unsafe.Pointer(&r.mem[12]))
+	w[1] = r.tail
+	atomic.StoreUint32((*uint32)(unsafe.Pointer(&r.mem[12])), *(*uint32)(unsafe.Pointer(&w)))
+}
+
+// Close unregisters the ring, returns the buffers still with the kernel to
+// the pool and releases the ring memory. Call it when no operation
+// selecting from the group is in flight. The buffers go back through Put,
+// so stats, owner tracking and hooks see them; the first error is
+// returned.
+func (r *BufRing[T]) Close() error {
+	var err error
+	if f := internal.FakeRingOf(r.ringFd); f != nil {
+		err = f.UnregisterBufRing(r.bgid)
+	} else {
+		err = unregisterBufRing(r.ringFd, r.bgid)
+	}
+	if err != nil {
+		return err
+	}
+	for id, ok := range r.inKernel {
+		if ok {
+			r.inKernel[id] = false
+			if perr := r.pool.Put(id); err == nil {
+				err = perr
+			}
+		}
+	}
+	clear(r.consumed)
+	r.provided = 0
+	mem := r.mem
+	r.mem = nil
+	if uerr := unmapRegion(mem); err == nil {
+		err = uerr
+	}
+	return err
+}
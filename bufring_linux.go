@@ -0,0 +1,27 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "unsafe"
+
+// registerBufRing registers mem as a provided-buffer ring of entries
+// entries for group bgid.
+func registerBufRing(ringFd int, mem []byte, entries int, bgid uint16, incremental bool) error {
+	reg := ioUringBufReg{
+		ringAddr:    uint64(uintptr(unsafe.Pointer(unsafe.SliceData(mem)))),
+		ringEntries: uint32(entries),
+		bgid:        bgid,
+	}
+	if incremental {
+		reg.flags = iouPbufRingInc
+	}
+	return ioUringRegister(ringFd, ioringRegisterPbufRing, unsafe.Pointer(&reg), 1)
+}
+
+// unregisterBufRing removes the provided-buffer ring of group bgid.
+func unregisterBufRing(ringFd int, bgid uint16) error {
+	reg := ioUringBufReg{bgid: bgid}
+	return ioUringRegister(ringFd, ioringUnregisterPbufRing, unsafe.Pointer(&reg), 1)
+}
@@ -0,0 +1,132 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && !mips64 && !mips64le
+
+package iobuf_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func newTestBufRing(t *testing.T, capacity, entries int, incremental bool) (*iobuf.BufRing[iobuf.SmallBuffer], *iobuf.SmallBufferBoundedPool) {
+	t.Helper()
+	ring := newTestRing(t)
+	pool := iobuf.NewSmallBufferPool(capacity)
+	pool.Fill(iobuf.NewSmallBuffer)
+	r, err := iobuf.NewBufRing(ring, pool, 7, entries, incremental)
+	if err != nil {
+		if errors.Is(err, syscall.EINVAL) {
+			t.Skipf("buffer ring mode not supported: %v", err)
+		}
+		t.Fatalf("NewBufRing() failed: %v", err)
+	}
+	return r, pool
+}
+
+func TestBufRing_Replenish(t *testing.T) {
+	r, pool := newTestBufRing(t, 8, 4, false)
+	if n, err := r.Replenish(); err != nil || n != 4 {
+		t.Fatalf("Replenish() = %d, %v; want 4 (ring full)", n, err)
+	}
+	if n, _ := r.Replenish(); n != 0 {
+		t.Errorf("Replenish() on a full ring added %d buffers", n)
+	}
+	if r.Provided() != 4 {
+		t.Errorf("Provided() = %d, want 4", r.Provided())
+	}
+
+	// The kernel consumes buffer 2; the application returns it.
+	idx, data := r.Completed(2, 10)
+	if idx != 2 || len(data) != 10 || &data[0] != &pool.Bytes(2)[0] {
+		t.Fatalf("Completed(2) = %d, len %d", idx, len(data))
+	}
+	if r.Provided() != 3 {
+		t.Fatalf("Provided() after a completion = %d, want 3", r.Provided())
+	}
+	_ = pool.Put(idx)
+	if n, _ := r.Replenish(); n != 1 {
+		t.Errorf("Replenish() after a completion added %d, want 1", n)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if r.Provided() != 0 {
+		t.Errorf("Provided() after Close = %d", r.Provided())
+	}
+}
+
+func TestBufRing_CloseBalancesStats(t *testing.T) {
+	r, pool := newTestBufRing(t, 8, 4, false)
+	pool.SetStats(true)
+	if n, _ := r.Replenish(); n != 4 {
+		t.Fatalf("Replenish() = %d, want 4", n)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	s := pool.Stats()
+	if s.Gets != 4 || s.Puts != 4 || s.InUse() != 0 {
+		t.Errorf("Stats() after Close: Gets %d, Puts %d, InUse %d; want 4, 4, 0", s.Gets, s.Puts, s.InUse())
+	}
+}
+
+func TestBufRing_Incremental(t *testing.T) {
+	r, pool := newTestBufRing(t, 1, 0, true)
+	defer r.Close()
+	if !r.Incremental() {
+		t.Fatal("Incremental() = false")
+	}
+	if n, _ := r.Replenish(); n != 1 {
+		t.Fatalf("Replenish() = %d, want 1", n)
+	}
+	copy(pool.Bytes(0), "hello, world")
+
+	_, first, final := r.Consume(0, 5, true)
+	if final || string(first) != "hello" {
+		t.Fatalf("first Consume() = %q, final %v", first, final)
+	}
+	_, second, final := r.Consume(0, 7, true)
+	if final || string(second) != ", world" {
+		t.Fatalf("second Consume() = %q, final %v", second, final)
+	}
+	if r.Provided() != 1 {
+		t.Errorf("buffer left the kernel before its final completion")
+	}
+	indirect, last, final := r.Consume(0, 0, false)
+	if !final || indirect != 0 || len(last) != 0 {
+		t.Fatalf("final Consume() = %d, %d bytes, final %v", indirect, len(last), final)
+	}
+	if r.Provided() != 0 {
+		t.Errorf("Provided() after final completion = %d", r.Provided())
+	}
+}
+
+func TestBufRing_Panics(t *testing.T) {
+	r, _ := newTestBufRing(t, 2, 0, true)
+	defer r.Close()
+	r.Replenish()
+	for name, f := range map[string]func(){
+		"unknown bid": func() { r.Consume(9, 1, false) },
+		"overrun":     func() { r.Consume(0, iobuf.BufferSizeSmall+1, true) },
+		"entries": func() {
+			p := iobuf.NewSmallBufferPool(2)
+			p.Fill(iobuf.NewSmallBuffer)
+			_, _ = iobuf.NewBufRing(-1, p, 1, 3, false)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s should panic", name)
+				}
+			}()
+			f()
+		})
+	}
+}
@@ -0,0 +1,19 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package iobuf
+
+import "errors"
+
+// registerBufRing is not supported without io_uring.
+func registerBufRing(ringFd int, mem []byte, entries int, bgid uint16, incremental bool) error {
+	return errors.ErrUnsupported
+}
+
+// unregisterBufRing is not supported without io_uring.
+func unregisterBufRing(ringFd int, bgid uint16) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,130 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "unsafe"
+
+// Builder assembles a byte stream across chained pool segments, like
+// strings.Builder but without heap allocations for the data.
+//
+// The pools are given in order of increasing buffer size, e.g., Small and
+// Medium tier pools. The first segment comes from the first pool and
+// subsequent segments from progressively larger ones, falling back to the
+// other pools when one is exhausted. The finished stream is handed to
+// vectored I/O with IoVec, or moved into a CompositeBuffer with MoveTo.
+//
+// A Builder must not be copied after first use or used from multiple
+// goroutines concurrently.
+type Builder struct {
+	_ noCopy
+
+	pools []BufferPool
+	segs  []compositeSegment // data is the written prefix of each segment
+	n     int
+}
+
+// NewBuilder returns an empty Builder that acquires segments from pools,
+// which should be ordered by increasing buffer size.
+// Panics if no pool is given.
+func NewBuilder(pools ...BufferPool) *Builder {
+	if len(pools) == 0 {
+		panic("iobuf.NewBuilder: no pools")
+	}
+	return &Builder{pools: pools}
+}
+
+// Len returns the number of bytes written.
+func (b *Builder) Len() int { return b.n }
+
+// Segments returns the number of segments held.
+func (b *Builder) Segments() int { return len(b.segs) }
+
+// Write appends p to the stream. If no segment can be acquired from a
+// non-blocking pool, Write returns the number of bytes appended so far and
+// iox.ErrWouldBlock.
+func (b *Builder) Write(p []byte) (n int, err error) {
+	for n < len(p) {
+		tail, err := b.tail()
+		if err != nil {
+			return n, err
+		}
+		m := copy(tail.data[len(tail.data):cap(tail.data)], p[n:])
+		tail.data = tail.data[:len(tail.data)+m]
+		n += m
+		b.n += m
+	}
+	return n, nil
+}
+
+// WriteString appends s to the stream, like Write.
+func (b *Builder) WriteString(s string) (n int, err error) {
+	return b.Write(unsafe.Slice(unsafe.StringData(s), len(s)))
+}
+
+// WriteByte appends c to the stream, like Write.
+func (b *Builder) WriteByte(c byte) error {
+	_, err := b.Write([]byte{c})
+	return err
+}
+
+// IoVec returns an IoVec slice describing the written data, one element
+// per segment, for writev or io_uring submission. The vector stays valid
+// until the Builder is modified or closed.
+func (b *Builder) IoVec() []IoVec {
+	vec := make([]IoVec, 0, len(b.segs))
+	for _, seg := range b.segs {
+		if len(seg.data) > 0 {
+			vec = append(vec, IoVec{Base: unsafe.SliceData(seg.data), Len: uint64(len(seg.data))})
+		}
+	}
+	return vec
+}
+
+// MoveTo appends the written data to c and transfers ownership of every
+// segment to it. The Builder is left empty and can be reused.
+func (b *Builder) MoveTo(c *CompositeBuffer) {
+	for _, seg := range b.segs {
+		c.Append(seg.pool, seg.indirect, len(seg.data))
+	}
+	clear(b.segs)
+	b.segs = b.segs[:0]
+	b.n = 0
+}
+
+// Close returns every segment to its pool and empties the Builder.
+// The Builder can be reused afterwards.
+func (b *Builder) Close() error {
+	for len(b.segs) > 0 {
+		seg := &b.segs[len(b.segs)-1]
+		if err := seg.pool.Put(seg.indirect); err != nil {
+			return err
+		}
+		b.n -= len(seg.data)
+		*seg = compositeSegment{}
+		b.segs = b.segs[:len(b.segs)-1]
+	}
+	return nil
+}
+
+// tail returns the last segment, acquiring a new one if it is full.
+func (b *Builder) tail() (*compositeSegment, error) {
+	if k := len(b.segs); k > 0 && len(b.segs[k-1].data) < cap(b.segs[k-1].data) {
+		return &b.segs[k-1], nil
+	}
+	first := min(len(b.segs), len(b.pools)-1)
+	var err error
+	for i := range b.pools {
+		pool := b.pools[(first+i)%len(b.pools)]
+		var indirect int
+		indirect, err = pool.Get()
+		if err != nil {
+			continue
+		}
+		buf := pool.Bytes(indirect)
+		b.segs = append(b.segs, compositeSegment{pool: pool, indirect: indirect, data: buf[:0:len(buf)]})
+		return &b.segs[len(b.segs)-1], nil
+	}
+	return nil, err
+}
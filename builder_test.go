@@ -0,0 +1,92 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+	"code.hybscloud.com/iox"
+)
+
+func TestBuilder_SpansSegments(t *testing.T) {
+	micro, small := newTieredPools(t)
+	b := iobuf.NewBuilder(micro, small)
+	defer b.Close()
+
+	_, _ = b.WriteString("HTTP/1.1 200 OK\r\n")
+	_ = b.WriteByte('\n')
+	body := bytes.Repeat([]byte("z"), iobuf.BufferSizeMicro)
+	if n, err := b.Write(body); n != len(body) || err != nil {
+		t.Fatalf("Write() = %d, %v", n, err)
+	}
+	if b.Segments() != 2 {
+		t.Fatalf("Segments() = %d, want Micro then Small", b.Segments())
+	}
+	want := append([]byte("HTTP/1.1 200 OK\r\n\n"), body...)
+	if b.Len() != len(want) {
+		t.Fatalf("Len() = %d, want %d", b.Len(), len(want))
+	}
+
+	var got []byte
+	for _, v := range b.IoVec() {
+		got = append(got, unsafe.Slice(v.Base, v.Len)...)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("IoVec() does not describe the written data")
+	}
+}
+
+func TestBuilder_MoveTo(t *testing.T) {
+	micro, small := newTieredPools(t)
+	b := iobuf.NewBuilder(micro, small)
+	data := bytes.Repeat([]byte("abc"), 300)
+	_, _ = b.Write(data)
+
+	var c iobuf.CompositeBuffer
+	b.MoveTo(&c)
+	if b.Len() != 0 || b.Segments() != 0 {
+		t.Errorf("Builder not empty after MoveTo: Len()=%d", b.Len())
+	}
+	got, err := io.ReadAll(&c)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("CompositeBuffer read %d bytes, err=%v", len(got), err)
+	}
+
+	// Reading released the segments; all buffers are back in their pools.
+	for _, pool := range []iobuf.BufferPool{micro, small} {
+		for range pool.Cap() {
+			idx, err := pool.Get()
+			if err != nil {
+				t.Fatalf("segment not returned: %v", err)
+			}
+			defer pool.Put(idx)
+		}
+	}
+}
+
+func TestBuilder_Exhausted(t *testing.T) {
+	pool := iobuf.NewPicoBufferPool(1)
+	pool.Fill(iobuf.NewPicoBuffer)
+	pool.SetNonblock(true)
+	b := iobuf.NewBuilder(pool)
+
+	n, err := b.Write(make([]byte, iobuf.BufferSizePico+1))
+	if n != iobuf.BufferSizePico || err != iox.ErrWouldBlock {
+		t.Fatalf("Write() = %d, %v, want %d, ErrWouldBlock", n, err, iobuf.BufferSizePico)
+	}
+	if b.Len() != n {
+		t.Errorf("Len() = %d, want %d", b.Len(), n)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if _, err := pool.Get(); err != nil {
+		t.Fatalf("segment not returned by Close: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"math/bits"
+	"sync/atomic"
+
+	"code.hybscloud.com/iobuf/internal"
+)
+
+// ByteRing is a single-producer/single-consumer byte ring buffer over one
+// page-aligned memory region.
+//
+// The producer reserves a contiguous window with Claim, fills it in place
+// and publishes it with Commit. The consumer obtains the published bytes
+// with Read and frees them with Consume. Windows never wrap, so they can be
+// handed directly to system calls or io_uring submissions without copying.
+//
+// Exactly one goroutine may act as producer (Claim, Commit) and exactly one
+// as consumer (Read, Consume); the two may run concurrently.
+type ByteRing struct {
+	buf  []byte
+	mask uint64
+
+	_       [internal.CacheLineSize]byte
+	tail    atomic.Uint64 // Published write position, owned by the producer
+	claimed int           // Length of the outstanding Claim window
+
+	_    [internal.CacheLineSize]byte
+	head atomic.Uint64 // Consumed read position, owned by the consumer
+	_    [internal.CacheLineSize]byte
+}
+
+// NewByteRing creates a ByteRing with a capacity of size bytes, rounded up
+// to a power of two. The region is aligned to PageSize.
+// Panics if size < 1.
+func NewByteRing(size int) *ByteRing {
+	if size < 1 {
+		panic("iobuf.NewByteRing: size must be positive")
+	}
+	size = 1 << bits.Len(uint(size-1))
+	return &ByteRing{buf: AlignedMem(size, PageSize), mask: uint64(size - 1)}
+}
+
+// Cap returns the capacity of the ring in bytes.
+func (r *ByteRing) Cap() int { return len(r.buf) }
+
+// Len returns the number of committed bytes not yet consumed.
+func (r *ByteRing) Len() int { return int(r.tail.Load() - r.head.Load()) }
+
+// Claim reserves up to n contiguous bytes for writing and returns them.
+// The window is shorter than n when the ring is nearly full or the free
+// space wraps around the end of the region; it is empty when the ring is
+// full. A new Claim replaces the previous uncommitted one.
+// Producer only. Panics if n is negative.
+func (r *ByteRing) Claim(n int) []byte {
+	if n < 0 {
+		panic("iobuf.ByteRing.Claim: negative count")
+	}
+	tail := r.tail.Load()
+	free := uint64(len(r.buf)) - (tail - r.head.Load())
+	off := tail & r.mask
+	n = int(min(uint64(n), free, uint64(len(r.buf))-off))
+	r.claimed = n
+	return r.buf[off : off+uint64(n) : off+uint64(n)]
+}
+
+// Commit publishes the first n bytes of the last claimed window to the
+// consumer. Producer only. Panics if n is negative or exceeds the claim.
+func (r *ByteRing) Commit(n int) {
+	if n < 0 || n > r.claimed {
+		panic("iobuf.ByteRing.Commit: count exceeds claimed window")
+	}
+	r.claimed = 0
+	r.tail.Add(uint64(n))
+}
+
+// Read returns the contiguous committed bytes at the read position. The
+// window may be shorter than Len when the data wraps around the end of the
+// region; it is empty when the ring is empty. The bytes stay valid until
+// they are consumed. Consumer only.
+func (r *ByteRing) Read() []byte {
+	head := r.head.Load()
+	off := head & r.mask
+	n := min(r.tail.Load()-head, uint64(len(r.buf))-off)
+	return r.buf[off : off+n : off+n]
+}
+
+// Consume frees the first n bytes returned by Read for reuse by the
+// producer. Consumer only. Panics if n is negative or exceeds Len.
+func (r *ByteRing) Consume(n int) {
+	head := r.head.Load()
+	if n < 0 || uint64(n) > r.tail.Load()-head {
+		panic("iobuf.ByteRing.Consume: count exceeds committed data")
+	}
+	r.head.Store(head + uint64(n))
+}
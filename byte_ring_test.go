@@ -0,0 +1,115 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"bytes"
+	"runtime"
+	"sync"
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestByteRing_ClaimCommitRead(t *testing.T) {
+	r := iobuf.NewByteRing(100)
+	if r.Cap() != 128 {
+		t.Fatalf("Cap() = %d, want 128", r.Cap())
+	}
+	w := r.Claim(10)
+	if uintptr(unsafe.Pointer(unsafe.SliceData(w)))%iobuf.PageSize != 0 {
+		t.Error("ring region not page-aligned")
+	}
+	copy(w, "hello")
+	r.Commit(5)
+	if r.Len() != 5 || string(r.Read()) != "hello" {
+		t.Fatalf("Len()=%d Read()=%q", r.Len(), r.Read())
+	}
+	r.Consume(5)
+	if r.Len() != 0 || len(r.Read()) != 0 {
+		t.Error("ring not empty after Consume")
+	}
+}
+
+func TestByteRing_WrapAndFull(t *testing.T) {
+	r := iobuf.NewByteRing(16)
+	r.Claim(12)
+	r.Commit(12)
+	r.Consume(8)
+
+	// Free space wraps: the window stops at the end of the region.
+	if w := r.Claim(16); len(w) != 4 {
+		t.Fatalf("Claim at end = %d bytes, want 4", len(w))
+	}
+	r.Commit(4)
+	if w := r.Claim(16); len(w) != 8 {
+		t.Fatalf("Claim after wrap = %d bytes, want 8", len(w))
+	}
+	r.Commit(8)
+	if w := r.Claim(1); len(w) != 0 {
+		t.Errorf("Claim on full ring = %d bytes, want 0", len(w))
+	}
+	if got := len(r.Read()); got != 8 || r.Len() != 16 {
+		t.Errorf("Read()=%d Len()=%d, want 8 and 16", got, r.Len())
+	}
+}
+
+func TestByteRing_Panics(t *testing.T) {
+	r := iobuf.NewByteRing(16)
+	r.Claim(4)
+	for name, f := range map[string]func(){
+		"Commit beyond claim":   func() { r.Commit(5) },
+		"Consume beyond length": func() { r.Consume(1) },
+		"negative Claim":        func() { r.Claim(-1) },
+		"zero size":             func() { iobuf.NewByteRing(0) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s should panic", name)
+				}
+			}()
+			f()
+		})
+	}
+}
+
+func TestByteRing_Concurrent(t *testing.T) {
+	r := iobuf.NewByteRing(64)
+	const total = 1 << 16
+	src := make([]byte, total)
+	for i := range src {
+		src[i] = byte(i * 7)
+	}
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for off := 0; off < total; {
+			w := r.Claim(total - off)
+			if len(w) == 0 {
+				runtime.Gosched()
+				continue
+			}
+			n := copy(w, src[off:])
+			r.Commit(n)
+			off += n
+		}
+	})
+	dst := make([]byte, 0, total)
+	for len(dst) < total {
+		p := r.Read()
+		if len(p) == 0 {
+			runtime.Gosched()
+			continue
+		}
+		dst = append(dst, p...)
+		r.Consume(len(p))
+	}
+	wg.Wait()
+	if !bytes.Equal(dst, src) {
+		t.Fatal("data corrupted across the ring")
+	}
+}
@@ -0,0 +1,363 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	_ "unsafe" // For go:linkname
+
+	"code.hybscloud.com/iobuf/internal"
+	"code.hybscloud.com/iox"
+	"code.hybscloud.com/spin"
+)
+
+// CachedPool is a two-level indirect pool that fronts a BoundedPool with
+// small shard-local caches of indices.
+//
+// Get and Put first operate on a shard cache and only touch the shared
+// lock-free BoundedPool to refill or flush a batch of indices at a time.
+// This is the classic malloc front-end design: on the hot path a goroutine
+// pays for one uncontended spinlock instead of several CAS operations on
+// cursors shared by every producer and consumer.
+//
+// The number of shards is GOMAXPROCS rounded up to a power of two. A shard
+// is picked by the P (the runtime's logical processor) the calling
+// goroutine runs on, as sync.Pool does, so consecutive calls on one P hit
+// the same cache line. If the shard is momentarily held by a goroutine
+// that migrated off its P, the call falls through to the shared pool
+// rather than waiting.
+//
+// GOMAXPROCS may change while the pool is in use, by runtime.GOMAXPROCS
+// or by the runtime following a changed CPU limit. A call on a P beyond
+// the shards, or a refill or flush after the setting changed, replaces the
+// shards with a set sized for the new GOMAXPROCS. The indices cached in
+// the old shards are returned to the shared pool, from which the new
+// shards refill on demand.
+//
+// Indices cached in a shard are invisible to the shared pool. Get steals
+// from other shards before reporting an empty pool, so no index is ever
+// stranded. Flush returns all cached indices to the shared pool.
+//
+// Blocking behavior follows the underlying pool's SetNonblock mode and
+// WaitStrategy.
+type CachedPool[T BoundedPoolItem] struct {
+	_ noCopy
+
+	pool      *BoundedPool[T]
+	shards    atomic.Pointer[cachedPoolShards]
+	resize    sync.Mutex // Serializes replacing the shards
+	cacheSize int
+	batch     int
+	ready     func() bool // Readiness of Get, see WaitStrategy
+}
+
+// cachedPoolShards is the set of shards sized for one GOMAXPROCS setting.
+type cachedPoolShards struct {
+	shards []cachedPoolShard
+	mask   uint32
+	procs  int // GOMAXPROCS the set was sized for
+}
+
+// cachedPoolShard is a fixed-size stack of indices guarded by a spinlock.
+// Padding keeps adjacent shards on separate cache lines.
+type cachedPoolShard struct {
+	lock    spin.Lock
+	n       int
+	retired bool // Drained by a resize; calls fall through to the shared pool
+	items   []int
+	_       [internal.CacheLineSize]byte
+}
+
+// newCachedPoolShards creates a shard set for procs Ps, with caches
+// holding up to cacheSize indices.
+func newCachedPoolShards(procs, cacheSize int) *cachedPoolShards {
+	n := 1
+	for n < procs {
+		n <<= 1
+	}
+	set := &cachedPoolShards{
+		shards: make([]cachedPoolShard, n),
+		mask:   uint32(n - 1),
+		procs:  procs,
+	}
+	for i := range set.shards {
+		set.shards[i].items = make([]int, cacheSize)
+	}
+	return set
+}
+
+// NewCachedPool creates a CachedPool in front of pool with per-shard caches
+// holding up to cacheSize indices.
+//
+// The pool must be filled before the CachedPool is used. Refills and
+// flushes move cacheSize/2 indices (at least one) at a time.
+//
+// Panics if cacheSize < 1.
+func NewCachedPool[T BoundedPoolItem](pool *BoundedPool[T], cacheSize int) *CachedPool[T] {
+	if cacheSize < 1 {
+		panic("cache size must be positive")
+	}
+	p := &CachedPool[T]{
+		pool:      pool,
+		cacheSize: cacheSize,
+		batch:     max(1, cacheSize/2),
+	}
+	p.shards.Store(newCachedPoolShards(runtime.GOMAXPROCS(0), cacheSize))
+	p.ready = p.readyGet
+	return p
+}
+
+// Pool returns the shared BoundedPool behind the caches.
+func (p *CachedPool[T]) Pool() *BoundedPool[T] {
+	return p.pool
+}
+
+// Value returns the item at the specified indirect index.
+func (p *CachedPool[T]) Value(indirect int) T {
+	return p.pool.Value(indirect)
+}
+
+// SetValue sets the item at the specified indirect index.
+func (p *CachedPool[T]) SetValue(indirect int, value T) {
+	p.pool.SetValue(indirect, value)
+}
+
+// Cap returns the capacity of the shared pool.
+func (p *CachedPool[T]) Cap() int {
+	return p.pool.Cap()
+}
+
+// Get acquires an indirect index, preferring the shard-local cache.
+// Returns iox.ErrWouldBlock if the underlying pool is non-blocking and
+// no index is available in the shared pool or any shard.
+func (p *CachedPool[T]) Get() (indirect int, err error) {
+	var aw iox.Backoff
+	for attempt := 1; ; attempt++ {
+		indirect, ok := p.tryGet()
+		if ok {
+			if p.pool.debug != 0 {
+				p.pool.onGet(indirect)
+			}
+			return indirect, nil
+		}
+		if p.pool.nonblocking {
+			return boundedPoolEntryEmpty, iox.ErrWouldBlock
+		}
+		if w := p.pool.waiter; w != nil {
+			w.strategy.Wait(PoolGet, attempt, p.ready)
+		} else {
+			aw.Wait()
+		}
+	}
+}
+
+// Put returns an indirect index, preferring the shard-local cache.
+// When the cache is full, half of it is flushed to the shared pool first.
+func (p *CachedPool[T]) Put(indirect int) error {
+	if indirect < 0 || indirect >= int(p.pool.capacity) {
+		panic("invalid bounded pool indirect")
+	}
+	if p.pool.debug != 0 {
+		if p.pool.putWouldBlock() {
+			return iox.ErrWouldBlock
+		}
+		p.pool.onPut(indirect)
+	}
+	err := p.put(indirect)
+	if err == nil && p.pool.debug != 0 {
+		p.pool.onPutDone(indirect)
+	}
+	return err
+}
+
+// put is Put without the debug actions.
+func (p *CachedPool[T]) put(indirect int) error {
+	set, s := p.local()
+	if s.lock.Try() {
+		flushed := false
+		if s.n == len(s.items) && !s.retired {
+			p.flush(s, p.batch)
+			flushed = true
+		}
+		if s.n < len(s.items) && !s.retired {
+			s.items[s.n] = indirect
+			s.n++
+			s.lock.Unlock()
+			if flushed {
+				p.checkProcs(set)
+			}
+			// A Get waiting on the empty shared pool is not woken by
+			// an index cached here, so wake it directly.
+			if w := p.pool.waiter; w != nil && p.pool.count.Load() <= 0 {
+				w.strategy.Wake(PoolGet)
+			}
+			return nil
+		}
+		s.lock.Unlock()
+	}
+	return p.pool.put(indirect)
+}
+
+// Flush returns every cached index to the shared pool.
+//
+// Flush is useful before inspecting the shared pool or handing it to code
+// that does not go through the CachedPool.
+func (p *CachedPool[T]) Flush() {
+	set := p.shards.Load()
+	for i := range set.shards {
+		s := &set.shards[i]
+		s.lock.Lock()
+		p.flush(s, s.n)
+		s.lock.Unlock()
+	}
+}
+
+// tryGet makes one pass over the local shard, the shared pool and the
+// remaining shards.
+func (p *CachedPool[T]) tryGet() (indirect int, ok bool) {
+	set, s := p.local()
+	if s.lock.Try() {
+		refilled := false
+		if s.n == 0 && !s.retired {
+			p.refill(s, p.batch)
+			refilled = true
+		}
+		if s.n > 0 {
+			s.n--
+			indirect = s.items[s.n]
+			s.lock.Unlock()
+			if refilled {
+				p.checkProcs(set)
+			}
+			return indirect, true
+		}
+		s.lock.Unlock()
+	}
+	if e, err := p.pool.tryGet(); err == nil {
+		return int(e & uint64(p.pool.mask)), true
+	}
+	start := procID() & set.mask
+	for i := uint32(1); i <= set.mask; i++ {
+		s := &set.shards[(start+i)&set.mask]
+		s.lock.Lock()
+		if s.n > 0 {
+			s.n--
+			indirect = s.items[s.n]
+			s.lock.Unlock()
+			return indirect, true
+		}
+		s.lock.Unlock()
+	}
+	return boundedPoolEntryEmpty, false
+}
+
+// readyGet reports whether an index may be available in the shared pool
+// or a shard. A shard held by another call counts as available: its lock
+// may be held by a call waking the strategy that runs readyGet.
+func (p *CachedPool[T]) readyGet() bool {
+	if p.pool.count.Load() > 0 {
+		return true
+	}
+	set := p.shards.Load()
+	for i := range set.shards {
+		s := &set.shards[i]
+		if !s.lock.Try() {
+			return true
+		}
+		n := s.n
+		s.lock.Unlock()
+		if n > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// local returns the current shard set and the shard of the P the calling
+// goroutine runs on. A P beyond the set means GOMAXPROCS has grown, and
+// the set is resized first.
+func (p *CachedPool[T]) local() (*cachedPoolShards, *cachedPoolShard) {
+	set := p.shards.Load()
+	pid := procID()
+	if pid > set.mask {
+		set = p.resizeShards()
+	}
+	return set, &set.shards[pid&set.mask]
+}
+
+// checkProcs resizes the shards if GOMAXPROCS no longer matches set. It
+// is called on refills and flushes only, since reading GOMAXPROCS takes a
+// runtime lock.
+func (p *CachedPool[T]) checkProcs(set *cachedPoolShards) {
+	if runtime.GOMAXPROCS(0) != set.procs {
+		p.resizeShards()
+	}
+}
+
+// resizeShards replaces the shards with a set sized for the current
+// GOMAXPROCS and returns it. The old shards are drained into the shared
+// pool and retired, so calls still holding the old set fall through to
+// the shared pool instead of caching indices nobody reaches.
+func (p *CachedPool[T]) resizeShards() *cachedPoolShards {
+	p.resize.Lock()
+	defer p.resize.Unlock()
+	old := p.shards.Load()
+	procs := runtime.GOMAXPROCS(0)
+	if procs == old.procs {
+		return old
+	}
+	set := newCachedPoolShards(procs, p.cacheSize)
+	p.shards.Store(set)
+	for i := range old.shards {
+		s := &old.shards[i]
+		s.lock.Lock()
+		// The shared pool has room for every index held outside it.
+		p.flush(s, s.n)
+		s.retired = true
+		s.lock.Unlock()
+	}
+	return set
+}
+
+// procID returns the ID of the P the calling goroutine runs on. The
+// goroutine is unpinned right away; it may migrate before it locks the
+// shard, which the shard lock tolerates.
+func procID() uint32 {
+	pid := runtime_procPin()
+	runtime_procUnpin()
+	return uint32(pid)
+}
+
+//go:linkname runtime_procPin runtime.procPin
+func runtime_procPin() int
+
+//go:linkname runtime_procUnpin runtime.procUnpin
+func runtime_procUnpin()
+
+// refill moves up to n indices from the shared pool into the shard.
+// The caller must hold the shard lock.
+func (p *CachedPool[T]) refill(s *cachedPoolShard, n int) {
+	for ; n > 0 && s.n < len(s.items); n-- {
+		e, err := p.pool.tryGet()
+		if err != nil {
+			return
+		}
+		s.items[s.n] = int(e & uint64(p.pool.mask))
+		s.n++
+	}
+}
+
+// flush moves up to n indices from the shard into the shared pool.
+// The caller must hold the shard lock.
+func (p *CachedPool[T]) flush(s *cachedPoolShard, n int) {
+	for ; n > 0 && s.n > 0; n-- {
+		if p.pool.tryPut(uint64(s.items[s.n-1])) != nil {
+			return
+		}
+		s.n--
+	}
+}
@@ -0,0 +1,230 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+	"code.hybscloud.com/iox"
+)
+
+func TestCachedPool_BasicGetPut(t *testing.T) {
+	const capacity = 64
+	pool := iobuf.NewBoundedPool[int](capacity)
+	pool.Fill(func() int { return 0 })
+	cached := iobuf.NewCachedPool(pool, 8)
+
+	if cached.Cap() != capacity {
+		t.Fatalf("Cap() = %d, want %d", cached.Cap(), capacity)
+	}
+
+	seen := make(map[int]bool, capacity)
+	for i := range capacity {
+		idx, err := cached.Get()
+		if err != nil {
+			t.Fatalf("Get() failed at iteration %d: %v", i, err)
+		}
+		if seen[idx] {
+			t.Fatalf("Get() returned duplicate index %d", idx)
+		}
+		seen[idx] = true
+	}
+	for idx := range seen {
+		if err := cached.Put(idx); err != nil {
+			t.Fatalf("Put(%d) failed: %v", idx, err)
+		}
+	}
+}
+
+func TestCachedPool_NonblockingEmpty(t *testing.T) {
+	pool := iobuf.NewBoundedPool[int](4)
+	pool.Fill(func() int { return 0 })
+	pool.SetNonblock(true)
+	cached := iobuf.NewCachedPool(pool, 2)
+
+	for range 4 {
+		if _, err := cached.Get(); err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+	}
+	if _, err := cached.Get(); err != iox.ErrWouldBlock {
+		t.Fatalf("Get() on empty pool: got %v, want ErrWouldBlock", err)
+	}
+}
+
+func TestCachedPool_Flush(t *testing.T) {
+	const capacity = 32
+	pool := iobuf.NewBoundedPool[int](capacity)
+	pool.Fill(func() int { return 0 })
+	pool.SetNonblock(true)
+	cached := iobuf.NewCachedPool(pool, 16)
+
+	indices := make([]int, 0, capacity)
+	for range capacity {
+		idx, err := cached.Get()
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		indices = append(indices, idx)
+	}
+	for _, idx := range indices {
+		if err := cached.Put(idx); err != nil {
+			t.Fatalf("Put(%d) failed: %v", idx, err)
+		}
+	}
+
+	cached.Flush()
+	for i := range capacity {
+		if _, err := pool.Get(); err != nil {
+			t.Fatalf("shared pool Get() after Flush failed at %d: %v", i, err)
+		}
+	}
+}
+
+func TestCachedPool_ValueSetValue(t *testing.T) {
+	pool := iobuf.NewBoundedPool[int](4)
+	pool.Fill(func() int { return 0 })
+	cached := iobuf.NewCachedPool(pool, 2)
+
+	idx, err := cached.Get()
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	cached.SetValue(idx, 42)
+	if got := cached.Value(idx); got != 42 {
+		t.Errorf("Value(%d) = %d, want 42", idx, got)
+	}
+	if cached.Pool() != pool {
+		t.Error("Pool() should return the shared pool")
+	}
+	_ = cached.Put(idx)
+}
+
+func TestCachedPool_Concurrent(t *testing.T) {
+	const (
+		capacity   = 64
+		goroutines = 16
+		iterations = 2000
+	)
+	pool := iobuf.NewBoundedPool[int](capacity)
+	pool.Fill(func() int { return 0 })
+	cached := iobuf.NewCachedPool(pool, 4)
+
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Go(func() {
+			for range iterations {
+				idx, err := cached.Get()
+				if err != nil {
+					t.Errorf("Get() failed: %v", err)
+					return
+				}
+				if err := cached.Put(idx); err != nil {
+					t.Errorf("Put() failed: %v", err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	// Every index must still be reachable.
+	pool.SetNonblock(true)
+	for i := range capacity {
+		if _, err := cached.Get(); err != nil {
+			t.Fatalf("Get() after stress failed at %d: %v", i, err)
+		}
+	}
+}
+
+func TestCachedPool_StatsBalance(t *testing.T) {
+	const capacity = 16
+	pool := iobuf.NewBoundedPool[int](capacity)
+	pool.Fill(func() int { return 0 })
+	pool.SetStats(true)
+	var puts int
+	pool.SetHooks(&iobuf.PoolHooks{OnPut: func(int) { puts++ }})
+	cached := iobuf.NewCachedPool(pool, 8)
+
+	for range 3 {
+		indices := make([]int, 0, capacity)
+		for range capacity {
+			idx, err := cached.Get()
+			if err != nil {
+				t.Fatalf("Get() failed: %v", err)
+			}
+			indices = append(indices, idx)
+		}
+		for _, idx := range indices {
+			if err := cached.Put(idx); err != nil {
+				t.Fatalf("Put(%d) failed: %v", idx, err)
+			}
+		}
+	}
+
+	s := pool.Stats()
+	if s.Gets != 3*capacity || s.Puts != s.Gets {
+		t.Errorf("Gets = %d, Puts = %d, want both %d", s.Gets, s.Puts, 3*capacity)
+	}
+	if puts != 3*capacity {
+		t.Errorf("OnPut called %d times, want %d", puts, 3*capacity)
+	}
+}
+
+func TestCachedPool_InvalidCacheSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewCachedPool(0) should panic")
+		}
+	}()
+	pool := iobuf.NewBoundedPool[int](4)
+	iobuf.NewCachedPool(pool, 0)
+}
+
+func TestCachedPool_GOMAXPROCSChange(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	const capacity = 64
+	pool := iobuf.NewBoundedPool[int](capacity)
+	pool.Fill(func() int { return 0 })
+	pool.SetStats(true)
+
+	runtime.GOMAXPROCS(1)
+	cached := iobuf.NewCachedPool(pool, 8)
+	churn := func(goroutines int) {
+		var wg sync.WaitGroup
+		for range goroutines {
+			wg.Go(func() {
+				for range 500 {
+					idx, err := cached.Get()
+					if err != nil {
+						t.Errorf("Get() failed: %v", err)
+						return
+					}
+					if err := cached.Put(idx); err != nil {
+						t.Errorf("Put() failed: %v", err)
+						return
+					}
+				}
+			})
+		}
+		wg.Wait()
+	}
+	churn(4)
+
+	// Growing runs goroutines on Ps beyond the original shard; shrinking
+	// leaves shards no P maps to. Neither may lose an index.
+	for _, procs := range []int{8, 2, 1} {
+		runtime.GOMAXPROCS(procs)
+		churn(8)
+		cached.Flush()
+		if s := pool.Stats(); s.Available != capacity || s.Gets != s.Puts {
+			t.Fatalf("GOMAXPROCS=%d: %d of %d indices in the shared pool, %d Gets, %d Puts",
+				procs, s.Available, capacity, s.Gets, s.Puts)
+		}
+	}
+}
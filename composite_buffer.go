@@ -0,0 +1,137 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"io"
+	"unsafe"
+)
+
+// CompositeBuffer links several checked-out pool buffers into one logical
+// byte stream, like a socket buffer chain.
+//
+// Messages larger than one tier buffer are kept as a chain of segments
+// instead of being concatenated by copying. The stream can be consumed
+// through io.Reader or handed to vectored I/O through IoVec. Segments are
+// returned to their pools as soon as they are fully read, and Close
+// returns the rest.
+//
+// A CompositeBuffer must not be copied after first use or used from
+// multiple goroutines concurrently.
+type CompositeBuffer struct {
+	_ noCopy
+
+	segs []compositeSegment
+	head int // First segment with unread data
+	off  int // Read offset within segs[head]
+	n    int // Total unread bytes
+}
+
+// compositeSegment is one pooled buffer of a CompositeBuffer.
+type compositeSegment struct {
+	pool     BufferPool
+	indirect int
+	data     []byte // Valid bytes of the buffer
+}
+
+// Append adds the first n bytes of the buffer at the given indirect index
+// of pool to the end of the stream. Ownership of the index passes to the
+// CompositeBuffer. Panics if n is negative or larger than the buffer.
+func (c *CompositeBuffer) Append(pool BufferPool, indirect int, n int) {
+	buf := pool.Bytes(indirect)
+	if n < 0 || n > len(buf) {
+		panic("iobuf.CompositeBuffer.Append: length out of range")
+	}
+	c.segs = append(c.segs, compositeSegment{pool: pool, indirect: indirect, data: buf[:n]})
+	c.n += n
+}
+
+// AppendBuf adds the unread data of b to the end of the stream and takes
+// over its buffer; b is left empty and must not be closed.
+func (c *CompositeBuffer) AppendBuf(b *Buf) {
+	c.segs = append(c.segs, compositeSegment{pool: b.pool, indirect: b.indirect, data: b.buf[b.r:b.w]})
+	c.n += b.w - b.r
+	*b = Buf{}
+}
+
+// Len returns the number of unread bytes across all segments.
+func (c *CompositeBuffer) Len() int { return c.n }
+
+// Segments returns the number of segments still held.
+func (c *CompositeBuffer) Segments() int { return len(c.segs) - c.head }
+
+// Read reads up to len(p) bytes from the stream into p. Segments that are
+// fully consumed are returned to their pools. It returns io.EOF when the
+// stream is empty and len(p) > 0.
+func (c *CompositeBuffer) Read(p []byte) (n int, err error) {
+	if c.n == 0 {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	for n < len(p) && c.n > 0 {
+		seg := &c.segs[c.head]
+		m := copy(p[n:], seg.data[c.off:])
+		n += m
+		c.off += m
+		c.n -= m
+		if c.off == len(seg.data) {
+			if err = c.releaseHead(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// IoVec returns an IoVec slice describing the unread data, one element per
+// segment, for writev or io_uring submission. The vector stays valid until
+// the stream is read or closed.
+func (c *CompositeBuffer) IoVec() []IoVec {
+	if c.n == 0 {
+		return nil
+	}
+	vec := make([]IoVec, 0, len(c.segs)-c.head)
+	for i := c.head; i < len(c.segs); i++ {
+		data := c.segs[i].data
+		if i == c.head {
+			data = data[c.off:]
+		}
+		if len(data) == 0 {
+			continue
+		}
+		vec = append(vec, IoVec{Base: unsafe.SliceData(data), Len: uint64(len(data))})
+	}
+	return vec
+}
+
+// Close returns every remaining segment to its pool and empties the
+// stream. The CompositeBuffer can be reused afterwards.
+func (c *CompositeBuffer) Close() error {
+	for c.head < len(c.segs) {
+		if err := c.releaseHead(); err != nil {
+			return err
+		}
+	}
+	c.segs = c.segs[:0]
+	c.head, c.off, c.n = 0, 0, 0
+	return nil
+}
+
+// releaseHead returns the head segment to its pool and advances to the next.
+func (c *CompositeBuffer) releaseHead() error {
+	seg := &c.segs[c.head]
+	if seg.pool != nil {
+		if err := seg.pool.Put(seg.indirect); err != nil {
+			return err
+		}
+	}
+	c.n -= len(seg.data) - c.off
+	*seg = compositeSegment{}
+	c.head++
+	c.off = 0
+	return nil
+}
@@ -0,0 +1,113 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"io"
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestCompositeBuffer_ReadAcrossSegments(t *testing.T) {
+	pool := iobuf.NewPicoBufferPool(4)
+	pool.Fill(iobuf.NewPicoBuffer)
+	pool.SetNonblock(true)
+
+	var c iobuf.CompositeBuffer
+	for _, s := range []string{"hello ", "composite ", "world"} {
+		b, err := iobuf.AcquireBuf(pool)
+		if err != nil {
+			t.Fatalf("AcquireBuf() failed: %v", err)
+		}
+		_, _ = b.Write([]byte(s))
+		c.AppendBuf(&b)
+	}
+	if c.Len() != 21 || c.Segments() != 3 {
+		t.Fatalf("Len()=%d Segments()=%d", c.Len(), c.Segments())
+	}
+
+	vec := c.IoVec()
+	if len(vec) != 3 || vec[1].Len != 10 {
+		t.Fatalf("IoVec() = %+v", vec)
+	}
+	if got := string(unsafe.Slice(vec[2].Base, vec[2].Len)); got != "world" {
+		t.Errorf("vec[2] = %q, want %q", got, "world")
+	}
+
+	p := make([]byte, 8)
+	n, _ := c.Read(p)
+	if string(p[:n]) != "hello co" {
+		t.Errorf("first Read = %q", p[:n])
+	}
+	// The first segment was fully consumed and returned to the pool.
+	if c.Segments() != 2 {
+		t.Errorf("Segments() after consuming first = %d, want 2", c.Segments())
+	}
+	if vec := c.IoVec(); len(vec) != 2 || vec[0].Len != 8 {
+		t.Errorf("IoVec() after partial read = %+v", vec)
+	}
+
+	rest, err := io.ReadAll(&c)
+	if err != nil || string(rest) != "mposite world" {
+		t.Errorf("ReadAll() = %q, %v", rest, err)
+	}
+	if c.Len() != 0 || c.IoVec() != nil {
+		t.Errorf("drained stream: Len()=%d IoVec()=%v", c.Len(), c.IoVec())
+	}
+	if n, err := c.Read(nil); n != 0 || err != nil {
+		t.Errorf("Read(nil) = %d, %v", n, err)
+	}
+
+	for range pool.Cap() {
+		if _, err := pool.Get(); err != nil {
+			t.Fatalf("segments not returned to pool: %v", err)
+		}
+	}
+}
+
+func TestCompositeBuffer_AppendAndClose(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(2)
+	pool.Fill(iobuf.NewNanoBuffer)
+	pool.SetNonblock(true)
+
+	var c iobuf.CompositeBuffer
+	for range 2 {
+		idx, err := pool.Get()
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		c.Append(pool, idx, iobuf.BufferSizeNano)
+	}
+	if c.Len() != 2*iobuf.BufferSizeNano {
+		t.Fatalf("Len() = %d", c.Len())
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if c.Len() != 0 || c.Segments() != 0 {
+		t.Errorf("after Close: Len()=%d Segments()=%d", c.Len(), c.Segments())
+	}
+	for range pool.Cap() {
+		if _, err := pool.Get(); err != nil {
+			t.Fatalf("Close did not return segments: %v", err)
+		}
+	}
+}
+
+func TestCompositeBuffer_AppendOutOfRange(t *testing.T) {
+	pool := iobuf.NewPicoBufferPool(1)
+	pool.Fill(iobuf.NewPicoBuffer)
+	idx, _ := pool.Get()
+
+	var c iobuf.CompositeBuffer
+	defer func() {
+		if recover() == nil {
+			t.Error("Append with n > buffer size should panic")
+		}
+	}()
+	c.Append(pool, idx, iobuf.BufferSizePico+1)
+}
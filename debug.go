@@ -0,0 +1,212 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"hash/maphash"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+)
+
+// DebugFillPattern is the byte written over a buffer on Get when debug
+// fill is enabled with SetDebugFill.
+const DebugFillPattern = 0xA5
+
+// poolDebug is a bit set of diagnostic modes and buffer policies enabled
+// on a BoundedPool. Their actions run only when the set is non-zero,
+// keeping the hot path to a single branch when all are off.
+type poolDebug uint32
+
+const (
+	poolDebugFill      poolDebug = 1 << iota // Overwrite buffers with DebugFillPattern on Get
+	poolDebugChecksum                        // Hash buffers on Put and verify on Get
+	poolZeroOnGet                            // Zero buffers on Get, see SetZeroOnGet
+	poolZeroOnPut                            // Zero buffers on Put, see SetZeroOnPut
+	poolDebugStats                           // Count Get and Put, see SetStats
+	poolDebugHooks                           // Call event hooks, see SetHooks
+	poolDebugLog                             // Log diagnostic events, see SetLogger
+	poolReclaimLeaks                         // Reclaim leaked handles, see SetReclaimLeaks
+	poolDebugStall                           // Report stalled waits, see SetStallHandler
+	poolDebugHold                            // Track hold times, see SetHoldTime
+	poolDebugOwner                           // Record buffer holders, see Holders
+	poolDebugDoublePut                       // Panic on Put of a released index, see SetDebugDoublePut
+	poolDebugTrace                           // Mark waits in execution traces, see SetTrace
+	poolSignal                               // Signal occupancy transitions, see SetNotify and SetWaitStrategy
+
+	// poolDebugWait selects the modes that observe blocking waits.
+	poolDebugWait = poolDebugStats | poolDebugHooks | poolDebugLog | poolDebugStall | poolDebugTrace
+
+	// poolDebugWatch selects the modes that check the length of a wait
+	// while it lasts.
+	poolDebugWatch = poolDebugLog | poolDebugStall
+)
+
+// debugChecksumSeed seeds the hashes recorded by checksum debug mode.
+var debugChecksumSeed = maphash.MakeSeed()
+
+// SetDebugFill enables or disables overwriting every acquired buffer with
+// DebugFillPattern before Get returns it.
+//
+// Use-of-stale-data bugs, such as reading more bytes than the last write
+// produced, otherwise "work" by accident because old payloads linger in
+// recycled buffers. With debug fill enabled they read a recognizable
+// 0xA5 pattern instead.
+//
+// Debug fill is only available for byte-array and []byte items, and must
+// be configured before the pool is shared between goroutines. Panics for
+// other item types.
+func (pool *BoundedPool[T]) SetDebugFill(enabled bool) {
+	if enabled {
+		requireByteItems[T]("debug fill")
+		pool.debug |= poolDebugFill
+	} else {
+		pool.debug &^= poolDebugFill
+	}
+}
+
+// SetDebugChecksum enables or disables use-after-Put detection.
+//
+// When enabled, Put records a hash of the buffer contents and the next Get
+// of the same index verifies it before handing the buffer out. A mismatch
+// means the buffer was written through a stale indirect index after it was
+// released, and Get panics with the offending index instead of letting the
+// corruption surface later as a heisenbug.
+//
+// Hashing costs a pass over the buffer on every Put and Get, so checksum
+// mode is meant for tests and debug builds. It is only available for
+// byte-array and []byte items, and must be configured before the pool is
+// shared between goroutines. Panics for other item types.
+func (pool *BoundedPool[T]) SetDebugChecksum(enabled bool) {
+	if enabled {
+		requireByteItems[T]("debug checksum")
+		pool.sums = make([]uint64, pool.capacity)
+		pool.debug |= poolDebugChecksum
+	} else {
+		pool.debug &^= poolDebugChecksum
+		pool.sums = nil
+	}
+}
+
+// Index states recorded by double-Put detection.
+const (
+	debugIndexUnknown uint32 = iota // Held before detection was enabled
+	debugIndexHeld
+	debugIndexFree
+)
+
+// SetDebugDoublePut enables or disables double-Put detection.
+//
+// When enabled, the pool remembers which indices it holds, and a Put of an
+// index that is already back in the pool panics with the index. A double
+// Put otherwise corrupts the pool silently: the index is handed out twice
+// and two holders share one buffer. Indices held when detection is enabled
+// are not checked on their next Put. Detection must be configured before
+// the pool is shared between goroutines.
+func (pool *BoundedPool[T]) SetDebugDoublePut(enabled bool) {
+	if enabled {
+		pool.states = make([]atomic.Uint32, pool.capacity)
+		if len(pool.items) == int(pool.capacity) {
+			h, t := pool.head.Load(), pool.tail.Load()
+			for c := h; c != t; c++ {
+				e := pool.entries[pool.remap(c&pool.mask)].Load()
+				pool.states[e&uint64(pool.mask)].Store(debugIndexFree)
+			}
+		}
+		pool.debug |= poolDebugDoublePut
+	} else {
+		pool.debug &^= poolDebugDoublePut
+		pool.states = nil
+	}
+}
+
+// onGet runs the enabled debug actions and policies for an index just
+// acquired.
+func (pool *BoundedPool[T]) onGet(indirect int) {
+	if pool.debug&poolDebugChecksum != 0 {
+		sum := pool.sums[indirect]
+		pool.sums[indirect] = 0
+		if sum != 0 && debugChecksum(pool.itemBytes(indirect)) != sum {
+			panic("iobuf: buffer " + strconv.Itoa(indirect) + " modified after Put")
+		}
+	}
+	if pool.debug&poolZeroOnGet != 0 {
+		MemZero(pool.itemBytes(indirect))
+	}
+	if pool.debug&poolDebugStats != 0 {
+		pool.stats.gets.Add(1)
+	}
+	if pool.debug&poolDebugFill != 0 {
+		buf := pool.itemBytes(indirect)
+		for i := range buf {
+			buf[i] = DebugFillPattern
+		}
+	}
+	if pool.debug&poolDebugHold != 0 {
+		pool.holdGet(indirect)
+	}
+	if pool.debug&poolDebugOwner != 0 {
+		pool.owners.acquire(indirect, 2)
+	}
+	if pool.debug&poolDebugDoublePut != 0 {
+		pool.states[indirect].Store(debugIndexHeld)
+	}
+	if pool.debug&poolDebugHooks != 0 && pool.hooks.OnGet != nil {
+		pool.hooks.OnGet(indirect)
+	}
+}
+
+// onPut runs the enabled debug actions and policies for an index about to
+// be released.
+func (pool *BoundedPool[T]) onPut(indirect int) {
+	if indirect < 0 || indirect >= int(pool.capacity) {
+		panic("invalid bounded pool indirect")
+	}
+	if pool.debug&poolDebugDoublePut != 0 && pool.states[indirect].Swap(debugIndexFree) == debugIndexFree {
+		panic("iobuf: buffer " + strconv.Itoa(indirect) + " put twice")
+	}
+	if pool.debug&poolZeroOnPut != 0 {
+		MemZero(pool.itemBytes(indirect))
+	}
+	if pool.debug&poolDebugChecksum != 0 {
+		pool.sums[indirect] = debugChecksum(pool.itemBytes(indirect))
+	}
+	if pool.debug&poolDebugHold != 0 {
+		pool.holdPut(indirect)
+	}
+	if pool.debug&poolDebugOwner != 0 {
+		pool.owners.release(indirect)
+	}
+}
+
+// onPutDone runs the enabled actions for an index just released.
+func (pool *BoundedPool[T]) onPutDone(indirect int) {
+	if pool.debug&poolDebugStats != 0 {
+		pool.stats.puts.Add(1)
+	}
+	if pool.debug&poolDebugHooks != 0 && pool.hooks.OnPut != nil {
+		pool.hooks.OnPut(indirect)
+	}
+}
+
+// debugChecksum hashes buf for checksum debug mode. Zero is reserved for
+// "no checksum recorded", so a zero hash is mapped to one.
+func debugChecksum(buf []byte) uint64 {
+	return max(maphash.Bytes(debugChecksumSeed, buf), 1)
+}
+
+// requireByteItems panics unless T is a byte array or a byte slice.
+// Debug modes that rewrite or hash item memory would corrupt other types.
+func requireByteItems[T any](mode string) {
+	if !isByteItems[T]() {
+		panic("iobuf: " + mode + " requires byte buffer items, got " + reflect.TypeFor[T]().String())
+	}
+}
+
+// isByteItems reports whether T is a byte array or byte slice.
+func isByteItems[T any]() bool {
+	t := reflect.TypeFor[T]()
+	return (t.Kind() == reflect.Array || t.Kind() == reflect.Slice) && t.Elem().Kind() == reflect.Uint8
+}
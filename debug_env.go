@@ -0,0 +1,105 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// debugEnv holds the diagnostic modes requested by the IOBUFDEBUG
+// environment variable, parsed once at program start.
+var debugEnv = parseDebugEnv(os.Getenv("IOBUFDEBUG"))
+
+// debugSettings are diagnostic modes applied to every new BoundedPool.
+type debugSettings struct {
+	fill      bool          // SetDebugFill, for byte buffer pools
+	checksum  bool          // SetDebugChecksum, for byte buffer pools
+	doublePut bool          // SetDebugDoublePut
+	stats     bool          // SetStats
+	holdTime  bool          // SetHoldTime
+	reclaim   bool          // SetReclaimLeaks
+	trace     bool          // SetTrace
+	stall     time.Duration // SetStallHandler logging to slog.Default, if positive
+}
+
+// parseDebugEnv parses a comma-separated list of name=value settings in
+// the style of GODEBUG. Boolean settings take 1 or 0; stall takes a
+// duration. Unknown names and malformed values are ignored.
+func parseDebugEnv(env string) (s debugSettings) {
+	for field := range strings.SplitSeq(env, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		on := value == "1"
+		switch name {
+		case "fill":
+			s.fill = on
+		case "checksum":
+			s.checksum = on
+		case "doubleput":
+			s.doublePut = on
+		case "stats":
+			s.stats = on
+		case "holdtime":
+			s.holdTime = on
+		case "reclaim":
+			s.reclaim = on
+		case "trace":
+			s.trace = on
+		case "stall":
+			if d, err := time.ParseDuration(value); err == nil && d > 0 {
+				s.stall = d
+			}
+		}
+	}
+	return s
+}
+
+// applyDebugEnv enables the modes requested by IOBUFDEBUG on a new pool.
+// Byte-only modes are skipped for pools of other item types.
+func (pool *BoundedPool[T]) applyDebugEnv(s debugSettings) {
+	if isByteItems[T]() {
+		if s.fill {
+			pool.SetDebugFill(true)
+		}
+		if s.checksum {
+			pool.SetDebugChecksum(true)
+		}
+	}
+	if s.doublePut {
+		pool.SetDebugDoublePut(true)
+	}
+	if s.stats {
+		pool.SetStats(true)
+	}
+	if s.holdTime {
+		pool.SetHoldTime(true)
+	}
+	if s.reclaim {
+		pool.SetReclaimLeaks(true)
+	}
+	if s.trace {
+		pool.SetTrace(true)
+	}
+	if s.stall > 0 {
+		pool.SetStallHandler(s.stall, logStall)
+	}
+}
+
+// logStall reports a stall to the default logger.
+func logStall(s PoolStall) {
+	attrs := []slog.Attr{
+		slog.String("op", s.Op.String()),
+		slog.Duration("waited", s.Waited),
+		slog.Int("capacity", s.Stats.Capacity),
+		slog.Int("available", s.Stats.Available),
+	}
+	if s.Stats.Name != "" {
+		attrs = append(attrs, slog.String("pool", s.Stats.Name))
+	}
+	slog.LogAttrs(context.Background(), slog.LevelWarn, "iobuf: pool stalled", attrs...)
+}
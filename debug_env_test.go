@@ -0,0 +1,50 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDebugEnv(t *testing.T) {
+	tests := []struct {
+		env  string
+		want debugSettings
+	}{
+		{"", debugSettings{}},
+		{"fill=1,checksum=1", debugSettings{fill: true, checksum: true}},
+		{"doubleput=1, stats=1 ,holdtime=1,reclaim=1", debugSettings{doublePut: true, stats: true, holdTime: true, reclaim: true}},
+		{"trace=1", debugSettings{trace: true}},
+		{"stall=250ms", debugSettings{stall: 250 * time.Millisecond}},
+		{"fill=1,fill=0", debugSettings{}},
+		{"stall=soon,bogus=1,checksum", debugSettings{}},
+	}
+	for _, tt := range tests {
+		if got := parseDebugEnv(tt.env); got != tt.want {
+			t.Errorf("parseDebugEnv(%q) = %+v, want %+v", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestBoundedPool_applyDebugEnv(t *testing.T) {
+	s := parseDebugEnv("fill=1,checksum=1,doubleput=1,stats=1,stall=1s")
+	bytes := NewBoundedPool[NanoBuffer](2)
+	bytes.applyDebugEnv(s)
+	want := poolDebugFill | poolDebugChecksum | poolDebugDoublePut | poolDebugStats | poolDebugStall
+	if bytes.debug&want != want {
+		t.Errorf("byte pool debug = %b, want %b set", bytes.debug, want)
+	}
+
+	// Byte-only modes are skipped rather than panicking.
+	ints := NewBoundedPool[int](2)
+	ints.applyDebugEnv(s)
+	if ints.debug&(poolDebugFill|poolDebugChecksum) != 0 {
+		t.Errorf("int pool debug = %b, want no byte-only modes", ints.debug)
+	}
+	if ints.debug&poolDebugDoublePut == 0 || ints.stall.threshold != time.Second {
+		t.Errorf("int pool debug = %b, stall = %+v", ints.debug, ints.stall)
+	}
+}
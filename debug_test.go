@@ -0,0 +1,168 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"bytes"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestBoundedPool_DebugFill(t *testing.T) {
+	pool := iobuf.NewMicroBufferPool(1)
+	pool.Fill(iobuf.NewMicroBuffer)
+	pool.SetDebugFill(true)
+
+	idx, err := pool.Get()
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	want := bytes.Repeat([]byte{iobuf.DebugFillPattern}, iobuf.BufferSizeMicro)
+	if !bytes.Equal(pool.Bytes(idx), want) {
+		t.Fatal("fresh buffer not filled with DebugFillPattern")
+	}
+
+	// Stale payload from the previous holder is overwritten.
+	copy(pool.Bytes(idx), "stale payload")
+	_ = pool.Put(idx)
+	idx, _ = pool.Get()
+	if !bytes.Equal(pool.Bytes(idx), want) {
+		t.Error("recycled buffer still contains stale data")
+	}
+	_ = pool.Put(idx)
+
+	pool.SetDebugFill(false)
+	idx, _ = pool.Get()
+	copy(pool.Bytes(idx), "kept")
+	_ = pool.Put(idx)
+	idx, _ = pool.Get()
+	if string(pool.Bytes(idx)[:4]) != "kept" {
+		t.Error("debug fill still active after disabling")
+	}
+}
+
+func TestCachedPool_DebugFill(t *testing.T) {
+	pool := iobuf.NewBoundedPool[[]byte](4)
+	pool.Fill(func() []byte { return make([]byte, 16) })
+	pool.SetDebugFill(true)
+	cached := iobuf.NewCachedPool(pool, 2)
+
+	idx, err := cached.Get()
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if got := pool.Bytes(idx)[15]; got != iobuf.DebugFillPattern {
+		t.Errorf("CachedPool.Get() buffer byte = %#x, want %#x", got, iobuf.DebugFillPattern)
+	}
+}
+
+func TestBoundedPool_DebugFillRejectsNonBufferItems(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("SetDebugFill on a pool of *int should panic")
+		}
+	}()
+	iobuf.NewBoundedPool[*int](2).SetDebugFill(true)
+}
+
+func TestBoundedPool_DebugChecksum(t *testing.T) {
+	pool := iobuf.NewSmallBufferPool(1)
+	pool.Fill(iobuf.NewSmallBuffer)
+	pool.SetDebugChecksum(true)
+
+	idx, _ := pool.Get()
+	buf := pool.Bytes(idx)
+	copy(buf, "payload")
+	_ = pool.Put(idx)
+
+	// Untouched buffers pass verification.
+	idx, _ = pool.Get()
+	_ = pool.Put(idx)
+
+	buf[0] = 'X' // write through a stale reference after Put
+	defer func() {
+		if recover() == nil {
+			t.Error("Get() after use-after-Put write should panic")
+		}
+	}()
+	_, _ = pool.Get()
+}
+
+func TestBoundedPool_DebugChecksumDisabled(t *testing.T) {
+	pool := iobuf.NewPicoBufferPool(1)
+	pool.Fill(iobuf.NewPicoBuffer)
+	pool.SetDebugChecksum(true)
+	pool.SetDebugChecksum(false)
+
+	idx, _ := pool.Get()
+	buf := pool.Bytes(idx)
+	_ = pool.Put(idx)
+	buf[0] = 'X'
+	if _, err := pool.Get(); err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+}
+
+func TestCachedPool_DebugChecksum(t *testing.T) {
+	pool := iobuf.NewBoundedPool[[]byte](4)
+	pool.Fill(func() []byte { return make([]byte, 16) })
+	pool.SetDebugChecksum(true)
+	cached := iobuf.NewCachedPool(pool, 4)
+
+	idx, _ := cached.Get()
+	buf := pool.Bytes(idx)
+	_ = cached.Put(idx)
+	buf[3] = 'X'
+
+	defer func() {
+		if recover() == nil {
+			t.Error("CachedPool.Get() should detect use-after-Put write")
+		}
+	}()
+	for range pool.Cap() {
+		if _, err := cached.Get(); err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+	}
+}
+
+func TestBoundedPool_DebugChecksumRequiresBytes(t *testing.T) {
+	pool := iobuf.NewBoundedPool[int](4)
+	defer func() {
+		if recover() == nil {
+			t.Error("SetDebugChecksum on non-byte items should panic")
+		}
+	}()
+	pool.SetDebugChecksum(true)
+}
+
+func TestBoundedPool_DebugDoublePut(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(4)
+	pool.Fill(iobuf.NewNanoBuffer)
+	early, _ := pool.Get() // held before detection: not checked
+	pool.SetDebugDoublePut(true)
+
+	idx, _ := pool.Get()
+	_ = pool.Put(idx)
+	_ = pool.Put(early)
+
+	// Putting an index that never left the pool is caught as well.
+	free := (idx + 1) % pool.Cap()
+	if free == early {
+		free = (free + 1) % pool.Cap()
+	}
+	tests := map[string]int{"put twice": idx, "never acquired": free}
+	for name, indirect := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Put(%d) should panic", indirect)
+				}
+			}()
+			_ = pool.Put(indirect)
+		})
+	}
+}
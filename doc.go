@@ -37,11 +37,42 @@
 // the algorithm from "A Scalable, Portable, and Memory-Efficient Lock-Free FIFO
 // Queue" (Ruslan Nikolaev, 2019). Key characteristics:
 //
-//   - Lock-free: Uses atomic CAS operations, no mutexes
+//   - Fetch-and-add: Claims ring positions with atomic fetch-and-add, no
+//     mutexes or CAS retry loops; a claim waits only on the partner
+//     operation of the same slot
 //   - Bounded: Fixed capacity rounded to power of two
 //   - Memory-efficient: Single contiguous array, no per-element allocation
 //   - Cache-optimized: Aligned to cache line boundaries to prevent false sharing
 //
+// CachedPool fronts a BoundedPool with small shard-local caches of indices,
+// touching the shared ring only to refill or flush in batches:
+//
+//	cached := NewCachedPool(pool, 32)
+//	idx, err := cached.Get()
+//	cached.Put(idx)
+//
+// NodePool keeps one BoundedPool per NUMA node, bound to node-local memory,
+// and serves each thread from its own node, borrowing from the other nodes
+// only when the local shard runs dry:
+//
+//	pool, err := NewNodePool(1024, NewSmallBuffer)
+//
+// Event loops that wait on epoll or io_uring can attach an eventfd with
+// SetNotify; it is signalled when an empty pool gets an item back, so
+// buffer availability is awaited like socket readiness:
+//
+//	pool.SetNotify(PoolGet, efd)
+//
+// Blocking Get and Put wait with adaptive backoff by default. Pools set
+// up for other workloads select a WaitStrategy instead, such as SpinWait
+// for low-latency loops or ParkWait for batch processing:
+//
+//	pool.SetWaitStrategy(&ParkWait{})
+//
+// A pool with one producer or one consumer, such as a receive pipeline
+// fed by a single reactor, asserts it with SetSingleProducer or
+// SetSingleConsumer, making that side of the ring wait-free.
+//
 // # Indirect Pool Pattern
 //
 // Pools store indices (int) rather than buffer values directly. This enables:
@@ -78,6 +109,24 @@
 //	iovecs := IoVecFromSmallBuffers(buffers)
 //	addr, n := IoVecAddrLen(iovecs)  // Get pointer for syscall
 //
+// # Diagnostics
+//
+// BoundedPool has opt-in diagnostic modes, off by default so the hot path
+// stays a single branch: debug fill, use-after-Put checksums, double-Put
+// detection, statistics, hold-time histograms, leak reclamation, event
+// hooks, logging, execution trace regions and a stall detector. Each is
+// enabled per pool with its Set method, or for every pool created by the
+// process through the IOBUFDEBUG environment variable, a comma-separated
+// list of settings in the style of GODEBUG read once at startup:
+//
+//	IOBUFDEBUG=fill=1,checksum=1,doubleput=1,stall=5s ./server
+//
+// The boolean settings fill, checksum, doubleput, stats, holdtime, reclaim
+// and trace take 1 or 0; fill and checksum only apply to byte buffer pools.
+// stall takes a duration and logs blocking calls exceeding it to the
+// default slog logger. Building with the iobufdebug tag additionally
+// records the holder of every buffer, see BoundedPool.Holders.
+//
 // # Architecture Requirements
 //
 // This package requires a 64-bit CPU architecture (amd64, arm64, riscv64, loong64,
@@ -0,0 +1,107 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// poolDebugNames names the diagnostic modes and policies in DumpState.
+var poolDebugNames = []struct {
+	bit  poolDebug
+	name string
+}{
+	{poolDebugFill, "fill"},
+	{poolDebugChecksum, "checksum"},
+	{poolZeroOnGet, "zero-on-get"},
+	{poolZeroOnPut, "zero-on-put"},
+	{poolDebugStats, "stats"},
+	{poolDebugHooks, "hooks"},
+	{poolDebugLog, "log"},
+	{poolReclaimLeaks, "reclaim"},
+	{poolDebugStall, "stall"},
+	{poolDebugHold, "holdtime"},
+	{poolDebugOwner, "owners"},
+	{poolDebugDoublePut, "doubleput"},
+	{poolDebugTrace, "trace"},
+	{poolSignal, "signal"},
+}
+
+// DumpState writes a human-readable snapshot of the pool's ring to w: the
+// head and tail cursors, occupancy, enabled diagnostic modes, and the
+// state of every slot in queue order, from head around to the slot before
+// it. A queued slot holds an item index tagged with the turn of the Put
+// that stored it; every other slot is empty and carries the turn the next
+// Put into it expects.
+//
+// Slots contradicting that layout are marked with "!", and indices queued
+// more than once are listed at the end, which is how a double Put shows.
+// DumpState reads the ring without stopping the pool, so under concurrent
+// use the snapshot can be torn; it is meant for a wedged pool.
+func (pool *BoundedPool[T]) DumpState(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "BoundedPool[%s]: capacity %d", reflect.TypeFor[T](), pool.capacity)
+	if len(pool.items) != int(pool.capacity) {
+		fmt.Fprintf(bw, ", not filled\n")
+		return bw.Flush()
+	}
+	h, t := pool.head.Load(), pool.tail.Load()
+	avail := uint32(min(max(pool.count.Load(), 0), int64(pool.capacity)))
+	fmt.Fprintf(bw, ", head %d, tail %d, available %d, in use %d", h, t, avail, pool.capacity-avail)
+	if pool.nonblocking {
+		fmt.Fprintf(bw, ", nonblocking")
+	}
+	if pool.singleProducer {
+		fmt.Fprintf(bw, ", single-producer")
+	}
+	if pool.singleConsumer {
+		fmt.Fprintf(bw, ", single-consumer")
+	}
+	var modes []string
+	for _, m := range poolDebugNames {
+		if pool.debug&m.bit != 0 {
+			modes = append(modes, m.name)
+		}
+	}
+	if len(modes) > 0 {
+		fmt.Fprintf(bw, ", modes %s", strings.Join(modes, ","))
+	}
+	fmt.Fprintf(bw, "\n%12s %6s  %s\n", "position", "slot", "entry")
+
+	seen := make([]int, pool.capacity)
+	for c := h; c != h+pool.capacity; c++ {
+		slot := pool.remap(c & pool.mask)
+		e := pool.entries[slot].Load()
+		queued := c-h < avail
+		var desc string
+		bad := false
+		if e&boundedPoolEntryEmpty != 0 {
+			turn := uint32(e & boundedPoolEntryTurnMask)
+			desc = fmt.Sprintf("empty, turn %d", turn)
+			bad = queued || turn != (c/pool.capacity)&boundedPoolEntryTurnMask
+		} else {
+			idx := int(e & uint64(pool.mask))
+			turn := uint32(e>>32) & boundedPoolEntryTurnMask
+			seen[idx]++
+			desc = fmt.Sprintf("index %d, turn %d", idx, turn)
+			bad = !queued || turn != (c/pool.capacity)&boundedPoolEntryTurnMask
+		}
+		mark := " "
+		if bad {
+			mark = "!"
+		}
+		fmt.Fprintf(bw, "%12d %6d %s%s\n", c, slot, mark, desc)
+	}
+	for idx, n := range seen {
+		if n > 1 {
+			fmt.Fprintf(bw, "! index %d queued %d times\n", idx, n)
+		}
+	}
+	return bw.Flush()
+}
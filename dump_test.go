@@ -0,0 +1,59 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestBoundedPool_DumpState(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(4)
+	var out strings.Builder
+	_ = pool.DumpState(&out)
+	if !strings.Contains(out.String(), "not filled") {
+		t.Errorf("DumpState() before Fill = %q", out.String())
+	}
+
+	pool.Fill(iobuf.NewNanoBuffer)
+	pool.SetNonblock(true)
+	pool.SetStats(true)
+	idx, _ := pool.Get()
+	out.Reset()
+	if err := pool.DumpState(&out); err != nil {
+		t.Fatal(err)
+	}
+	dump := out.String()
+	for _, want := range []string{
+		"BoundedPool[iobuf.NanoBuffer]: capacity 4, head 1, tail 4, available 3, in use 1, nonblocking, modes stats",
+		"empty, turn 1",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("DumpState() = %q, want it to contain %q", dump, want)
+		}
+	}
+	if strings.Contains(dump, "!") {
+		t.Errorf("healthy pool reported anomalies:\n%s", dump)
+	}
+	_ = pool.Put(idx)
+}
+
+func TestBoundedPool_DumpStateDoublePut(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(4)
+	pool.Fill(iobuf.NewNanoBuffer)
+	a, _ := pool.Get()
+	_, _ = pool.Get()
+	_ = pool.Put(a)
+	_ = pool.Put(a) // the second holder's index is lost, a is queued twice
+
+	var out strings.Builder
+	_ = pool.DumpState(&out)
+	if want := fmt.Sprintf("! index %d queued 2 times", a); !strings.Contains(out.String(), want) {
+		t.Errorf("DumpState() = %q, want it to contain %q", out.String(), want)
+	}
+}
@@ -0,0 +1,97 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+
+	"code.hybscloud.com/iobuf/internal"
+	"code.hybscloud.com/iox"
+	"code.hybscloud.com/spin"
+)
+
+// elimSpins is the number of spin-waits a Get parks in an elimination cell
+// before giving up and backing off.
+const elimSpins = 64
+
+// Elimination cell states. A handed cell holds the index of the Put that
+// filled it.
+const (
+	elimIdle    = 0
+	elimWaiting = 1 << 63
+	elimHanded  = 1 << 62
+)
+
+// elimCell is a rendezvous point between one waiting Get and one Put,
+// padded so neighbouring cells do not share a cache line.
+type elimCell struct {
+	state atomic.Uint64
+	_     [internal.CacheLineSize - 8]byte
+}
+
+// SetElimination enables elimination with the given number of rendezvous
+// cells, or disables it if cells is 0.
+//
+// With elimination enabled, a blocking Get that finds the pool empty
+// first parks briefly in a random cell, and a Put arriving while the pool
+// is empty hands its index directly to a parked Get instead of storing it
+// in the ring. The pair completes without touching the ring cursors, which
+// is where small pools under heavy contention spend their time. A Get that
+// is not met in time falls back to the pool's WaitStrategy. Non-blocking
+// Gets never park.
+//
+// A few cells suffice; more than the number of goroutines contending on
+// the pool only spread Gets and Puts apart. SetElimination must be called
+// before the pool is shared between goroutines. Panics if cells is
+// negative.
+func (pool *BoundedPool[T]) SetElimination(cells int) {
+	if cells < 0 {
+		panic("iobuf.BoundedPool.SetElimination: negative cells")
+	}
+	if cells == 0 {
+		pool.elim = nil
+		return
+	}
+	pool.elim = make([]elimCell, cells)
+}
+
+// eliminateGet parks in a random cell waiting for a Put to hand over an
+// index. Returns ErrWouldBlock if the cell is taken or no Put came.
+func (pool *BoundedPool[T]) eliminateGet() (entry uint64, err error) {
+	cell := &pool.elim[rand.Uint32N(uint32(len(pool.elim)))].state
+	if !cell.CompareAndSwap(elimIdle, elimWaiting) {
+		return boundedPoolEntryEmpty, iox.ErrWouldBlock
+	}
+	sw := spin.Wait{}
+	for range elimSpins {
+		if e := cell.Load(); e != elimWaiting {
+			cell.Store(elimIdle)
+			return e &^ elimHanded, nil
+		}
+		sw.Once()
+	}
+	if cell.CompareAndSwap(elimWaiting, elimIdle) {
+		return boundedPoolEntryEmpty, iox.ErrWouldBlock
+	}
+	// A Put filled the cell after the last check.
+	e := cell.Load()
+	cell.Store(elimIdle)
+	return e &^ elimHanded, nil
+}
+
+// eliminatePut hands e to a parked Get, if there is one.
+func (pool *BoundedPool[T]) eliminatePut(e uint64) bool {
+	for i := range pool.elim {
+		cell := &pool.elim[i].state
+		if cell.Load() == elimWaiting && cell.CompareAndSwap(elimWaiting, elimHanded|e) {
+			if pool.debug&poolDebugStats != 0 {
+				pool.stats.eliminated.Add(1)
+			}
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"code.hybscloud.com/iobuf"
+	"code.hybscloud.com/iox"
+	"code.hybscloud.com/spin"
+)
+
+func TestBoundedPool_Elimination(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(1)
+	pool.Fill(iobuf.NewNanoBuffer)
+	pool.SetStats(true)
+	pool.SetElimination(1)
+
+	// A Put reaches a parked Get only if it lands while the Get spins in
+	// its cell, so retry until one does.
+	for round := 0; pool.Stats().Eliminated == 0; round++ {
+		if round == 1000 {
+			t.Fatal("no Put was handed to a waiting Get")
+		}
+		idx, _ := pool.Get()
+		got := make(chan int)
+		go func() {
+			i, _ := pool.Get()
+			got <- i
+		}()
+		runtime.Gosched()
+		_ = pool.Put(idx)
+		if i := <-got; i != idx {
+			t.Fatalf("Get() = %d, want %d", i, idx)
+		}
+		_ = pool.Put(idx)
+	}
+
+	s := pool.Stats()
+	if s.Available != 1 || s.Gets != s.Puts {
+		t.Errorf("Stats() = %+v, want the item back in the pool", s)
+	}
+}
+
+func TestBoundedPool_EliminationConcurrent(t *testing.T) {
+	const capacity = 4
+	const goroutines = 16
+	const iterations = 500
+
+	pool := iobuf.NewBoundedPool[int](capacity)
+	pool.Fill(func() int { return 0 })
+	pool.SetElimination(2)
+
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Go(func() {
+			for i := range iterations {
+				idx, err := pool.Get()
+				if err != nil {
+					t.Errorf("goroutine %d iteration %d: Get() failed: %v", g, i, err)
+					return
+				}
+				spin.Yield()
+				if err := pool.Put(idx); err != nil {
+					t.Errorf("goroutine %d iteration %d: Put() failed: %v", g, i, err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	// Every index is back in the ring exactly once.
+	pool.SetNonblock(true)
+	seen := make(map[int]bool)
+	for range capacity {
+		idx, err := pool.Get()
+		if err != nil {
+			t.Fatalf("Get() after the run: %v", err)
+		}
+		if seen[idx] {
+			t.Fatalf("index %d returned twice", idx)
+		}
+		seen[idx] = true
+	}
+	if _, err := pool.Get(); err != iox.ErrWouldBlock {
+		t.Errorf("Get() from a drained pool: %v, want ErrWouldBlock", err)
+	}
+}
+
+func TestBoundedPool_EliminationNonblocking(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(1)
+	pool.Fill(iobuf.NewNanoBuffer)
+	pool.SetNonblock(true)
+	pool.SetElimination(1)
+
+	idx, _ := pool.Get()
+	start := time.Now()
+	if _, err := pool.Get(); err != iox.ErrWouldBlock {
+		t.Fatalf("Get() from an empty pool: %v, want ErrWouldBlock", err)
+	}
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Errorf("non-blocking Get() took %v", d)
+	}
+	_ = pool.Put(idx)
+}
+
+func TestBoundedPool_SetEliminationPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("SetElimination(-1) did not panic")
+		}
+	}()
+	iobuf.NewNanoBufferPool(1).SetElimination(-1)
+}
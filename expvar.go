@@ -0,0 +1,62 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "expvar"
+
+// PublishExpvar publishes the stats of every registered pool as the expvar
+// variable prefix, served at /debug/vars by the standard expvar handler.
+//
+// The variable is a JSON object keyed by registered pool name; each value
+// holds the fields of PoolStats. Stats are gathered when the variable is
+// read, so pools registered later show up too. Panics if prefix is already
+// published, like expvar.Publish.
+func PublishExpvar(prefix string) {
+	expvar.Publish(prefix, expvar.Func(func() any {
+		all := RegisteredPoolStats()
+		vars := make(map[string]expvarPoolStats, len(all))
+		for _, s := range all {
+			tier := "none"
+			if s.Tier != TierEnd {
+				tier = s.Tier.String()
+			}
+			vars[s.Name] = expvarPoolStats{
+				Tier:        tier,
+				Capacity:    s.Capacity,
+				Available:   s.Available,
+				InUse:       s.InUse(),
+				Gets:        s.Gets,
+				Puts:        s.Puts,
+				WouldBlocks: s.WouldBlocks,
+				Blocks:      s.Blocks,
+				BlockTimeNs: int64(s.BlockTime),
+				Reclaimed:   s.Reclaimed,
+				Eliminated:  s.Eliminated,
+				GetRetries:  s.GetRetries,
+				PutRetries:  s.PutRetries,
+				Backoffs:    s.Backoffs,
+			}
+		}
+		return vars
+	}))
+}
+
+// expvarPoolStats is the JSON form of PoolStats published by PublishExpvar.
+type expvarPoolStats struct {
+	Tier        string `json:"tier"`
+	Capacity    int    `json:"capacity"`
+	Available   int    `json:"available"`
+	InUse       int    `json:"in_use"`
+	Gets        uint64 `json:"gets"`
+	Puts        uint64 `json:"puts"`
+	WouldBlocks uint64 `json:"would_blocks"`
+	Blocks      uint64 `json:"blocks"`
+	BlockTimeNs int64  `json:"block_time_ns"`
+	Reclaimed   uint64 `json:"reclaimed_leaks"`
+	Eliminated  uint64 `json:"eliminated"`
+	GetRetries  uint64 `json:"get_retries"`
+	PutRetries  uint64 `json:"put_retries"`
+	Backoffs    uint64 `json:"backoffs"`
+}
@@ -0,0 +1,40 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	iobuf.PublishExpvar("iobuf_test")
+	pool := iobuf.NewMicroBufferPool(8)
+	pool.Fill(iobuf.NewMicroBuffer)
+	iobuf.RegisterPool("expvar.micro", pool)
+	defer iobuf.UnregisterPool("expvar.micro")
+	idx, _ := pool.Get()
+	defer pool.Put(idx)
+
+	var vars map[string]struct {
+		Tier     string `json:"tier"`
+		Capacity int    `json:"capacity"`
+		InUse    int    `json:"in_use"`
+		Gets     uint64 `json:"gets"`
+	}
+	if err := json.Unmarshal([]byte(expvar.Get("iobuf_test").String()), &vars); err != nil {
+		t.Fatalf("expvar output is not JSON: %v", err)
+	}
+	got, ok := vars["expvar.micro"]
+	if !ok {
+		t.Fatalf("registered pool missing from %v", vars)
+	}
+	if got.Tier != "Micro" || got.Capacity != 8 || got.InUse != 1 || got.Gets != 1 {
+		t.Errorf("published stats = %+v", got)
+	}
+}
@@ -0,0 +1,25 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "sync"
+
+// Features reports which io_uring buffer registration modes the running
+// kernel supports.
+type Features struct {
+	BuffersSparse      bool // IORING_REGISTER_BUFFERS2 with sparse tables (5.19+)
+	BufRing            bool // Ring-mapped provided buffers, IORING_REGISTER_PBUF_RING (5.19+)
+	BufRingIncremental bool // Incremental consumption, IOU_PBUF_RING_INC (6.12+)
+	CloneBuffers       bool // IORING_REGISTER_CLONE_BUFFERS (6.12+)
+	Zcrx               bool // Zero-copy receive, IORING_OP_RECV_ZC (6.15+)
+}
+
+var kernelFeatures = sync.OnceValues(ProbeFeatures)
+
+// KernelFeatures returns the result of ProbeFeatures, probing on the first
+// call only.
+func KernelFeatures() (Features, error) {
+	return kernelFeatures()
+}
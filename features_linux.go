@@ -0,0 +1,70 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// ProbeFeatures detects which buffer registration modes the kernel
+// supports by trying each of them on a temporary io_uring instance. The
+// error is non-nil only if io_uring itself is unavailable, for example
+// disabled through /proc/sys/kernel/io_uring_disabled or by a seccomp
+// policy. Use KernelFeatures to probe once per process.
+func ProbeFeatures() (Features, error) {
+	ring, err := setupProbeRing()
+	if err != nil {
+		return Features{}, err
+	}
+	defer syscall.Close(ring)
+
+	var f Features
+	if RegisterBuffersSparse(ring, 1) == nil {
+		f.BuffersSparse = true
+		if other, err := setupProbeRing(); err == nil {
+			f.CloneBuffers = CloneBuffers(other, ring) == nil
+			syscall.Close(other)
+		}
+		_ = UnregisterBuffers(ring)
+	}
+	if mem, err := mapRegion(int(PageSize), 0); err == nil {
+		if registerBufRing(ring, mem, 1, 0, false) == nil {
+			f.BufRing = true
+			_ = unregisterBufRing(ring, 0)
+		}
+		if registerBufRing(ring, mem, 1, 1, true) == nil {
+			f.BufRingIncremental = true
+			_ = unregisterBufRing(ring, 1)
+		}
+		_ = unmapRegion(mem)
+	}
+	f.Zcrx = probeOp(ring, ioringOpRecvZC)
+	return f, nil
+}
+
+// setupProbeRing creates a minimal io_uring instance.
+func setupProbeRing() (int, error) {
+	var params [120]byte // struct io_uring_params
+	fd, _, errno := syscall.Syscall(sysIoUringSetup, 1, uintptr(unsafe.Pointer(&params[0])), 0)
+	if errno != 0 {
+		if errno == syscall.ENOSYS {
+			return -1, errors.ErrUnsupported
+		}
+		return -1, os.NewSyscallError("io_uring_setup", errno)
+	}
+	return int(fd), nil
+}
+
+// probeOp reports whether the ring supports opcode op (IORING_REGISTER_PROBE).
+func probeOp(ring int, op uint8) bool {
+	var probe ioUringProbe
+	if ioUringRegister(ring, ioringRegisterProbe, unsafe.Pointer(&probe), uint32(len(probe.ops))) != nil {
+		return false
+	}
+	return op <= probe.lastOp && probe.ops[op].flags&ioUringOpSupported != 0
+}
@@ -0,0 +1,48 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && !mips64 && !mips64le
+
+package iobuf_test
+
+import (
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestKernelFeatures(t *testing.T) {
+	f, err := iobuf.KernelFeatures()
+	if err != nil {
+		t.Skipf("io_uring not available: %v", err)
+	}
+	t.Logf("features: %+v", f)
+	if f.BufRingIncremental && !f.BufRing {
+		t.Error("incremental buffer rings reported without buffer rings")
+	}
+	if f.CloneBuffers && !f.BuffersSparse {
+		t.Error("buffer cloning reported without sparse tables")
+	}
+	if again, _ := iobuf.KernelFeatures(); again != f {
+		t.Error("KernelFeatures() not stable across calls")
+	}
+}
+
+func TestNewBufferGroup(t *testing.T) {
+	ring := newTestRing(t)
+	pool := iobuf.NewSmallBufferPool(4)
+	pool.Fill(iobuf.NewSmallBuffer)
+	g, err := iobuf.NewBufferGroup(ring, pool, 3, func(iobuf.ProvideBuffersOp) error { return nil })
+	if err != nil {
+		t.Fatalf("NewBufferGroup() failed: %v", err)
+	}
+	defer g.Close()
+	f, _ := iobuf.KernelFeatures()
+	if _, ok := g.(*iobuf.BufRing[iobuf.SmallBuffer]); ok != f.BufRing {
+		t.Errorf("NewBufferGroup() = %T with BufRing support %v", g, f.BufRing)
+	}
+	if n, err := g.Replenish(); err != nil || n != 4 {
+		t.Errorf("Replenish() = %d, %v; want 4", n, err)
+	}
+}
@@ -0,0 +1,16 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package iobuf
+
+import "errors"
+
+// ProbeFeatures detects which buffer registration modes the kernel
+// supports. io_uring is only available on Linux; elsewhere it returns
+// errors.ErrUnsupported.
+func ProbeFeatures() (Features, error) {
+	return Features{}, errors.ErrUnsupported
+}
@@ -0,0 +1,54 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// AlignedMemFromFile maps length bytes of f starting at off and returns
+// them as a byte slice, so file contents can be fed straight into IoVec or
+// sendmsg without read copies. The mapping is shared: with writable set,
+// stores reach the file, and changes made to the file through other
+// descriptors are visible in the slice.
+//
+// The mapping starts at the page boundary at or below off, so the slice is
+// page-aligned whenever off is. Accessing pages beyond the end of the file
+// raises SIGBUS. The memory must be released with FreeMemFile.
+// Panics if off < 0 or length < 1.
+func AlignedMemFromFile(f *os.File, off, length int64, writable bool) ([]byte, error) {
+	if off < 0 || length < 1 {
+		panic("iobuf.AlignedMemFromFile: invalid offset or length")
+	}
+	ps := int64(syscall.Getpagesize())
+	base := off &^ (ps - 1)
+	size := off - base + length
+	prot := syscall.PROT_READ
+	if writable {
+		prot |= syscall.PROT_WRITE
+	}
+	mem, err := mmap(int(f.Fd()), base, int(size), prot, syscall.MAP_SHARED)
+	// Keep f from being finalized, closing the descriptor, during mmap.
+	runtime.KeepAlive(f)
+	if err != nil {
+		return nil, err
+	}
+	return mem[off-base:], nil
+}
+
+// FreeMemFile unmaps memory returned by AlignedMemFromFile. The memory must
+// not be used afterwards.
+func FreeMemFile(mem []byte) error {
+	if cap(mem) == 0 {
+		return nil
+	}
+	ps := uintptr(syscall.Getpagesize())
+	p := uintptr(unsafe.Pointer(unsafe.SliceData(mem)))
+	head := p & (ps - 1)
+	return unmapRegion(regionSlice(p-head, int(head)+cap(mem)))
+}
@@ -0,0 +1,60 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestAlignedMemFromFile(t *testing.T) {
+	ps := int(iobuf.PageSize)
+	data := bytes.Repeat([]byte("0123456789abcdef"), 3*ps/16)
+	name := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(name, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(name, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	mem, err := iobuf.AlignedMemFromFile(f, int64(ps), int64(ps), false)
+	if err != nil {
+		t.Fatalf("AlignedMemFromFile() failed: %v", err)
+	}
+	if uintptr(unsafe.Pointer(unsafe.SliceData(mem)))%iobuf.PageSize != 0 {
+		t.Error("mapping at a page offset is not page-aligned")
+	}
+	if !bytes.Equal(mem, data[ps:2*ps]) {
+		t.Error("mapped contents differ from the file")
+	}
+	if err := iobuf.FreeMemFile(mem); err != nil {
+		t.Errorf("FreeMemFile() failed: %v", err)
+	}
+
+	// An unaligned writable view writes through to the file.
+	mem, err = iobuf.AlignedMemFromFile(f, 100, 10, true)
+	if err != nil {
+		t.Fatalf("AlignedMemFromFile(writable) failed: %v", err)
+	}
+	if len(mem) != 10 || !bytes.Equal(mem, data[100:110]) {
+		t.Fatalf("unaligned view = %q, want %q", mem, data[100:110])
+	}
+	copy(mem, "xxxxxxxxxx")
+	if err := iobuf.FreeMemFile(mem); err != nil {
+		t.Errorf("FreeMemFile() failed: %v", err)
+	}
+	got := make([]byte, 10)
+	if _, err := f.ReadAt(got, 100); err != nil || string(got) != "xxxxxxxxxx" {
+		t.Errorf("file after write = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,26 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package iobuf
+
+import (
+	"errors"
+	"os"
+)
+
+// AlignedMemFromFile maps a region of f into memory. It is only supported
+// on Linux; elsewhere it returns errors.ErrUnsupported.
+// Panics if off < 0 or length < 1.
+func AlignedMemFromFile(f *os.File, off, length int64, writable bool) ([]byte, error) {
+	if off < 0 || length < 1 {
+		panic("iobuf.AlignedMemFromFile: invalid offset or length")
+	}
+	return nil, errors.ErrUnsupported
+}
+
+// FreeMemFile releases memory returned by AlignedMemFromFile. File mappings
+// are only supported on Linux, so there is never anything to free.
+func FreeMemFile(mem []byte) error { return nil }
@@ -0,0 +1,65 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "math"
+
+// FixedIoVec is an IoVec paired with the index under which its buffer is
+// registered with io_uring (IORING_REGISTER_BUFFERS). READ_FIXED and
+// WRITE_FIXED submissions need both the address range and the buffer
+// index; carrying them together avoids a separate lookup table.
+type FixedIoVec struct {
+	IoVec
+	BufIndex uint16
+}
+
+// RegisterIoVecs returns the vector to register for pool: element i
+// describes the whole buffer at indirect index i, so the registered buffer
+// index of every pooled buffer equals its indirect index.
+// Panics if the pool is not filled or has more than 65536 buffers.
+func RegisterIoVecs(pool *RegisterBufferPool) []IoVec {
+	if len(pool.items) != int(pool.capacity) {
+		panic("must Fill the pool before using it")
+	}
+	if pool.capacity > math.MaxUint16+1 {
+		panic("iobuf.RegisterIoVecs: too many buffers to register")
+	}
+	return IoVecFromRegisteredBuffers(pool.items)
+}
+
+// FixedIoVecOf returns the FixedIoVec for the first n bytes of the buffer
+// at indirect index in pool, assuming the pool was registered in the order
+// of RegisterIoVecs. The caller must hold the index.
+// Panics if n is negative or exceeds the buffer size.
+func FixedIoVecOf(pool *RegisterBufferPool, indirect int, n int) FixedIoVec {
+	if n < 0 || n > registerBufferSize {
+		panic("iobuf.FixedIoVecOf: length out of range")
+	}
+	buf := pool.itemBytes(indirect)
+	return FixedIoVec{
+		IoVec:    IoVec{Base: &buf[0], Len: uint64(n)},
+		BufIndex: uint16(indirect),
+	}
+}
+
+// FixedIO describes the data side of a READ_FIXED or WRITE_FIXED
+// submission in one value: the registered file index to place in the SQE
+// fd field (with IOSQE_FIXED_FILE), and the registered buffer with the
+// address range inside it.
+type FixedIO struct {
+	FixedIoVec
+	FileIndex uint32
+}
+
+// FixedIOOf returns the FixedIO for registered file fileIndex and the first
+// n bytes of the buffer at indirect index in pool, assuming the pool was
+// registered in the order of RegisterIoVecs. The caller must hold the
+// index. Panics if fileIndex is negative or n is out of range.
+func FixedIOOf(fileIndex int, pool *RegisterBufferPool, indirect int, n int) FixedIO {
+	if fileIndex < 0 || fileIndex > math.MaxInt32 {
+		panic("iobuf.FixedIOOf: file index out of range")
+	}
+	return FixedIO{FixedIoVec: FixedIoVecOf(pool, indirect, n), FileIndex: uint32(fileIndex)}
+}
@@ -0,0 +1,59 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestFixedIoVecOf(t *testing.T) {
+	pool := iobuf.NewRegisterBufferPool(4)
+	pool.Fill(func() iobuf.RegisterBuffer { return iobuf.RegisterBuffer{} })
+	reg := iobuf.RegisterIoVecs(pool)
+	if len(reg) != pool.Cap() {
+		t.Fatalf("RegisterIoVecs() len = %d, want %d", len(reg), pool.Cap())
+	}
+
+	idx, _ := pool.Get()
+	fv := iobuf.FixedIoVecOf(pool, idx, 100)
+	if int(fv.BufIndex) != idx || fv.Len != 100 {
+		t.Errorf("FixedIoVecOf() = {index %d, len %d}, want {%d, 100}", fv.BufIndex, fv.Len, idx)
+	}
+	if fv.Base != reg[idx].Base || fv.Base != &pool.Bytes(idx)[0] {
+		t.Error("FixedIoVec does not point at the registered buffer")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("FixedIoVecOf beyond the buffer size should panic")
+		}
+	}()
+	iobuf.FixedIoVecOf(pool, idx, iobuf.BufferSizeLarge+1)
+}
+
+func TestFixedIOOf(t *testing.T) {
+	pool := iobuf.NewRegisterBufferPool(2)
+	pool.Fill(func() iobuf.RegisterBuffer { return iobuf.RegisterBuffer{} })
+	idx, _ := pool.Get()
+	d := iobuf.FixedIOOf(5, pool, idx, 64)
+	if d.FileIndex != 5 || int(d.BufIndex) != idx || d.Len != 64 || d.Base != &pool.Bytes(idx)[0] {
+		t.Errorf("FixedIOOf() = %+v", d)
+	}
+
+	m := iobuf.NewRegisteredIndexMap(pool, 8)
+	m.Bind(6, idx)
+	if d := m.FixedIOOf(3, idx, 64); d.FileIndex != 3 || d.BufIndex != 6 {
+		t.Errorf("RegisteredIndexMap.FixedIOOf() = file %d, buffer %d; want 3, 6", d.FileIndex, d.BufIndex)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("FixedIOOf with a negative file index should panic")
+		}
+	}()
+	iobuf.FixedIOOf(-1, pool, idx, 64)
+}
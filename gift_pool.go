@@ -0,0 +1,43 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+// GiftPool is a pool of page-aligned buffers mapped outside the Go heap
+// whose pages can be gifted to a pipe with vmsplice (SPLICE_F_GIFT).
+//
+// A gifted page belongs to the pipe until a reader consumes it, possibly
+// by splicing it into a file or socket without a copy, so the memory must
+// never be written again. Gift therefore unmaps a buffer once all of its
+// bytes are in the pipe, which leaves the pages alive for the kernel
+// alone, and puts a freshly mapped buffer under the same index before
+// returning the index to the pool. Heap memory could not be handed over
+// this way: the garbage collector would reuse it while the pipe still
+// references it.
+//
+// Gifting is only supported on Linux.
+type GiftPool struct {
+	_ noCopy
+
+	pool *BoundedPool[[]byte]
+	size int
+	sent []int // Bytes already spliced per index, see Gift
+}
+
+// Pool returns the BoundedPool of buffer indices. Its blocking behavior
+// applies to Get and Put.
+func (p *GiftPool) Pool() *BoundedPool[[]byte] { return p.pool }
+
+// BufferSize returns the size of each buffer, a multiple of the page size.
+func (p *GiftPool) BufferSize() int { return p.size }
+
+// Get acquires a buffer index.
+// Returns iox.ErrWouldBlock if the pool is non-blocking and empty.
+func (p *GiftPool) Get() (indirect int, err error) { return p.pool.Get() }
+
+// Put returns a buffer index that was not gifted.
+func (p *GiftPool) Put(indirect int) error { return p.pool.Put(indirect) }
+
+// Bytes returns the buffer at the given indirect index.
+func (p *GiftPool) Bytes(indirect int) []byte { return p.pool.Value(indirect) }
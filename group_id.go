@@ -0,0 +1,89 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"errors"
+	"math/bits"
+	"sync"
+)
+
+// ErrGroupIDInUse is returned when a buffer group ID is already allocated.
+var ErrGroupIDInUse = errors.New("iobuf: buffer group ID in use")
+
+// ErrGroupIDsExhausted is returned when all 65536 buffer group IDs are
+// allocated.
+var ErrGroupIDsExhausted = errors.New("iobuf: buffer group IDs exhausted")
+
+// GroupIDAllocator hands out io_uring buffer group IDs (bgid) for one ring.
+//
+// Buffer groups of a ring share a single 16-bit ID space, and the kernel
+// happily lets a BufRing and a ProvidedBuffers group use the same ID, after
+// which receives select buffers from the wrong pool. Allocate the ID of
+// every group on a ring from the ring's GroupIDAllocator before creating
+// the group, and Free it after the group is closed.
+//
+// A GroupIDAllocator is safe for concurrent use. The zero value is ready
+// to use.
+type GroupIDAllocator struct {
+	mu   sync.Mutex
+	used [1 << 16 / 64]uint64
+	next uint16 // Where the next search starts
+}
+
+// Alloc returns the lowest-numbered free ID at or after the last one
+// allocated, wrapping around, so recently freed IDs are not reused at
+// once. Returns ErrGroupIDsExhausted if every ID is in use.
+func (a *GroupIDAllocator) Alloc() (uint16, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	start := int(a.next)
+	for i := range len(a.used) + 1 {
+		w := (start/64 + i) % len(a.used)
+		free := ^a.used[w]
+		if i == 0 {
+			free &= ^uint64(0) << (start % 64)
+		}
+		if free == 0 {
+			continue
+		}
+		id := uint16(w*64 + bits.TrailingZeros64(free))
+		a.used[w] |= 1 << (id % 64)
+		a.next = id + 1
+		return id, nil
+	}
+	return 0, ErrGroupIDsExhausted
+}
+
+// Reserve marks a specific ID as allocated, for groups whose ID is fixed
+// by convention or by another component. Returns ErrGroupIDInUse if the ID
+// is already allocated.
+func (a *GroupIDAllocator) Reserve(bgid uint16) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.used[bgid/64]&(1<<(bgid%64)) != 0 {
+		return ErrGroupIDInUse
+	}
+	a.used[bgid/64] |= 1 << (bgid % 64)
+	return nil
+}
+
+// InUse reports whether bgid is allocated.
+func (a *GroupIDAllocator) InUse(bgid uint16) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.used[bgid/64]&(1<<(bgid%64)) != 0
+}
+
+// Free releases bgid for reuse. Close the group first: the kernel must no
+// longer select buffers from it. Panics if bgid is not allocated.
+func (a *GroupIDAllocator) Free(bgid uint16) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.used[bgid/64]&(1<<(bgid%64)) == 0 {
+		panic("iobuf.GroupIDAllocator.Free: group ID not allocated")
+	}
+	a.used[bgid/64] &^= 1 << (bgid % 64)
+}
@@ -0,0 +1,62 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"errors"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestGroupIDAllocator_AllocFree(t *testing.T) {
+	var a iobuf.GroupIDAllocator
+	if err := a.Reserve(1); err != nil {
+		t.Fatalf("Reserve(1) failed: %v", err)
+	}
+	if err := a.Reserve(1); !errors.Is(err, iobuf.ErrGroupIDInUse) {
+		t.Errorf("Reserve(1) twice = %v, want ErrGroupIDInUse", err)
+	}
+	for _, want := range []uint16{0, 2, 3} {
+		if id, err := a.Alloc(); err != nil || id != want {
+			t.Errorf("Alloc() = %d, %v; want %d", id, err, want)
+		}
+	}
+	a.Free(2)
+	if a.InUse(2) {
+		t.Error("InUse(2) after Free")
+	}
+	// Freed IDs are not handed out again right away.
+	if id, _ := a.Alloc(); id != 4 {
+		t.Errorf("Alloc() after Free = %d, want 4", id)
+	}
+}
+
+func TestGroupIDAllocator_Exhaust(t *testing.T) {
+	var a iobuf.GroupIDAllocator
+	if err := a.Reserve(65535); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 65535 {
+		if _, err := a.Alloc(); err != nil {
+			t.Fatalf("Alloc() #%d failed: %v", i, err)
+		}
+	}
+	if _, err := a.Alloc(); !errors.Is(err, iobuf.ErrGroupIDsExhausted) {
+		t.Fatalf("Alloc() with all IDs in use = %v, want ErrGroupIDsExhausted", err)
+	}
+	a.Free(100)
+	if id, err := a.Alloc(); err != nil || id != 100 {
+		t.Errorf("Alloc() after wrap-around = %d, %v; want 100", id, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Free of an unallocated ID should panic")
+		}
+	}()
+	var b iobuf.GroupIDAllocator
+	b.Free(3)
+}
@@ -0,0 +1,68 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// guardedMaps maps the data address of each GuardedMem allocation to its
+// full mapping, including the guard pages, for FreeGuardedMem.
+var guardedMaps = struct {
+	sync.Mutex
+	m map[uintptr][]byte
+}{m: make(map[uintptr][]byte)}
+
+// GuardedMem allocates size bytes surrounded by inaccessible (PROT_NONE)
+// guard pages, so out-of-bounds accesses fault immediately instead of
+// silently corrupting neighbouring memory.
+//
+// The region is placed at the end of its pages, directly in front of the
+// trailing guard page, so that even a one-byte overrun faults. The leading
+// guard page catches underruns. The start of the region is page-aligned
+// only when size is a multiple of the page size. The slice is capped at
+// size so append cannot grow into the guard.
+//
+// GuardedMem is meant for debugging unsafe code such as the ArrayFromSlice
+// and SliceOfArray helpers; each allocation costs at least three pages.
+// The memory is mapped outside the Go heap and must be released with
+// FreeGuardedMem. Panics if size < 1.
+func GuardedMem(size int) ([]byte, error) {
+	if size < 1 {
+		panic("iobuf.GuardedMem: size must be positive")
+	}
+	ps := syscall.Getpagesize()
+	span := (size + ps - 1) &^ (ps - 1)
+	m, err := syscall.Mmap(-1, 0, span+2*ps, syscall.PROT_NONE, syscall.MAP_PRIVATE|syscall.MAP_ANONYMOUS)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Mprotect(m[ps:ps+span], syscall.PROT_READ|syscall.PROT_WRITE); err != nil {
+		_ = syscall.Munmap(m)
+		return nil, err
+	}
+	off := ps + span - size
+	mem := m[off : off+size : off+size]
+	guardedMaps.Lock()
+	guardedMaps.m[uintptr(unsafe.Pointer(unsafe.SliceData(mem)))] = m
+	guardedMaps.Unlock()
+	return mem, nil
+}
+
+// FreeGuardedMem unmaps memory returned by GuardedMem, including its guard
+// pages. Panics if mem was not returned by GuardedMem.
+func FreeGuardedMem(mem []byte) error {
+	addr := uintptr(unsafe.Pointer(unsafe.SliceData(mem)))
+	guardedMaps.Lock()
+	m, ok := guardedMaps.m[addr]
+	delete(guardedMaps.m, addr)
+	guardedMaps.Unlock()
+	if !ok {
+		panic("iobuf.FreeGuardedMem: memory not allocated by GuardedMem")
+	}
+	return syscall.Munmap(m)
+}
@@ -0,0 +1,59 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"runtime/debug"
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestGuardedMem(t *testing.T) {
+	mem, err := iobuf.GuardedMem(100)
+	if err != nil {
+		t.Fatalf("GuardedMem() failed: %v", err)
+	}
+	defer iobuf.FreeGuardedMem(mem)
+	if len(mem) != 100 || cap(mem) != 100 {
+		t.Fatalf("len=%d cap=%d, want 100", len(mem), cap(mem))
+	}
+	for i := range mem {
+		mem[i] = byte(i)
+	}
+
+	for name, off := range map[string]int{"overrun": len(mem), "underrun": -4097} {
+		t.Run(name, func(t *testing.T) {
+			defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not fault", name)
+				}
+			}()
+			poke(unsafe.Add(unsafe.Pointer(unsafe.SliceData(mem)), off))
+		})
+	}
+}
+
+func TestGuardedMem_PageMultiple(t *testing.T) {
+	mem, err := iobuf.GuardedMem(8192)
+	if err != nil {
+		t.Fatalf("GuardedMem() failed: %v", err)
+	}
+	if uintptr(unsafe.Pointer(unsafe.SliceData(mem)))%4096 != 0 {
+		t.Error("page-multiple region should be page-aligned")
+	}
+	if err := iobuf.FreeGuardedMem(mem); err != nil {
+		t.Fatalf("FreeGuardedMem() failed: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("FreeGuardedMem on foreign memory should panic")
+		}
+	}()
+	_ = iobuf.FreeGuardedMem(make([]byte, 1))
+}
@@ -0,0 +1,23 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package iobuf
+
+import "errors"
+
+// GuardedMem allocates size bytes surrounded by inaccessible guard pages.
+// Guard pages are only supported on Linux; elsewhere it returns
+// errors.ErrUnsupported.
+func GuardedMem(size int) ([]byte, error) {
+	if size < 1 {
+		panic("iobuf.GuardedMem: size must be positive")
+	}
+	return nil, errors.ErrUnsupported
+}
+
+// FreeGuardedMem releases memory returned by GuardedMem. Guard pages are
+// only supported on Linux, so there is never anything to free.
+func FreeGuardedMem(mem []byte) error { return nil }
@@ -0,0 +1,140 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// HoldBuckets is the number of buckets in a HoldHistogram.
+const HoldBuckets = 32
+
+// HoldHistogram is a histogram of buffer hold times, the durations between
+// a Get and the Put returning the same index.
+//
+// Buckets grow in powers of two from one microsecond: bucket 0 counts holds
+// shorter than HoldBucketBound(0), and bucket i counts holds from
+// HoldBucketBound(i-1) up to HoldBucketBound(i). The last bucket is
+// unbounded.
+type HoldHistogram struct {
+	Counts [HoldBuckets]uint64 // Holds per bucket
+	Sum    time.Duration       // Total of all holds
+}
+
+// HoldBucketBound returns the exclusive upper bound of bucket i of a
+// HoldHistogram. The last bucket has no bound; it returns the largest
+// time.Duration.
+func HoldBucketBound(i int) time.Duration {
+	if i >= HoldBuckets-1 {
+		return time.Duration(1<<63 - 1)
+	}
+	return time.Microsecond << i
+}
+
+// holdBucket returns the bucket counting a hold of d.
+func holdBucket(d time.Duration) int {
+	return min(bits.Len64(uint64(d/time.Microsecond)), HoldBuckets-1)
+}
+
+// Count returns the number of holds recorded.
+func (h HoldHistogram) Count() uint64 {
+	var n uint64
+	for _, c := range h.Counts {
+		n += c
+	}
+	return n
+}
+
+// Mean returns the average hold time, or 0 if none was recorded.
+func (h HoldHistogram) Mean() time.Duration {
+	n := h.Count()
+	if n == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(n)
+}
+
+// Quantile returns an estimate of the q-quantile of hold times, for q in
+// [0, 1]: the upper bound of the bucket the quantile falls in, so the
+// estimate errs high by at most a factor of two. Holds in the last bucket
+// are reported as its lower bound. Returns 0 if no hold was recorded.
+func (h HoldHistogram) Quantile(q float64) time.Duration {
+	n := h.Count()
+	if n == 0 {
+		return 0
+	}
+	rank := uint64(q*float64(n) + 0.5)
+	rank = min(max(rank, 1), n)
+	var seen uint64
+	for i, c := range h.Counts {
+		seen += c
+		if seen >= rank {
+			if i == HoldBuckets-1 {
+				return HoldBucketBound(i - 1)
+			}
+			return HoldBucketBound(i)
+		}
+	}
+	return HoldBucketBound(HoldBuckets - 2)
+}
+
+// poolHold tracks the hold times of a pool with hold-time tracking enabled.
+type poolHold struct {
+	base   time.Time // Monotonic origin of the acquire stamps
+	since  []int64   // Per index: nanoseconds after base it was acquired, plus one; 0 if unknown
+	counts [HoldBuckets]atomic.Uint64
+	sum    atomic.Int64
+}
+
+// SetHoldTime enables or disables tracking how long callers hold buffers.
+//
+// With tracking enabled, every Put records the time since the Get of the
+// same index in a histogram reported as PoolStats.Hold. Long holds point at
+// slow consumers; short holds on an exhausted pool point at too little
+// capacity. Tracking costs a clock read on every Get and Put, so it is off
+// by default. Disabling resets the histogram. SetHoldTime must be called
+// before the pool is shared between goroutines.
+func (pool *BoundedPool[T]) SetHoldTime(enabled bool) {
+	if enabled {
+		if pool.hold == nil {
+			pool.hold = &poolHold{base: time.Now(), since: make([]int64, pool.capacity)}
+		}
+		pool.debug |= poolDebugHold
+	} else {
+		pool.debug &^= poolDebugHold
+		pool.hold = nil
+	}
+}
+
+// holdGet stamps the acquisition of indirect.
+func (pool *BoundedPool[T]) holdGet(indirect int) {
+	pool.hold.since[indirect] = int64(time.Since(pool.hold.base)) + 1
+}
+
+// holdPut records the hold of indirect, which is about to be released.
+// Indices acquired before tracking was enabled are not recorded.
+func (pool *BoundedPool[T]) holdPut(indirect int) {
+	h := pool.hold
+	since := h.since[indirect]
+	if since == 0 {
+		return
+	}
+	h.since[indirect] = 0
+	d := time.Since(h.base) - time.Duration(since-1)
+	h.counts[holdBucket(d)].Add(1)
+	h.sum.Add(int64(d))
+}
+
+// snapshot returns the histogram recorded so far.
+func (h *poolHold) snapshot() HoldHistogram {
+	var s HoldHistogram
+	for i := range h.counts {
+		s.Counts[i] = h.counts[i].Load()
+	}
+	s.Sum = time.Duration(h.sum.Load())
+	return s
+}
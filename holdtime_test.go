@@ -0,0 +1,77 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"testing"
+	"time"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestBoundedPool_SetHoldTime(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(4)
+	pool.Fill(iobuf.NewNanoBuffer)
+	early, _ := pool.Get() // held before tracking starts: not recorded
+	pool.SetHoldTime(true)
+
+	for range 3 {
+		idx, _ := pool.Get()
+		_ = pool.Put(idx)
+	}
+	slow, _ := pool.Get()
+	time.Sleep(20 * time.Millisecond)
+	_ = pool.Put(slow)
+	_ = pool.Put(early)
+
+	h := pool.Stats().Hold
+	if h.Count() != 4 {
+		t.Fatalf("Count() = %d, want 4", h.Count())
+	}
+	if h.Sum < 20*time.Millisecond {
+		t.Errorf("Sum = %v, want at least 20ms", h.Sum)
+	}
+	if q := h.Quantile(1); q < 20*time.Millisecond || q > 80*time.Millisecond {
+		t.Errorf("Quantile(1) = %v, want the bucket holding 20ms", q)
+	}
+	if q := h.Quantile(0.5); q >= 20*time.Millisecond {
+		t.Errorf("Quantile(0.5) = %v, want a short hold", q)
+	}
+	if h.Mean() != h.Sum/4 {
+		t.Errorf("Mean() = %v, want %v", h.Mean(), h.Sum/4)
+	}
+
+	pool.SetHoldTime(false)
+	if h := pool.Stats().Hold; h.Count() != 0 {
+		t.Errorf("Count() after disabling = %d", h.Count())
+	}
+}
+
+func TestHoldHistogram(t *testing.T) {
+	var h iobuf.HoldHistogram
+	if h.Quantile(0.99) != 0 || h.Mean() != 0 {
+		t.Error("empty histogram reports holds")
+	}
+	h.Counts[0] = 90 // < 1µs
+	h.Counts[4] = 9  // [8µs, 16µs)
+	h.Counts[iobuf.HoldBuckets-1] = 1
+	tests := []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0, time.Microsecond},
+		{0.5, time.Microsecond},
+		{0.95, 16 * time.Microsecond},
+		{1, iobuf.HoldBucketBound(iobuf.HoldBuckets - 2)},
+	}
+	for _, tt := range tests {
+		if got := h.Quantile(tt.q); got != tt.want {
+			t.Errorf("Quantile(%v) = %v, want %v", tt.q, got, tt.want)
+		}
+	}
+	if b := iobuf.HoldBucketBound(10); b != 1024*time.Microsecond {
+		t.Errorf("HoldBucketBound(10) = %v", b)
+	}
+}
@@ -0,0 +1,103 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"runtime/trace"
+	"time"
+)
+
+// PoolOp identifies the pool operation reported to wait hooks.
+type PoolOp uint8
+
+const (
+	PoolGet PoolOp = iota // Get, waiting for an item
+	PoolPut               // Put, waiting for room
+)
+
+// String returns "Get" or "Put".
+func (op PoolOp) String() string {
+	if op == PoolPut {
+		return "Put"
+	}
+	return "Get"
+}
+
+// PoolHooks are callbacks a BoundedPool invokes on pool events. Any of
+// them may be nil.
+//
+// Hooks are the extension point for metrics, tracing and leak detection:
+// they run synchronously on the goroutine calling Get or Put, so they must
+// be fast and must not call back into the pool.
+type PoolHooks struct {
+	// OnGet is called with the index of every successful Get.
+	OnGet func(indirect int)
+
+	// OnPut is called with the index of every successful Put. The index
+	// already belongs to the pool again.
+	OnPut func(indirect int)
+
+	// OnBlock is called when a blocking Get finds the pool empty, or a
+	// blocking Put finds it full, and starts waiting.
+	OnBlock func(op PoolOp)
+
+	// OnUnblock is called when a call reported to OnBlock stops waiting,
+	// with the time it waited.
+	OnUnblock func(op PoolOp, waited time.Duration)
+}
+
+// SetHooks installs hooks on the pool, or removes them if hooks is nil.
+// The hooks apply to Get and Put. SetHooks must be called before the pool
+// is shared between goroutines, and hooks must not be modified afterwards.
+func (pool *BoundedPool[T]) SetHooks(hooks *PoolHooks) {
+	pool.hooks = hooks
+	if hooks != nil {
+		pool.debug |= poolDebugHooks
+	} else {
+		pool.debug &^= poolDebugHooks
+	}
+}
+
+// poolWait is the state of a blocking wait observed by diagnostic modes.
+type poolWait struct {
+	start  time.Time
+	fired  poolDebug     // Watch modes that already reported the wait, see watchWait
+	region *trace.Region // Execution trace region, see SetTrace
+}
+
+// waiting reports whether a wait has begun.
+func (w *poolWait) waiting() bool { return !w.start.IsZero() }
+
+// beginWait records the start of a blocking wait.
+func (pool *BoundedPool[T]) beginWait(op PoolOp) poolWait {
+	if pool.debug&poolDebugStats != 0 {
+		pool.stats.blocks.Add(1)
+	}
+	if pool.debug&poolDebugHooks != 0 && pool.hooks.OnBlock != nil {
+		pool.hooks.OnBlock(op)
+	}
+	if op == PoolGet && pool.debug&poolDebugLog != 0 {
+		pool.logExhausted()
+	}
+	w := poolWait{start: time.Now()}
+	if pool.debug&poolDebugTrace != 0 {
+		w.region = pool.traceWait(op)
+	}
+	return w
+}
+
+// endWait records the end of a blocking wait.
+func (pool *BoundedPool[T]) endWait(op PoolOp, w *poolWait) {
+	if w.region != nil {
+		w.region.End()
+	}
+	waited := time.Since(w.start)
+	if pool.debug&poolDebugStats != 0 {
+		pool.stats.blockNanos.Add(int64(waited))
+	}
+	if pool.debug&poolDebugHooks != 0 && pool.hooks.OnUnblock != nil {
+		pool.hooks.OnUnblock(op, waited)
+	}
+}
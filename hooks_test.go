@@ -0,0 +1,77 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestBoundedPool_Hooks(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(1)
+	pool.Fill(iobuf.NewNanoBuffer)
+	var mu sync.Mutex
+	var events []string
+	var waited time.Duration
+	record := func(e string) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	}
+	pool.SetHooks(&iobuf.PoolHooks{
+		OnGet:   func(i int) { record("get") },
+		OnPut:   func(i int) { record("put") },
+		OnBlock: func(op iobuf.PoolOp) { record("block " + op.String()) },
+		OnUnblock: func(op iobuf.PoolOp, d time.Duration) {
+			record("unblock " + op.String())
+			waited = d
+		},
+	})
+
+	idx, _ := pool.Get()
+	done := make(chan struct{})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = pool.Put(idx)
+		close(done)
+	}()
+	idx, _ = pool.Get() // blocks until the goroutine puts
+	<-done
+	_ = pool.Put(idx)
+
+	want := []string{"get", "put", "block Get", "unblock Get", "get", "put"}
+	// The waiting Get may observe the Put before or after it is reported.
+	if len(events) != len(want) || events[0] != "get" || events[len(events)-1] != "put" {
+		t.Fatalf("events = %v, want %v in some interleaving", events, want)
+	}
+	if waited <= 0 {
+		t.Errorf("OnUnblock waited = %v, want > 0", waited)
+	}
+
+	pool.SetHooks(nil)
+	events = nil
+	idx, _ = pool.Get()
+	_ = pool.Put(idx)
+	if len(events) != 0 {
+		t.Errorf("hooks still called after SetHooks(nil): %v", events)
+	}
+}
+
+func TestBoundedPool_HooksNonblockingPutFull(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(1)
+	pool.Fill(iobuf.NewNanoBuffer)
+	pool.SetNonblock(true)
+	puts := 0
+	pool.SetHooks(&iobuf.PoolHooks{OnPut: func(int) { puts++ }})
+	if err := pool.Put(0); err == nil {
+		t.Fatal("Put() into a full pool succeeded")
+	}
+	if puts != 0 {
+		t.Error("OnPut called for a failed Put")
+	}
+}
@@ -0,0 +1,134 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Explicit huge page sizes for AlignedMemHugeSize.
+const (
+	HugePageSize2M = 1 << 21 // 2 MiB, the default huge page size on x86-64 and ARM64
+	HugePageSize1G = 1 << 30 // 1 GiB, gigantic pages
+)
+
+// ErrHugePagesUnavailable is returned when explicit huge pages cannot be
+// allocated, either because the platform lacks them or because no huge
+// pages of the requested size are reserved (see /proc/sys/vm/nr_hugepages).
+var ErrHugePagesUnavailable = errors.New("iobuf: huge pages unavailable")
+
+// AlignedMemHuge allocates size bytes backed by explicit 2 MiB huge pages.
+//
+// Buffer regions registered for DMA or io_uring see fewer TLB misses when
+// backed by huge pages. The memory is mapped outside the Go heap: it is
+// never moved or collected and must be released with FreeMemHuge.
+//
+// The error wraps ErrHugePagesUnavailable when the allocation fails, so
+// callers can fall back to AlignedMem. Panics if size < 1.
+func AlignedMemHuge(size int) ([]byte, error) {
+	return AlignedMemHugeSize(size, HugePageSize2M)
+}
+
+// HugePagePolicy selects what a HugePageAllocator does when explicit huge
+// pages cannot be allocated.
+type HugePagePolicy uint8
+
+const (
+	// HugePageRequire fails the allocation with ErrHugePagesUnavailable.
+	HugePageRequire HugePagePolicy = iota
+	// HugePageFallback maps regular pages instead, advised for transparent
+	// huge pages, and records the fallback.
+	HugePageFallback
+)
+
+// HugePageStats reports how a HugePageAllocator satisfied its allocations.
+type HugePageStats struct {
+	Huge      int64 // Allocations backed by explicit huge pages
+	Fallbacks int64 // Allocations that fell back to regular pages
+	Failures  int64 // Allocations that failed
+}
+
+// HugePageAllocator allocates memory backed by explicit huge pages and
+// applies a policy when none are reserved.
+//
+// AlignedMemHugeSize always reports failure, leaving the caller to retry
+// with regular pages. A HugePageAllocator makes that choice once: with
+// HugePageRequire it fails hard, with HugePageFallback it silently maps
+// regular pages but counts each fallback in Stats and reports it to the
+// hook set with OnFallback, so a deployment missing its hugetlbfs
+// reservation does not go unnoticed.
+//
+// Memory from Alloc, whether huge or not, is mapped outside the Go heap and
+// must be released with Free. A HugePageAllocator is safe for concurrent
+// use once configured.
+type HugePageAllocator struct {
+	pageSize   int
+	policy     HugePagePolicy
+	onFallback func(size int, err error)
+
+	huge      atomic.Int64
+	fallbacks atomic.Int64
+	failures  atomic.Int64
+}
+
+// NewHugePageAllocator returns an allocator of hugePageSize pages, e.g.,
+// HugePageSize2M, with the given fallback policy.
+// Panics if hugePageSize is not a power of two.
+func NewHugePageAllocator(hugePageSize int, policy HugePagePolicy) *HugePageAllocator {
+	if hugePageSize <= 0 || hugePageSize&(hugePageSize-1) != 0 {
+		panic("iobuf.NewHugePageAllocator: huge page size must be a power of two")
+	}
+	return &HugePageAllocator{pageSize: hugePageSize, policy: policy}
+}
+
+// OnFallback sets a function called with the requested size and the huge
+// page error each time an allocation falls back to regular pages. It must
+// be set before the allocator is used.
+func (a *HugePageAllocator) OnFallback(fn func(size int, err error)) {
+	a.onFallback = fn
+}
+
+// Alloc returns size bytes rounded up to whole huge pages, sliced to size.
+// Under HugePageRequire the error wraps ErrHugePagesUnavailable when no
+// huge pages are available. Panics if size < 1.
+func (a *HugePageAllocator) Alloc(size int) ([]byte, error) {
+	mem, err := AlignedMemHugeSize(size, a.pageSize)
+	if err == nil {
+		a.huge.Add(1)
+		return mem, nil
+	}
+	if a.policy != HugePageFallback {
+		a.failures.Add(1)
+		return nil, err
+	}
+	length := (size + a.pageSize - 1) &^ (a.pageSize - 1)
+	mem, merr := mapRegion(length, 0)
+	if merr != nil {
+		a.failures.Add(1)
+		return nil, merr
+	}
+	_ = MadviseHuge(mem)
+	a.fallbacks.Add(1)
+	if a.onFallback != nil {
+		a.onFallback(size, err)
+	}
+	return mem[:size], nil
+}
+
+// Free releases memory returned by Alloc. The memory must not be used
+// afterwards.
+func (a *HugePageAllocator) Free(mem []byte) error {
+	return unmapRegion(mem)
+}
+
+// Stats returns the allocation counters.
+func (a *HugePageAllocator) Stats() HugePageStats {
+	return HugePageStats{
+		Huge:      a.huge.Load(),
+		Fallbacks: a.fallbacks.Load(),
+		Failures:  a.failures.Load(),
+	}
+}
@@ -0,0 +1,122 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"fmt"
+	"math/bits"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// madvCollapse is MADV_COLLAPSE (Linux 6.1+), which package syscall
+// does not define.
+const madvCollapse = 25
+
+// mapHugeShift is the bit offset of the log2 page size in mmap flags
+// when MAP_HUGETLB is set.
+const mapHugeShift = 26
+
+// AlignedMemHugeSize allocates size bytes backed by explicit huge pages of
+// hugePageSize bytes, e.g., HugePageSize2M or HugePageSize1G. The mapping is
+// rounded up to a whole number of huge pages and is therefore aligned to
+// hugePageSize.
+//
+// The memory must be released with FreeMemHuge. The error wraps
+// ErrHugePagesUnavailable when the allocation fails.
+// Panics if size < 1 or hugePageSize is not a power of two.
+func AlignedMemHugeSize(size int, hugePageSize int) ([]byte, error) {
+	if size < 1 {
+		panic("iobuf.AlignedMemHugeSize: size must be positive")
+	}
+	if hugePageSize <= 0 || hugePageSize&(hugePageSize-1) != 0 {
+		panic("iobuf.AlignedMemHugeSize: huge page size must be a power of two")
+	}
+	length := (size + hugePageSize - 1) &^ (hugePageSize - 1)
+	flags := syscall.MAP_PRIVATE | syscall.MAP_ANONYMOUS | syscall.MAP_HUGETLB |
+		bits.TrailingZeros(uint(hugePageSize))<<mapHugeShift
+	mem, err := mmapAnon(length, flags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %d bytes: %w", ErrHugePagesUnavailable, length, err)
+	}
+	return mem[:size], nil
+}
+
+// FreeMemHuge unmaps memory returned by AlignedMemHuge or
+// AlignedMemHugeSize. The memory must not be used afterwards.
+func FreeMemHuge(mem []byte) error {
+	return unmapRegion(mem)
+}
+
+// HugePageSizes returns the huge page sizes supported by the kernel in
+// increasing order, or nil if huge pages are unavailable. A size being
+// supported does not imply that pages of that size are reserved.
+func HugePageSizes() []int {
+	entries, err := os.ReadDir("/sys/kernel/mm/hugepages")
+	if err != nil {
+		return nil
+	}
+	var sizes []int
+	for _, e := range entries {
+		kb, ok := strings.CutPrefix(e.Name(), "hugepages-")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(kb, "kB"))
+		if err != nil {
+			continue
+		}
+		sizes = append(sizes, n<<10)
+	}
+	slices.Sort(sizes)
+	return sizes
+}
+
+// MadviseHuge advises the kernel to back mem with transparent huge pages
+// (MADV_HUGEPAGE). Unlike AlignedMemHuge it needs no hugetlbfs reservation,
+// and the kernel falls back to regular pages when no huge page is free.
+//
+// The advice applies to the whole pages inside mem; partial pages at either
+// end are skipped. It takes effect when the pages are first touched, so
+// advise memory before filling it. Pools of Huge-tier and larger buffers
+// are advised automatically by Fill.
+func MadviseHuge(mem []byte) error {
+	mem = pageAligned(mem)
+	if len(mem) == 0 {
+		return nil
+	}
+	return syscall.Madvise(mem, syscall.MADV_HUGEPAGE)
+}
+
+// CollapseHuge synchronously collapses the already populated pages of mem
+// into transparent huge pages (MADV_COLLAPSE). It returns syscall.EINVAL on
+// kernels older than Linux 6.1.
+func CollapseHuge(mem []byte) error {
+	mem = pageAligned(mem)
+	if len(mem) == 0 {
+		return nil
+	}
+	return syscall.Madvise(mem, madvCollapse)
+}
+
+// pageAligned returns the largest sub-slice of mem that starts and ends on
+// system page boundaries.
+func pageAligned(mem []byte) []byte {
+	if len(mem) == 0 {
+		return nil
+	}
+	ps := uintptr(syscall.Getpagesize())
+	base := uintptr(unsafe.Pointer(unsafe.SliceData(mem)))
+	start := (base + ps - 1) &^ (ps - 1)
+	end := (base + uintptr(len(mem))) &^ (ps - 1)
+	if start >= end {
+		return nil
+	}
+	return mem[start-base : end-base]
+}
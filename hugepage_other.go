@@ -0,0 +1,37 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package iobuf
+
+// AlignedMemHugeSize allocates size bytes backed by explicit huge pages of
+// hugePageSize bytes. Explicit huge pages are only supported on Linux; on
+// other platforms it always returns ErrHugePagesUnavailable.
+func AlignedMemHugeSize(size int, hugePageSize int) ([]byte, error) {
+	if size < 1 {
+		panic("iobuf.AlignedMemHugeSize: size must be positive")
+	}
+	if hugePageSize <= 0 || hugePageSize&(hugePageSize-1) != 0 {
+		panic("iobuf.AlignedMemHugeSize: huge page size must be a power of two")
+	}
+	return nil, ErrHugePagesUnavailable
+}
+
+// FreeMemHuge releases memory returned by AlignedMemHuge. Explicit huge
+// pages are only supported on Linux, so there is never anything to free.
+func FreeMemHuge(mem []byte) error { return nil }
+
+// HugePageSizes returns nil: explicit huge pages are only supported on Linux.
+func HugePageSizes() []int { return nil }
+
+// MadviseHuge advises the kernel to back mem with transparent huge pages.
+// Transparent huge pages are only supported on Linux; elsewhere it is a
+// no-op.
+func MadviseHuge(mem []byte) error { return nil }
+
+// CollapseHuge collapses the pages of mem into transparent huge pages.
+// Transparent huge pages are only supported on Linux; elsewhere it is a
+// no-op.
+func CollapseHuge(mem []byte) error { return nil }
@@ -0,0 +1,132 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestAlignedMemHuge(t *testing.T) {
+	mem, err := iobuf.AlignedMemHuge(4096)
+	if err != nil {
+		if !errors.Is(err, iobuf.ErrHugePagesUnavailable) {
+			t.Fatalf("AlignedMemHuge() error %v does not wrap ErrHugePagesUnavailable", err)
+		}
+		t.Skipf("huge pages not available: %v", err)
+	}
+	defer iobuf.FreeMemHuge(mem)
+
+	if len(mem) != 4096 || cap(mem) != iobuf.HugePageSize2M {
+		t.Errorf("len=%d cap=%d, want 4096 and one huge page", len(mem), cap(mem))
+	}
+	if uintptr(unsafe.Pointer(unsafe.SliceData(mem)))%iobuf.HugePageSize2M != 0 {
+		t.Error("huge page memory not aligned to 2 MiB")
+	}
+	mem[0], mem[len(mem)-1] = 1, 2
+}
+
+func TestHugePageSizes(t *testing.T) {
+	sizes := iobuf.HugePageSizes()
+	if !slices.IsSorted(sizes) {
+		t.Errorf("HugePageSizes() = %v, want increasing order", sizes)
+	}
+	for _, s := range sizes {
+		if s <= 0 || s&(s-1) != 0 {
+			t.Errorf("HugePageSizes() contains %d, want powers of two", s)
+		}
+	}
+}
+
+func TestAlignedMemHugeSize_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("AlignedMemHugeSize with non-power-of-two page size should panic")
+		}
+	}()
+	_, _ = iobuf.AlignedMemHugeSize(1, 3<<20)
+}
+
+func TestMadviseHuge(t *testing.T) {
+	mem := iobuf.AlignedMem(2*iobuf.HugePageSize2M, iobuf.HugePageSize2M)
+	if err := iobuf.MadviseHuge(mem); err != nil {
+		t.Skipf("transparent huge pages not available: %v", err)
+	}
+	// Ranges smaller than a page are skipped rather than rejected.
+	if err := iobuf.MadviseHuge(mem[1:100]); err != nil {
+		t.Errorf("MadviseHuge on partial page: %v", err)
+	}
+	if err := iobuf.MadviseHuge(nil); err != nil {
+		t.Errorf("MadviseHuge(nil): %v", err)
+	}
+	for i := 0; i < len(mem); i += 4096 {
+		mem[i] = 1
+	}
+	if err := iobuf.CollapseHuge(mem); err != nil {
+		t.Logf("CollapseHuge: %v", err)
+	}
+}
+
+func TestHugeBufferPool_FillAdvises(t *testing.T) {
+	if raceEnabled {
+		t.Skip("large array pools are slow under the race detector")
+	}
+	pool := iobuf.NewHugeBufferPool(2)
+	pool.Fill(iobuf.NewHugeBuffer)
+	idx, err := pool.Get()
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	pool.Bytes(idx)[iobuf.BufferSizeHuge-1] = 1
+	_ = pool.Put(idx)
+}
+
+func TestHugePageAllocator_Fallback(t *testing.T) {
+	a := iobuf.NewHugePageAllocator(iobuf.HugePageSize2M, iobuf.HugePageFallback)
+	var hooked int
+	a.OnFallback(func(size int, err error) {
+		if size != 4096 || !errors.Is(err, iobuf.ErrHugePagesUnavailable) {
+			t.Errorf("OnFallback(%d, %v), want 4096 and ErrHugePagesUnavailable", size, err)
+		}
+		hooked++
+	})
+	mem, err := a.Alloc(4096)
+	if err != nil {
+		t.Fatalf("Alloc() with fallback failed: %v", err)
+	}
+	defer a.Free(mem)
+	if len(mem) != 4096 || cap(mem) != iobuf.HugePageSize2M {
+		t.Errorf("len=%d cap=%d, want 4096 and one huge page", len(mem), cap(mem))
+	}
+	mem[0], mem[len(mem)-1] = 1, 2
+
+	st := a.Stats()
+	if st.Huge+st.Fallbacks != 1 || st.Failures != 0 || int64(hooked) != st.Fallbacks {
+		t.Errorf("Stats() = %+v with %d hook calls, want one allocation", st, hooked)
+	}
+}
+
+func TestHugePageAllocator_Require(t *testing.T) {
+	a := iobuf.NewHugePageAllocator(iobuf.HugePageSize1G, iobuf.HugePageRequire)
+	a.OnFallback(func(int, error) { t.Error("OnFallback called under HugePageRequire") })
+	mem, err := a.Alloc(1)
+	if err == nil {
+		defer a.Free(mem)
+		if st := a.Stats(); st.Huge != 1 {
+			t.Errorf("Stats() = %+v, want one huge allocation", st)
+		}
+		return
+	}
+	if !errors.Is(err, iobuf.ErrHugePagesUnavailable) {
+		t.Errorf("Alloc() error %v does not wrap ErrHugePagesUnavailable", err)
+	}
+	if st := a.Stats(); st.Failures != 1 || st.Fallbacks != 0 {
+		t.Errorf("Stats() = %+v, want one failure", st)
+	}
+}
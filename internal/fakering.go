@@ -0,0 +1,44 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "sync"
+
+// FakeRing receives the io_uring registrations iobuf makes for a ring
+// descriptor claimed by a test harness, in place of io_uring_register.
+// Fake descriptors are negative, so they never collide with real ones.
+type FakeRing interface {
+	RegisterBufRing(mem []byte, entries int, bgid uint16, incremental bool) error
+	UnregisterBufRing(bgid uint16) error
+	RegisterZcrx(area, region []byte, entries uint32) (ZcrxLayout, error)
+}
+
+// ZcrxLayout is what zero-copy receive registration reports back: the
+// refill ring offsets within the region, its entry count, the area token
+// and the instance ID.
+type ZcrxLayout struct {
+	Head, Tail, Rqes uint32
+	Entries          uint32
+	Token            uint64
+	ID               uint32
+}
+
+var fakeRings sync.Map // int -> FakeRing
+
+// AddFakeRing routes registrations for descriptor fd to r.
+func AddFakeRing(fd int, r FakeRing) { fakeRings.Store(fd, r) }
+
+// RemoveFakeRing stops routing registrations for descriptor fd.
+func RemoveFakeRing(fd int) { fakeRings.Delete(fd) }
+
+// FakeRingOf returns the harness claiming descriptor fd, or nil.
+func FakeRingOf(fd int) FakeRing {
+	if fd >= 0 {
+		return nil
+	}
+	r, _ := fakeRings.Load(fd)
+	f, _ := r.(FakeRing)
+	return f
+}
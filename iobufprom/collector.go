@@ -0,0 +1,123 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package iobufprom exports the statistics of registered iobuf pools as
+// Prometheus metrics.
+//
+// It lives in its own module so that iobuf itself does not depend on the
+// Prometheus client. Register pools with iobuf.RegisterPool, then register
+// a Collector:
+//
+//	iobuf.RegisterPool("recv", recvPool)
+//	prometheus.MustRegister(iobufprom.NewCollector())
+//
+// Every metric carries the labels pool (the registered name) and tier.
+package iobufprom
+
+import (
+	"code.hybscloud.com/iobuf"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var poolLabels = []string{"pool", "tier"}
+
+// Collector is a prometheus.Collector reporting every pool registered with
+// iobuf.RegisterPool at scrape time.
+type Collector struct {
+	capacity    *prometheus.Desc
+	available   *prometheus.Desc
+	inUse       *prometheus.Desc
+	gets        *prometheus.Desc
+	puts        *prometheus.Desc
+	wouldBlocks *prometheus.Desc
+	blocks      *prometheus.Desc
+	blockTime   *prometheus.Desc
+	reclaimed   *prometheus.Desc
+	eliminated  *prometheus.Desc
+	hold        *prometheus.Desc
+	getRetries  *prometheus.Desc
+	putRetries  *prometheus.Desc
+	backoffs    *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector returns a Collector for the registered pools.
+func NewCollector() *Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("iobuf_pool_"+name, help, poolLabels, nil)
+	}
+	return &Collector{
+		capacity:    desc("capacity", "Number of buffers the pool holds."),
+		available:   desc("available", "Buffers currently in the pool."),
+		inUse:       desc("in_use", "Buffers currently held by callers."),
+		gets:        desc("gets_total", "Successful Get calls."),
+		puts:        desc("puts_total", "Successful Put calls."),
+		wouldBlocks: desc("would_block_total", "Non-blocking Get or Put calls that returned ErrWouldBlock."),
+		blocks:      desc("blocks_total", "Blocking Get or Put calls that had to wait."),
+		blockTime:   desc("block_seconds_total", "Total time spent waiting in blocking Get or Put calls."),
+		reclaimed:   desc("reclaimed_leaks_total", "Leaked buffers returned to the pool by the garbage collector."),
+		eliminated:  desc("eliminated_total", "Put calls handing their buffer directly to a waiting Get."),
+		hold:        desc("hold_seconds", "Time callers held buffers between Get and Put."),
+		getRetries:  desc("get_retries_total", "Spin-waits of Get calls on a slot whose Put was still in flight."),
+		putRetries:  desc("put_retries_total", "Spin-waits of Put calls on a slot whose Get was still in flight."),
+		backoffs:    desc("backoffs_total", "Adaptive waits of blocking Get or Put calls."),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.capacity
+	ch <- c.available
+	ch <- c.inUse
+	ch <- c.gets
+	ch <- c.puts
+	ch <- c.wouldBlocks
+	ch <- c.blocks
+	ch <- c.blockTime
+	ch <- c.reclaimed
+	ch <- c.eliminated
+	ch <- c.hold
+	ch <- c.getRetries
+	ch <- c.putRetries
+	ch <- c.backoffs
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range iobuf.RegisteredPoolStats() {
+		tier := "none"
+		if s.Tier != iobuf.TierEnd {
+			tier = s.Tier.String()
+		}
+		gauge := func(d *prometheus.Desc, v float64) {
+			ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v, s.Name, tier)
+		}
+		counter := func(d *prometheus.Desc, v float64) {
+			ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v, s.Name, tier)
+		}
+		gauge(c.capacity, float64(s.Capacity))
+		gauge(c.available, float64(s.Available))
+		gauge(c.inUse, float64(s.InUse()))
+		counter(c.gets, float64(s.Gets))
+		counter(c.puts, float64(s.Puts))
+		counter(c.wouldBlocks, float64(s.WouldBlocks))
+		counter(c.blocks, float64(s.Blocks))
+		counter(c.blockTime, s.BlockTime.Seconds())
+		counter(c.reclaimed, float64(s.Reclaimed))
+		counter(c.eliminated, float64(s.Eliminated))
+		counter(c.getRetries, float64(s.GetRetries))
+		counter(c.putRetries, float64(s.PutRetries))
+		counter(c.backoffs, float64(s.Backoffs))
+		if n := s.Hold.Count(); n > 0 {
+			buckets := make(map[float64]uint64, iobuf.HoldBuckets-1)
+			var cum uint64
+			for i, k := range s.Hold.Counts[:iobuf.HoldBuckets-1] {
+				cum += k
+				buckets[iobuf.HoldBucketBound(i).Seconds()] = cum
+			}
+			ch <- prometheus.MustNewConstHistogram(c.hold, n, s.Hold.Sum.Seconds(), buckets, s.Name, tier)
+		}
+	}
+}
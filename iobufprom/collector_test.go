@@ -0,0 +1,58 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobufprom_test
+
+import (
+	"strings"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+	"code.hybscloud.com/iobuf/iobufprom"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	pool := iobuf.NewSmallBufferPool(4)
+	pool.Fill(iobuf.NewSmallBuffer)
+	iobuf.RegisterPool("recv", pool)
+	defer iobuf.UnregisterPool("recv")
+	idx, _ := pool.Get()
+	defer pool.Put(idx)
+
+	want := `
+# HELP iobuf_pool_capacity Number of buffers the pool holds.
+# TYPE iobuf_pool_capacity gauge
+iobuf_pool_capacity{pool="recv",tier="Small"} 4
+# HELP iobuf_pool_gets_total Successful Get calls.
+# TYPE iobuf_pool_gets_total counter
+iobuf_pool_gets_total{pool="recv",tier="Small"} 1
+# HELP iobuf_pool_in_use Buffers currently held by callers.
+# TYPE iobuf_pool_in_use gauge
+iobuf_pool_in_use{pool="recv",tier="Small"} 1
+`
+	c := iobufprom.NewCollector()
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want),
+		"iobuf_pool_capacity", "iobuf_pool_gets_total", "iobuf_pool_in_use"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(c); n != 13 {
+		t.Errorf("collected %d metrics, want 13", n)
+	}
+}
+
+func TestCollector_HoldTime(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(2)
+	pool.Fill(iobuf.NewNanoBuffer)
+	pool.SetHoldTime(true)
+	iobuf.RegisterPool("hold", pool)
+	defer iobuf.UnregisterPool("hold")
+	idx, _ := pool.Get()
+	_ = pool.Put(idx)
+
+	c := iobufprom.NewCollector()
+	if n := testutil.CollectAndCount(c, "iobuf_pool_hold_seconds"); n != 1 {
+		t.Errorf("collected %d hold histograms, want 1", n)
+	}
+}
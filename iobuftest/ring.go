@@ -0,0 +1,279 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package iobuftest provides a mock io_uring for testing code built on
+// iobuf's buffer groups and zero-copy receive areas.
+//
+// A Ring stands in for the kernel side of an io_uring instance. Its
+// descriptor can be passed wherever iobuf takes a ring descriptor:
+// NewBufRing, NewBufferGroup and ZcrxArea.Register register with the mock
+// instead of calling io_uring_register, and Submit accepts the operations
+// of a ProvidedBuffers. Recv and RecvZC then consume buffers the way the
+// kernel does for buffer-select and zero-copy receives, returning the
+// completion an application would reap. Everything runs in-process and
+// deterministically, on every platform.
+//
+//	ring := iobuftest.NewRing()
+//	defer ring.Close()
+//	g, _ := iobuf.NewBufRing(ring.Fd(), pool, 1, 0, false)
+//	g.Replenish()
+//	cqe := ring.Recv(1, []byte("hello"))
+//	indirect, data := g.Completed(cqe.BufferID(), int(cqe.Res))
+package iobuftest
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+	"code.hybscloud.com/iobuf/internal"
+)
+
+// ENOBUFS is the Linux errno a receive completes with, negated in Res, when
+// its buffer group has no buffer left.
+const ENOBUFS = 105
+
+// Registration errors, standing in for EEXIST and ENOENT.
+var (
+	errExist    = errors.New("iobuftest: buffer group already registered")
+	errNotExist = errors.New("iobuftest: buffer group not registered")
+)
+
+// Completion flag bits set by the mock, as in linux/io_uring.h.
+const (
+	CQEFBuffer  = 1 << 0 // IORING_CQE_F_BUFFER: a buffer ID is in the upper 16 bits
+	CQEFBufMore = 1 << 4 // IORING_CQE_F_BUF_MORE: the buffer stays with the kernel
+)
+
+// CQE is a completion produced by the mock.
+type CQE struct {
+	Res   int32
+	Flags uint32
+	Off   uint64 // Area offset of a zero-copy receive (struct io_uring_zcrx_cqe)
+}
+
+// BufferID returns the buffer ID carried in the flags.
+func (c CQE) BufferID() uint16 { return uint16(c.Flags >> 16) }
+
+// Ring is a mock io_uring instance. A Ring is safe for concurrent use.
+type Ring struct {
+	mu       sync.Mutex
+	fd       int
+	rings    map[uint16]*bufRing
+	provided map[uint16][]providedBuf
+	zcrx     []*zcrxQueue
+}
+
+var nextFd atomic.Int64
+
+// NewRing returns a mock ring with a fresh fake descriptor. Close it when
+// done to release the descriptor.
+func NewRing() *Ring {
+	r := &Ring{
+		fd:       int(-2 - nextFd.Add(1)),
+		rings:    make(map[uint16]*bufRing),
+		provided: make(map[uint16][]providedBuf),
+	}
+	internal.AddFakeRing(r.fd, r)
+	return r
+}
+
+// Fd returns the fake ring descriptor. It is negative and only meaningful
+// to iobuf.
+func (r *Ring) Fd() int { return r.fd }
+
+// Close releases the fake descriptor. Like closing a real ring it drops
+// every registration.
+func (r *Ring) Close() {
+	internal.RemoveFakeRing(r.fd)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.rings)
+	clear(r.provided)
+	r.zcrx = nil
+}
+
+// Available returns the number of buffers the kernel could still select
+// from group bgid.
+func (r *Ring) Available(bgid uint16) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if br, ok := r.rings[bgid]; ok {
+		return int(br.tail() - br.head)
+	}
+	return len(r.provided[bgid])
+}
+
+// Recv emulates a buffer-select receive of data on group bgid. The data is
+// copied into the next buffer of the group; anything that does not fit is
+// dropped. If the group is empty the completion fails with -ENOBUFS.
+//
+// For an incremental buffer ring the data lands after what earlier
+// receives left in the same buffer, and the completion carries
+// CQEFBufMore until the buffer is used up.
+func (r *Ring) Recv(bgid uint16, data []byte) CQE {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if br, ok := r.rings[bgid]; ok {
+		return br.recv(data)
+	}
+	bufs := r.provided[bgid]
+	if len(bufs) == 0 {
+		return CQE{Res: -ENOBUFS}
+	}
+	b := bufs[0]
+	r.provided[bgid] = bufs[1:]
+	n := copy(memAt(b.addr, int(b.len)), data)
+	return CQE{Res: int32(n), Flags: uint32(b.bid)<<16 | CQEFBuffer}
+}
+
+// Submit executes a provide or remove buffers operation immediately. Pass
+// it as the submit function of iobuf.NewProvidedBuffers or
+// iobuf.NewBufferGroup.
+func (r *Ring) Submit(op iobuf.ProvideBuffersOp) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if op.Remove {
+		bufs := r.provided[op.Bgid]
+		r.provided[op.Bgid] = bufs[min(int(op.Nbufs), len(bufs)):]
+		return nil
+	}
+	for i := range int(op.Nbufs) {
+		r.provided[op.Bgid] = append(r.provided[op.Bgid], providedBuf{
+			addr: op.Addr + uintptr(i)*uintptr(op.Len),
+			len:  op.Len,
+			bid:  op.Bid + uint16(i),
+		})
+	}
+	return nil
+}
+
+// RecvZC emulates a zero-copy receive of data into the area registered
+// with ID id. The data is placed in one free page of the area; at most a
+// page is received per call. Pages given back through the refill ring are
+// reclaimed first. If no page is free the completion fails with -ENOBUFS.
+// Panics if no area is registered with id.
+func (r *Ring) RecvZC(id uint32, data []byte) CQE {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if int(id) >= len(r.zcrx) {
+		panic("iobuftest.Ring.RecvZC: unknown zero-copy receive ID")
+	}
+	return r.zcrx[id].recv(data)
+}
+
+// RegisterBufRing implements internal.FakeRing.
+func (r *Ring) RegisterBufRing(mem []byte, entries int, bgid uint16, incremental bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rings[bgid]; ok {
+		return errExist
+	}
+	r.rings[bgid] = &bufRing{mem: mem, mask: uint16(entries - 1), inc: incremental}
+	return nil
+}
+
+// UnregisterBufRing implements internal.FakeRing.
+func (r *Ring) UnregisterBufRing(bgid uint16) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rings[bgid]; !ok {
+		return errNotExist
+	}
+	delete(r.rings, bgid)
+	return nil
+}
+
+// RegisterZcrx implements internal.FakeRing.
+func (r *Ring) RegisterZcrx(area, region []byte, entries uint32) (internal.ZcrxLayout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	q := &zcrxQueue{area: area, region: region, mask: entries - 1}
+	for off := len(area) - int(iobuf.PageSize); off >= 0; off -= int(iobuf.PageSize) {
+		q.free = append(q.free, uint64(off))
+	}
+	q.layout = internal.ZcrxLayout{Head: 0, Tail: 64, Rqes: 128, Entries: entries, ID: uint32(len(r.zcrx))}
+	r.zcrx = append(r.zcrx, q)
+	return q.layout, nil
+}
+
+// bufRing is the kernel side of a registered buffer ring.
+type bufRing struct {
+	mem  []byte
+	mask uint16
+	head uint16
+	inc  bool
+}
+
+// tail loads the tail the application published, reading the 32-bit word
+// it shares with the first entry's buffer ID.
+func (br *bufRing) tail() uint16 {
+	w := atomic.LoadUint32((*uint32)(unsafe.Pointer(&br.mem[12])))
+	return (*[2]uint16)(unsafe.Pointer(&w))[1]
+}
+
+func (br *bufRing) recv(data []byte) CQE {
+	if br.head == br.tail() {
+		return CQE{Res: -ENOBUFS}
+	}
+	ent := br.mem[int(br.head&br.mask)*16:]
+	addr := (*uint64)(unsafe.Pointer(&ent[0]))
+	size := (*uint32)(unsafe.Pointer(&ent[8]))
+	bid := *(*uint16)(unsafe.Pointer(&ent[12]))
+	n := copy(memAt(uintptr(*addr), int(*size)), data)
+	flags := uint32(bid)<<16 | CQEFBuffer
+	if br.inc && (n < int(*size) || n == 0) {
+		*addr += uint64(n)
+		*size -= uint32(n)
+		return CQE{Res: int32(n), Flags: flags | CQEFBufMore}
+	}
+	br.head++
+	return CQE{Res: int32(n), Flags: flags}
+}
+
+// providedBuf is a buffer added with IORING_OP_PROVIDE_BUFFERS.
+type providedBuf struct {
+	addr uintptr
+	len  uint32
+	bid  uint16
+}
+
+// zcrxQueue is the kernel side of a zero-copy receive interface queue.
+type zcrxQueue struct {
+	area   []byte
+	region []byte
+	layout internal.ZcrxLayout
+	mask   uint32
+	head   uint32
+	free   []uint64 // Free page offsets in the area
+}
+
+func (q *zcrxQueue) recv(data []byte) CQE {
+	q.refill()
+	if len(q.free) == 0 {
+		return CQE{Res: -ENOBUFS}
+	}
+	off := q.free[len(q.free)-1]
+	q.free = q.free[:len(q.free)-1]
+	n := copy(q.area[off:off+uint64(iobuf.PageSize)], data)
+	return CQE{Res: int32(n), Off: off | q.layout.Token}
+}
+
+// refill reclaims the pages the application queued on the refill ring.
+func (q *zcrxQueue) refill() {
+	tail := atomic.LoadUint32((*uint32)(unsafe.Pointer(&q.region[q.layout.Tail])))
+	for ; q.head != tail; q.head++ {
+		rqe := q.region[q.layout.Rqes+(q.head&q.mask)*16:]
+		off := *(*uint64)(unsafe.Pointer(&rqe[0])) & (1<<48 - 1)
+		q.free = append(q.free, off&^uint64(iobuf.PageSize-1))
+	}
+	atomic.StoreUint32((*uint32)(unsafe.Pointer(&q.region[q.layout.Head])), q.head)
+}
+
+// memAt returns the application memory at addr as a byte slice.
+func memAt(addr uintptr, n int) []byte {
+	return unsafe.Slice(*(**byte)(unsafe.Pointer(&addr)), n)
+}
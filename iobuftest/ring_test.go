@@ -0,0 +1,126 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuftest_test
+
+import (
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+	"code.hybscloud.com/iobuf/iobuftest"
+)
+
+func TestRing_BufRing(t *testing.T) {
+	ring := iobuftest.NewRing()
+	defer ring.Close()
+	pool := iobuf.NewSmallBufferPool(4)
+	pool.Fill(iobuf.NewSmallBuffer)
+	g, err := iobuf.NewBufRing(ring.Fd(), pool, 1, 0, false)
+	if err != nil {
+		t.Fatalf("NewBufRing() failed: %v", err)
+	}
+	defer g.Close()
+	r := iobuf.NewReplenisher(g, 2)
+	if _, err := r.Flush(); err != nil || ring.Available(1) != 4 {
+		t.Fatalf("Flush() = %v, Available() = %d", err, ring.Available(1))
+	}
+
+	for i := range 4 {
+		cqe := ring.Recv(1, []byte("packet"))
+		idx, data, final, err := r.OnCompletion(cqe.Res, cqe.Flags)
+		if err != nil || !final || string(data) != "packet" {
+			t.Fatalf("receive %d: %q, final %v, err %v", i, data, final, err)
+		}
+		_ = pool.Put(idx)
+	}
+	// Buffers came back in batches as the application returned them.
+	if ring.Available(1) == 0 {
+		t.Error("ring ran dry despite replenishment")
+	}
+	for ring.Available(1) > 0 {
+		ring.Recv(1, nil)
+	}
+	if cqe := ring.Recv(1, []byte("x")); cqe.Res != -iobuftest.ENOBUFS {
+		t.Errorf("Recv() on an empty group = %+v, want -ENOBUFS", cqe)
+	}
+}
+
+func TestRing_BufRingIncremental(t *testing.T) {
+	ring := iobuftest.NewRing()
+	defer ring.Close()
+	pool := iobuf.NewNanoBufferPool(1)
+	pool.Fill(iobuf.NewNanoBuffer)
+	g, err := iobuf.NewBufRing(ring.Fd(), pool, 2, 0, true)
+	if err != nil {
+		t.Fatalf("NewBufRing() failed: %v", err)
+	}
+	defer g.Close()
+	g.Replenish()
+
+	var got []byte
+	for {
+		cqe := ring.Recv(2, make([]byte, 50))
+		idx, data, final := g.Consume(cqe.BufferID(), int(cqe.Res), iobuf.CQEBufMore(cqe.Flags))
+		got = append(got, data...)
+		if final {
+			if idx != 0 {
+				t.Errorf("final Consume() index = %d, want 0", idx)
+			}
+			break
+		}
+	}
+	if len(got) != iobuf.BufferSizeNano {
+		t.Errorf("received %d bytes across completions, want the whole %d-byte buffer", len(got), iobuf.BufferSizeNano)
+	}
+}
+
+func TestRing_ProvidedBuffers(t *testing.T) {
+	ring := iobuftest.NewRing()
+	defer ring.Close()
+	pool := iobuf.NewSmallBufferPool(2)
+	pool.Fill(iobuf.NewSmallBuffer)
+	g := iobuf.NewProvidedBuffers(pool, 3, ring.Submit)
+	g.Replenish()
+	cqe := ring.Recv(3, []byte("hi"))
+	if idx, data := g.Completed(cqe.BufferID(), int(cqe.Res)); string(data) != "hi" || &data[0] != &pool.Bytes(idx)[0] {
+		t.Errorf("Completed() = %d, %q", idx, data)
+	}
+	if err := g.Close(); err != nil || ring.Available(3) != 0 {
+		t.Errorf("Close() = %v, Available() = %d", err, ring.Available(3))
+	}
+}
+
+func TestRing_Zcrx(t *testing.T) {
+	ring := iobuftest.NewRing()
+	defer ring.Close()
+	z, err := iobuf.NewZcrxArea(2*int(iobuf.PageSize), 4, nil)
+	if err != nil {
+		t.Fatalf("NewZcrxArea() failed: %v", err)
+	}
+	defer z.Close()
+	if err := z.Register(ring.Fd(), 0, 0); err != nil {
+		t.Fatalf("Register() failed: %v", err)
+	}
+
+	a := ring.RecvZC(z.ID(), []byte("first"))
+	b := ring.RecvZC(z.ID(), []byte("second"))
+	if string(z.Received(a.Off, int(a.Res))) != "first" || string(z.Received(b.Off, int(b.Res))) != "second" {
+		t.Fatal("Received() does not see the data the mock placed")
+	}
+	if c := ring.RecvZC(z.ID(), []byte("x")); c.Res != -iobuftest.ENOBUFS {
+		t.Fatalf("RecvZC() with the area full = %+v, want -ENOBUFS", c)
+	}
+
+	// Recycling a page makes it available again once flushed.
+	if err := z.Recycle(a.Off, int(a.Res)); err != nil {
+		t.Fatal(err)
+	}
+	if c := ring.RecvZC(z.ID(), []byte("x")); c.Res != -iobuftest.ENOBUFS {
+		t.Fatal("recycled page reused before Flush")
+	}
+	z.Flush()
+	if c := ring.RecvZC(z.ID(), []byte("third")); c.Res != 5 || c.Off != a.Off {
+		t.Errorf("RecvZC() after recycling = %+v, want the recycled page", c)
+	}
+}
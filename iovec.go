@@ -5,6 +5,7 @@
 package iobuf
 
 import (
+	"iter"
 	"unsafe"
 )
 
@@ -27,13 +28,18 @@ type IoVec struct {
 	Len  uint64 // Number of bytes to transfer
 }
 
+// IovMax is the maximum number of elements a single vectored I/O system
+// call accepts on Linux (IOV_MAX).
+const IovMax = 1024
+
 // IoVecFromBytesSlice converts a slice of byte slices to a pointer and count
 // suitable for io_uring buffer registration (IORING_REGISTER_BUFFERS2).
 // Returns the address of the first IoVec element and the number of elements.
 //
-// Note: The returned address points to a newly allocated []IoVec slice.
-// The caller must ensure the input slices remain valid for the lifetime
-// of the registration.
+// Note: The returned address points to a newly allocated []IoVec slice
+// that nothing keeps reachable, and the caller must ensure the input
+// slices remain valid for the lifetime of the registration. Prefer
+// NewIoVecHandle, which retains both.
 func IoVecFromBytesSlice(iov [][]byte) (addr uintptr, n int) {
 	if len(iov) == 0 {
 		return 0, 0
@@ -46,6 +52,39 @@ func IoVecFromBytesSlice(iov [][]byte) (addr uintptr, n int) {
 	return
 }
 
+// IoVecFromStrings converts strings to an IoVec slice without copying
+// their bytes, for write-only system calls such as writev and sendmsg
+// carrying protocol literals and header strings.
+//
+// The kernel must only read through the result: string memory is
+// immutable and may be read-only, so it must never be the target of a
+// read, recvmsg or any other operation that writes into the vector.
+func IoVecFromStrings(strs []string) []IoVec {
+	if len(strs) == 0 {
+		return nil
+	}
+	vec := make([]IoVec, len(strs))
+	for i, s := range strs {
+		vec[i] = IoVec{Base: unsafe.StringData(s), Len: uint64(len(s))}
+	}
+	return vec
+}
+
+// BytesSliceFromIoVec returns a byte slice view of each element of vec
+// without copying, for handing kernel-filled vectors to code that speaks
+// [][]byte or net.Buffers. It is the inverse of IoVecFromBytesSlice.
+// Returns nil for an empty vector.
+func BytesSliceFromIoVec(vec []IoVec) [][]byte {
+	if len(vec) == 0 {
+		return nil
+	}
+	bufs := make([][]byte, len(vec))
+	for i, v := range vec {
+		bufs[i] = unsafe.Slice(v.Base, v.Len)
+	}
+	return bufs
+}
+
 // IoVecAddrLen extracts the raw pointer and length from an IoVec slice
 // for direct syscall consumption (readv, writev, io_uring submission).
 //
@@ -58,171 +97,290 @@ func IoVecAddrLen(vec []IoVec) (addr uintptr, n int) {
 	return
 }
 
-// IoVecFromPicoBuffers converts a slice of PicoBuffer to an IoVec slice.
-// The returned IoVec elements point directly to the buffer memory without copying.
-func IoVecFromPicoBuffers(buffers []PicoBuffer) []IoVec {
+// IoVecFromBuffers converts a slice of tier buffers of any size to an
+// IoVec slice. The returned IoVec elements point directly to the buffer
+// memory without copying, and each element covers a whole buffer.
+func IoVecFromBuffers[T BufferType](buffers []T) []IoVec {
 	if len(buffers) == 0 {
 		return nil
 	}
+	size := uint64(unsafe.Sizeof(buffers[0]))
 	vec := make([]IoVec, len(buffers))
 	for i := range len(buffers) {
-		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: BufferSizePico}
+		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: size}
 	}
 	return vec
 }
 
-// IoVecFromNanoBuffers converts a slice of NanoBuffer to an IoVec slice.
-// The returned IoVec elements point directly to the buffer memory without copying.
-func IoVecFromNanoBuffers(buffers []NanoBuffer) []IoVec {
-	if len(buffers) == 0 {
-		return nil
+// IoVecOf returns an IoVec for the n bytes of buf starting at off, e.g., to
+// retransmit the unacknowledged tail of a buffer or skip a header. An
+// empty range yields the zero IoVec. Panics if the range is outside the
+// buffer.
+func IoVecOf[T BufferType](buf *T, off, n int) IoVec {
+	size := int(unsafe.Sizeof(*buf))
+	if off < 0 || n < 0 || off > size || n > size-off {
+		panic("iobuf.IoVecOf: range out of bounds")
 	}
-	vec := make([]IoVec, len(buffers))
-	for i := range len(buffers) {
-		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: BufferSizeNano}
+	if n == 0 {
+		return IoVec{}
 	}
-	return vec
+	return IoVec{Base: (*byte)(unsafe.Add(unsafe.Pointer(buf), off)), Len: uint64(n)}
 }
 
-// IoVecFromMicroBuffers converts a slice of MicroBuffer to an IoVec slice.
-// The returned IoVec elements point directly to the buffer memory without copying.
-func IoVecFromMicroBuffers(buffers []MicroBuffer) []IoVec {
+// IoVecFromBuffersN is like IoVecFromBuffers but element i covers only the
+// first lens[i] bytes of buffers[i], so partially filled buffers, such as
+// a final short packet, describe their actual payload.
+// Panics if len(lens) != len(buffers) or a length is negative or exceeds
+// the buffer size.
+func IoVecFromBuffersN[T BufferType](buffers []T, lens []int) []IoVec {
+	if len(lens) != len(buffers) {
+		panic("iobuf.IoVecFromBuffersN: lens must match buffers")
+	}
 	if len(buffers) == 0 {
 		return nil
 	}
+	size := int(unsafe.Sizeof(buffers[0]))
 	vec := make([]IoVec, len(buffers))
-	for i := range len(buffers) {
-		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: BufferSizeMicro}
+	for i, n := range lens {
+		if n < 0 || n > size {
+			panic("iobuf.IoVecFromBuffersN: length out of range")
+		}
+		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: uint64(n)}
 	}
 	return vec
 }
 
+// IoVecFromPicoBuffers converts a slice of PicoBuffer to an IoVec slice.
+// It is IoVecFromBuffers instantiated for PicoBuffer.
+func IoVecFromPicoBuffers(buffers []PicoBuffer) []IoVec {
+	return IoVecFromBuffers(buffers)
+}
+
+// IoVecFromNanoBuffers converts a slice of NanoBuffer to an IoVec slice.
+// It is IoVecFromBuffers instantiated for NanoBuffer.
+func IoVecFromNanoBuffers(buffers []NanoBuffer) []IoVec {
+	return IoVecFromBuffers(buffers)
+}
+
+// IoVecFromMicroBuffers converts a slice of MicroBuffer to an IoVec slice.
+// It is IoVecFromBuffers instantiated for MicroBuffer.
+func IoVecFromMicroBuffers(buffers []MicroBuffer) []IoVec {
+	return IoVecFromBuffers(buffers)
+}
+
 // IoVecFromSmallBuffers converts a slice of SmallBuffer to an IoVec slice.
-// The returned IoVec elements point directly to the buffer memory without copying.
+// It is IoVecFromBuffers instantiated for SmallBuffer.
 func IoVecFromSmallBuffers(buffers []SmallBuffer) []IoVec {
-	if len(buffers) == 0 {
-		return nil
-	}
-	vec := make([]IoVec, len(buffers))
-	for i := range len(buffers) {
-		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: BufferSizeSmall}
-	}
-	return vec
+	return IoVecFromBuffers(buffers)
 }
 
 // IoVecFromMediumBuffers converts a slice of MediumBuffer to an IoVec slice.
-// The returned IoVec elements point directly to the buffer memory without copying.
+// It is IoVecFromBuffers instantiated for MediumBuffer.
 func IoVecFromMediumBuffers(buffers []MediumBuffer) []IoVec {
-	if len(buffers) == 0 {
-		return nil
-	}
-	vec := make([]IoVec, len(buffers))
-	for i := range len(buffers) {
-		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: BufferSizeMedium}
-	}
-	return vec
+	return IoVecFromBuffers(buffers)
 }
 
 // IoVecFromBigBuffers converts a slice of BigBuffer to an IoVec slice.
-// The returned IoVec elements point directly to the buffer memory without copying.
+// It is IoVecFromBuffers instantiated for BigBuffer.
 func IoVecFromBigBuffers(buffers []BigBuffer) []IoVec {
-	if len(buffers) == 0 {
-		return nil
-	}
-	vec := make([]IoVec, len(buffers))
-	for i := range len(buffers) {
-		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: BufferSizeBig}
-	}
-	return vec
+	return IoVecFromBuffers(buffers)
 }
 
 // IoVecFromLargeBuffers converts a slice of LargeBuffer to an IoVec slice.
-// The returned IoVec elements point directly to the buffer memory without copying.
+// It is IoVecFromBuffers instantiated for LargeBuffer.
 func IoVecFromLargeBuffers(buffers []LargeBuffer) []IoVec {
-	if len(buffers) == 0 {
-		return nil
-	}
-	vec := make([]IoVec, len(buffers))
-	for i := range len(buffers) {
-		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: BufferSizeLarge}
-	}
-	return vec
+	return IoVecFromBuffers(buffers)
 }
 
 // IoVecFromGreatBuffers converts a slice of GreatBuffer to an IoVec slice.
-// The returned IoVec elements point directly to the buffer memory without copying.
+// It is IoVecFromBuffers instantiated for GreatBuffer.
 func IoVecFromGreatBuffers(buffers []GreatBuffer) []IoVec {
-	if len(buffers) == 0 {
-		return nil
-	}
-	vec := make([]IoVec, len(buffers))
-	for i := range len(buffers) {
-		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: BufferSizeGreat}
-	}
-	return vec
+	return IoVecFromBuffers(buffers)
 }
 
 // IoVecFromHugeBuffers converts a slice of HugeBuffer to an IoVec slice.
-// The returned IoVec elements point directly to the buffer memory without copying.
+// It is IoVecFromBuffers instantiated for HugeBuffer.
 func IoVecFromHugeBuffers(buffers []HugeBuffer) []IoVec {
-	if len(buffers) == 0 {
-		return nil
-	}
-	vec := make([]IoVec, len(buffers))
-	for i := range len(buffers) {
-		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: BufferSizeHuge}
-	}
-	return vec
+	return IoVecFromBuffers(buffers)
 }
 
 // IoVecFromVastBuffers converts a slice of VastBuffer to an IoVec slice.
-// The returned IoVec elements point directly to the buffer memory without copying.
+// It is IoVecFromBuffers instantiated for VastBuffer.
 func IoVecFromVastBuffers(buffers []VastBuffer) []IoVec {
-	if len(buffers) == 0 {
-		return nil
-	}
-	vec := make([]IoVec, len(buffers))
-	for i := range len(buffers) {
-		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: BufferSizeVast}
-	}
-	return vec
+	return IoVecFromBuffers(buffers)
 }
 
 // IoVecFromGiantBuffers converts a slice of GiantBuffer to an IoVec slice.
-// The returned IoVec elements point directly to the buffer memory without copying.
+// It is IoVecFromBuffers instantiated for GiantBuffer.
 func IoVecFromGiantBuffers(buffers []GiantBuffer) []IoVec {
+	return IoVecFromBuffers(buffers)
+}
+
+// IoVecFromTitanBuffers converts a slice of TitanBuffer to an IoVec slice.
+// It is IoVecFromBuffers instantiated for TitanBuffer.
+func IoVecFromTitanBuffers(buffers []TitanBuffer) []IoVec {
+	return IoVecFromBuffers(buffers)
+}
+
+// IoVecFromRegisteredBuffers converts a slice of RegisterBuffer to an IoVec slice.
+// The returned IoVec elements point directly to the buffer memory without copying.
+func IoVecFromRegisteredBuffers(buffers []RegisterBuffer) []IoVec {
 	if len(buffers) == 0 {
 		return nil
 	}
 	vec := make([]IoVec, len(buffers))
 	for i := range len(buffers) {
-		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: BufferSizeGiant}
+		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: registerBufferSize}
 	}
 	return vec
 }
 
-// IoVecFromTitanBuffers converts a slice of TitanBuffer to an IoVec slice.
-// The returned IoVec elements point directly to the buffer memory without copying.
-func IoVecFromTitanBuffers(buffers []TitanBuffer) []IoVec {
-	if len(buffers) == 0 {
+// IoVecFromPool builds an IoVec slice describing the pooled buffers at the
+// given indirect indices.
+//
+// It works for any BufferPool, including pools of registered custom size
+// classes, and each element covers the full buffer. The caller must hold
+// the indices for the lifetime of any I/O operation using the result.
+func IoVecFromPool(pool BufferPool, indices []int) []IoVec {
+	if len(indices) == 0 {
 		return nil
 	}
-	vec := make([]IoVec, len(buffers))
-	for i := range len(buffers) {
-		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: BufferSizeTitan}
+	vec := make([]IoVec, len(indices))
+	for i, indirect := range indices {
+		b := pool.Bytes(indirect)
+		vec[i] = IoVec{Base: unsafe.SliceData(b), Len: uint64(len(b))}
 	}
 	return vec
 }
 
-// IoVecFromRegisteredBuffers converts a slice of RegisterBuffer to an IoVec slice.
-// The returned IoVec elements point directly to the buffer memory without copying.
-func IoVecFromRegisteredBuffers(buffers []RegisterBuffer) []IoVec {
-	if len(buffers) == 0 {
-		return nil
+// IoVecAdvance consumes n bytes from the front of vec, as after a short
+// writev or readv, and returns the remaining elements. Fully consumed
+// elements are dropped and the first unfinished element has its Base and
+// Len adjusted in place, mirroring net.Buffers. Elements are modified, not
+// copied, so callers retrying a partial transfer pass the result directly.
+// Panics if n is negative or exceeds the total length of vec.
+func IoVecAdvance(vec []IoVec, n int) []IoVec {
+	if n < 0 {
+		panic("iobuf.IoVecAdvance: negative count")
 	}
-	vec := make([]IoVec, len(buffers))
-	for i := range len(buffers) {
-		vec[i] = IoVec{Base: (*byte)(unsafe.Pointer(&buffers[i])), Len: registerBufferSize}
+	for n > 0 {
+		if len(vec) == 0 {
+			panic("iobuf.IoVecAdvance: count exceeds vector length")
+		}
+		if uint64(n) < vec[0].Len {
+			vec[0].Base = (*byte)(unsafe.Add(unsafe.Pointer(vec[0].Base), n))
+			vec[0].Len -= uint64(n)
+			return vec
+		}
+		n -= int(vec[0].Len)
+		vec = vec[1:]
 	}
 	return vec
 }
+
+// IoVecCoalesce merges elements whose memory is contiguous, so buffers
+// carved back to back from the same arena or array cost one iovec slot
+// instead of several. Empty elements are dropped. The vector is compacted
+// in place and the shortened slice is returned.
+func IoVecCoalesce(vec []IoVec) []IoVec {
+	k := 0
+	for _, v := range vec {
+		if v.Len == 0 {
+			continue
+		}
+		if k > 0 && unsafe.Add(unsafe.Pointer(vec[k-1].Base), vec[k-1].Len) == unsafe.Pointer(v.Base) {
+			vec[k-1].Len += v.Len
+			continue
+		}
+		vec[k] = v
+		k++
+	}
+	return vec[:k]
+}
+
+// IoVecSplit divides vec into a head of at most maxSegs elements and
+// maxBytes bytes, suitable for one system call, and the tail that remains.
+// A non-positive limit leaves that dimension unbounded; pass IovMax as
+// maxSegs to respect the kernel's element limit.
+//
+// When the byte limit falls inside an element, the element is split: the
+// head ends with its first part and the tail starts with the rest. The
+// head is then a new slice, so vec is modified only at the split element
+// and head and tail never alias an element.
+func IoVecSplit(vec []IoVec, maxBytes, maxSegs int) (head, tail []IoVec) {
+	if maxSegs <= 0 || maxSegs > len(vec) {
+		maxSegs = len(vec)
+	}
+	if maxBytes <= 0 {
+		return vec[:maxSegs], vec[maxSegs:]
+	}
+	budget := uint64(maxBytes)
+	for k := range maxSegs {
+		if vec[k].Len <= budget {
+			budget -= vec[k].Len
+			continue
+		}
+		if budget == 0 {
+			return vec[:k], vec[k:]
+		}
+		head = append(vec[:k:k], IoVec{Base: vec[k].Base, Len: budget})
+		vec[k].Base = (*byte)(unsafe.Add(unsafe.Pointer(vec[k].Base), budget))
+		vec[k].Len -= budget
+		return head, vec[k:]
+	}
+	return vec[:maxSegs], vec[maxSegs:]
+}
+
+// IoVecsAs returns vec viewed as a slice of V without copying, for passing
+// vectors to wrappers that declare their own struct iovec, such as
+// golang.org/x/sys/unix.Iovec. V must have the layout of struct iovec: a
+// base pointer followed by a 64-bit length.
+// Panics if V differs in size from IoVec.
+func IoVecsAs[V any](vec []IoVec) []V {
+	if unsafe.Sizeof(*new(V)) != unsafe.Sizeof(IoVec{}) {
+		panic("iobuf.IoVecsAs: type is not layout-compatible with IoVec")
+	}
+	return unsafe.Slice((*V)(unsafe.Pointer(unsafe.SliceData(vec))), len(vec))
+}
+
+// GatherCopy copies the bytes described by vec, in order, into dst and
+// returns the number of bytes copied, which is the smaller of len(dst) and
+// the total length of vec.
+func GatherCopy(dst []byte, vec []IoVec) int {
+	n := 0
+	for _, v := range vec {
+		if n == len(dst) {
+			break
+		}
+		n += copy(dst[n:], unsafe.Slice(v.Base, v.Len))
+	}
+	return n
+}
+
+// ScatterCopy copies src into the memory described by vec, filling the
+// elements in order, and returns the number of bytes copied, which is the
+// smaller of len(src) and the total length of vec.
+func ScatterCopy(vec []IoVec, src []byte) int {
+	n := 0
+	for _, v := range vec {
+		if n == len(src) {
+			break
+		}
+		n += copy(unsafe.Slice(v.Base, v.Len), src[n:])
+	}
+	return n
+}
+
+// IoVecBytes returns an iterator over the elements of vec as byte slices
+// viewing the described memory, so checksum or compression code can walk
+// a vector without unsafe code at each call site. Empty elements yield
+// empty slices.
+func IoVecBytes(vec []IoVec) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		for _, v := range vec {
+			if !yield(unsafe.Slice(v.Base, v.Len)) {
+				return
+			}
+		}
+	}
+}
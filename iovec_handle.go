@@ -0,0 +1,48 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "unsafe"
+
+// IoVecHandle owns a vector built from byte slices together with the
+// slices themselves, so both stay reachable for as long as the kernel may
+// use them, e.g., for the lifetime of an io_uring buffer registration.
+//
+// A bare uintptr from IoVecFromBytesSlice does not keep the vector alive;
+// the handle makes that contract explicit. Keep the handle reachable until
+// the kernel is done, then call Close.
+type IoVecHandle struct {
+	vec []IoVec
+	src [][]byte
+}
+
+// NewIoVecHandle builds a vector describing iov. The vector comes from
+// the AcquireVec cache and returns there on Close.
+func NewIoVecHandle(iov [][]byte) *IoVecHandle {
+	vec := AcquireVec(len(iov))
+	for i, b := range iov {
+		vec[i] = IoVec{Base: unsafe.SliceData(b), Len: uint64(len(b))}
+	}
+	return &IoVecHandle{vec: vec, src: iov}
+}
+
+// AddrLen returns the address of the first IoVec element and the number of
+// elements, for direct system call or io_uring use. Returns (0, 0) for an
+// empty or closed handle.
+func (h *IoVecHandle) AddrLen() (addr uintptr, n int) {
+	return IoVecAddrLen(h.vec)
+}
+
+// Vec returns the vector. It is valid until Close.
+func (h *IoVecHandle) Vec() []IoVec { return h.vec }
+
+// Close releases the vector and the references to the source slices.
+// The address returned by AddrLen must not be used afterwards.
+// Closing a closed handle is a no-op.
+func (h *IoVecHandle) Close() error {
+	ReleaseVec(h.vec)
+	h.vec, h.src = nil, nil
+	return nil
+}
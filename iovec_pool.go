@@ -0,0 +1,64 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"math/bits"
+	"sync"
+	"unsafe"
+)
+
+// ioVecClasses is the number of pooled vector length classes: powers of
+// two from 1 to IovMax.
+const ioVecClasses = 11
+
+// ioVecPools caches vector backing arrays per length class. Entries are
+// stored as pointers to the first element, so putting an array back does
+// not allocate a slice header.
+var ioVecPools [ioVecClasses]sync.Pool
+
+// AcquireVec returns an IoVec slice of length n from a shared cache of
+// backing arrays, so high-rate writev paths do not allocate a vector per
+// system call. The capacity is n rounded up to a power of two. Vectors
+// longer than IovMax are allocated directly.
+//
+// Return the vector with ReleaseVec once the system call has completed.
+// Panics if n < 0.
+func AcquireVec(n int) []IoVec {
+	if n < 0 {
+		panic("iobuf.AcquireVec: negative length")
+	}
+	if n == 0 {
+		return nil
+	}
+	if n > IovMax {
+		return make([]IoVec, n)
+	}
+	class := bits.Len(uint(n - 1))
+	if p, ok := ioVecPools[class].Get().(*IoVec); ok {
+		return unsafe.Slice(p, 1<<class)[:n]
+	}
+	return make([]IoVec, n, 1<<class)
+}
+
+// ReleaseVec returns a vector obtained from AcquireVec to the cache. The
+// elements are cleared so the cache does not keep buffers reachable. The
+// vector must not be used afterwards.
+//
+// ReleaseVec cannot tell where a vector came from: any vector whose
+// capacity is a power of two up to IovMax is cached, and others are
+// ignored. Pass the vector as returned by AcquireVec, or resliced from
+// its first element, and release it once. A subslice starting past the
+// first element, such as vec[4:] of an 8-element vector, may be cached
+// as an array of its own while it still aliases vec.
+func ReleaseVec(vec []IoVec) {
+	c := cap(vec)
+	if c == 0 || c > IovMax || c&(c-1) != 0 {
+		return
+	}
+	vec = vec[:c]
+	clear(vec)
+	ioVecPools[bits.Len(uint(c-1))].Put(unsafe.SliceData(vec))
+}
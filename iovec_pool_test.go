@@ -0,0 +1,68 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestAcquireVec(t *testing.T) {
+	if vec := iobuf.AcquireVec(0); vec != nil {
+		t.Errorf("AcquireVec(0) = %v, want nil", vec)
+	}
+	for _, n := range []int{1, 3, 16, 1000, iobuf.IovMax, iobuf.IovMax + 1} {
+		vec := iobuf.AcquireVec(n)
+		if len(vec) != n || cap(vec) < n {
+			t.Errorf("AcquireVec(%d) len=%d cap=%d", n, len(vec), cap(vec))
+		}
+		b := []byte{1}
+		vec[0] = iobuf.IoVec{Base: &b[0], Len: 1}
+		iobuf.ReleaseVec(vec)
+	}
+
+	// A released array comes back cleared.
+	for range 10 {
+		vec := iobuf.AcquireVec(5)
+		if cap(vec) != 8 {
+			t.Fatalf("AcquireVec(5) cap = %d, want 8", cap(vec))
+		}
+		for i, v := range vec[:cap(vec)] {
+			if v != (iobuf.IoVec{}) {
+				t.Fatalf("reused vector element %d = %+v, want zero", i, v)
+			}
+		}
+		b := []byte{1}
+		vec[4] = iobuf.IoVec{Base: &b[0], Len: 1}
+		iobuf.ReleaseVec(vec)
+	}
+	iobuf.ReleaseVec(make([]iobuf.IoVec, 3)) // odd capacities are ignored
+}
+
+func TestReleaseVec_Subslice(t *testing.T) {
+	vec := iobuf.AcquireVec(8)
+	b := []byte{1}
+	for i := range vec {
+		vec[i] = iobuf.IoVec{Base: &b[0], Len: 1}
+	}
+
+	// vec[2:] has capacity 6, matches no length class and is ignored, so
+	// its elements are left as they are.
+	iobuf.ReleaseVec(vec[2:])
+	for i, v := range vec {
+		if v.Len != 1 {
+			t.Fatalf("element %d cleared by ReleaseVec of a subslice", i)
+		}
+	}
+
+	// Resliced from the first element, the vector is released whole.
+	iobuf.ReleaseVec(vec[:3])
+	for i, v := range vec {
+		if v != (iobuf.IoVec{}) {
+			t.Fatalf("element %d = %+v after ReleaseVec, want zero", i, v)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"encoding/binary"
+	"io"
+	"unsafe"
+)
+
+// ioVecRefSize is the encoded size of one IoVecRef.
+const ioVecRefSize = 4 + 8 + 8
+
+// IoVecRef locates an iovec element by region and offset instead of by
+// address, so a vector over shared memory, such as a SharedMem mapped by
+// cooperating processes, can be sent to a peer and rebuilt there.
+//
+// Region is an index into a region table both sides agree on: the same
+// shared regions in the same order, each mapped at whatever address the
+// local process chose.
+type IoVecRef struct {
+	Region uint32
+	Offset uint64
+	Len    uint64
+}
+
+// IoVecToRefs expresses every element of vec relative to the region that
+// contains it. regions is the local region table, e.g., the Bytes of each
+// SharedMem. Returns an *IoVecError if an element is not wholly inside one
+// of the regions.
+func IoVecToRefs(vec []IoVec, regions [][]byte) ([]IoVecRef, error) {
+	refs := make([]IoVecRef, len(vec))
+	for i, v := range vec {
+		p := uintptr(unsafe.Pointer(v.Base))
+		found := false
+		for r, mem := range regions {
+			base := uintptr(unsafe.Pointer(unsafe.SliceData(mem)))
+			if len(mem) == 0 || p < base || p-base > uintptr(len(mem)) || v.Len > uint64(uintptr(len(mem))-(p-base)) {
+				continue
+			}
+			refs[i] = IoVecRef{Region: uint32(r), Offset: uint64(p - base), Len: v.Len}
+			found = true
+			break
+		}
+		if !found {
+			return nil, &IoVecError{Index: i, Reason: "not inside a shared region"}
+		}
+	}
+	return refs, nil
+}
+
+// IoVecFromRefs rebuilds a vector from refs against the local region
+// table. Returns an *IoVecError if a ref names an unknown region or
+// reaches past the end of its region.
+func IoVecFromRefs(refs []IoVecRef, regions [][]byte) ([]IoVec, error) {
+	vec := make([]IoVec, len(refs))
+	for i, ref := range refs {
+		if int64(ref.Region) >= int64(len(regions)) {
+			return nil, &IoVecError{Index: i, Reason: "unknown region"}
+		}
+		mem := regions[ref.Region]
+		if ref.Offset > uint64(len(mem)) || ref.Len > uint64(len(mem))-ref.Offset {
+			return nil, &IoVecError{Index: i, Reason: "range outside its region"}
+		}
+		if ref.Len > 0 {
+			vec[i] = IoVec{Base: &mem[ref.Offset], Len: ref.Len}
+		}
+	}
+	return vec, nil
+}
+
+// AppendIoVecRefs appends the wire encoding of refs to dst: a little-endian
+// uint32 count followed by each ref as region, offset and length.
+func AppendIoVecRefs(dst []byte, refs []IoVecRef) []byte {
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(refs)))
+	for _, ref := range refs {
+		dst = binary.LittleEndian.AppendUint32(dst, ref.Region)
+		dst = binary.LittleEndian.AppendUint64(dst, ref.Offset)
+		dst = binary.LittleEndian.AppendUint64(dst, ref.Len)
+	}
+	return dst
+}
+
+// DecodeIoVecRefs decodes refs encoded by AppendIoVecRefs from the start
+// of b and returns them with the number of bytes consumed. Returns
+// io.ErrUnexpectedEOF if b is truncated.
+func DecodeIoVecRefs(b []byte) (refs []IoVecRef, n int, err error) {
+	if len(b) < 4 {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	count := binary.LittleEndian.Uint32(b)
+	if uint64(len(b)-4) < uint64(count)*ioVecRefSize {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	refs = make([]IoVecRef, count)
+	b = b[4:]
+	for i := range refs {
+		refs[i] = IoVecRef{
+			Region: binary.LittleEndian.Uint32(b),
+			Offset: binary.LittleEndian.Uint64(b[4:]),
+			Len:    binary.LittleEndian.Uint64(b[12:]),
+		}
+		b = b[ioVecRefSize:]
+	}
+	return refs, 4 + int(count)*ioVecRefSize, nil
+}
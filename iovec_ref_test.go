@@ -0,0 +1,68 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestIoVecRefs_RoundTrip(t *testing.T) {
+	r0, r1 := make([]byte, 64), make([]byte, 128)
+	copy(r0[10:], "hello")
+	copy(r1[100:], "world")
+	vec := []iobuf.IoVec{{Base: &r0[10], Len: 5}, {Base: &r1[100], Len: 5}}
+
+	refs, err := iobuf.IoVecToRefs(vec, [][]byte{r0, r1})
+	if err != nil {
+		t.Fatalf("IoVecToRefs() failed: %v", err)
+	}
+	want := []iobuf.IoVecRef{{Region: 0, Offset: 10, Len: 5}, {Region: 1, Offset: 100, Len: 5}}
+	for i := range want {
+		if refs[i] != want[i] {
+			t.Errorf("refs[%d] = %+v, want %+v", i, refs[i], want[i])
+		}
+	}
+
+	wire := iobuf.AppendIoVecRefs([]byte("hdr"), refs)
+	decoded, n, err := iobuf.DecodeIoVecRefs(wire[3:])
+	if err != nil || n != len(wire)-3 || len(decoded) != 2 {
+		t.Fatalf("DecodeIoVecRefs() = %v, %d, %v", decoded, n, err)
+	}
+	if _, _, err := iobuf.DecodeIoVecRefs(wire[3 : len(wire)-1]); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("DecodeIoVecRefs(truncated) error = %v, want io.ErrUnexpectedEOF", err)
+	}
+
+	// The peer maps the same regions at other addresses.
+	p0, p1 := append([]byte(nil), r0...), append([]byte(nil), r1...)
+	back, err := iobuf.IoVecFromRefs(decoded, [][]byte{p0, p1})
+	if err != nil {
+		t.Fatalf("IoVecFromRefs() failed: %v", err)
+	}
+	dst := make([]byte, 10)
+	if n := iobuf.GatherCopy(dst, back); string(dst[:n]) != "helloworld" || back[0].Base != &p0[10] {
+		t.Errorf("rebuilt vector reads %q", dst[:n])
+	}
+}
+
+func TestIoVecRefs_Errors(t *testing.T) {
+	region := make([]byte, 16)
+	other := make([]byte, 4)
+	if _, err := iobuf.IoVecToRefs([]iobuf.IoVec{{Base: &other[0], Len: 4}}, [][]byte{region}); !errors.Is(err, iobuf.ErrInvalidIoVec) {
+		t.Errorf("IoVecToRefs(foreign) error = %v, want ErrInvalidIoVec", err)
+	}
+	if _, err := iobuf.IoVecToRefs([]iobuf.IoVec{{Base: &region[8], Len: 9}}, [][]byte{region}); !errors.Is(err, iobuf.ErrInvalidIoVec) {
+		t.Errorf("IoVecToRefs(overrun) error = %v, want ErrInvalidIoVec", err)
+	}
+	if _, err := iobuf.IoVecFromRefs([]iobuf.IoVecRef{{Region: 1}}, [][]byte{region}); !errors.Is(err, iobuf.ErrInvalidIoVec) {
+		t.Errorf("IoVecFromRefs(unknown region) error = %v, want ErrInvalidIoVec", err)
+	}
+	if _, err := iobuf.IoVecFromRefs([]iobuf.IoVecRef{{Offset: 10, Len: 7}}, [][]byte{region}); !errors.Is(err, iobuf.ErrInvalidIoVec) {
+		t.Errorf("IoVecFromRefs(overrun) error = %v, want ErrInvalidIoVec", err)
+	}
+}
@@ -5,6 +5,8 @@
 package iobuf_test
 
 import (
+	"errors"
+	"math"
 	"testing"
 	"unsafe"
 
@@ -50,6 +52,17 @@ func TestIoVecFromBytesSlice(t *testing.T) {
 	})
 }
 
+func TestBytesSliceFromIoVec(t *testing.T) {
+	if bufs := iobuf.BytesSliceFromIoVec(nil); bufs != nil {
+		t.Error("expected nil for empty input")
+	}
+	a, b := []byte("net."), []byte("Buffers")
+	bufs := iobuf.BytesSliceFromIoVec([]iobuf.IoVec{{Base: &a[0], Len: 4}, {}, {Base: &b[0], Len: 7}})
+	if len(bufs) != 3 || &bufs[0][0] != &a[0] || len(bufs[1]) != 0 || string(bufs[2]) != "Buffers" {
+		t.Errorf("BytesSliceFromIoVec() = %q", bufs)
+	}
+}
+
 func TestIoVecAddrLen(t *testing.T) {
 	t.Run("empty slice", func(t *testing.T) {
 		addr, n := iobuf.IoVecAddrLen(nil)
@@ -74,6 +87,83 @@ func TestIoVecAddrLen(t *testing.T) {
 	})
 }
 
+func TestIoVecFromStrings(t *testing.T) {
+	if vec := iobuf.IoVecFromStrings(nil); vec != nil {
+		t.Error("expected nil for empty input")
+	}
+	strs := []string{"HTTP/1.1 200 OK\r\n", "", "Content-Length: 0\r\n\r\n"}
+	vec := iobuf.IoVecFromStrings(strs)
+	dst := make([]byte, 64)
+	n := iobuf.GatherCopy(dst, vec)
+	if want := strs[0] + strs[2]; string(dst[:n]) != want {
+		t.Errorf("vector contents = %q, want %q", dst[:n], want)
+	}
+	if vec[0].Base != unsafe.StringData(strs[0]) {
+		t.Error("IoVecFromStrings() copied the string")
+	}
+}
+
+func TestIoVecFromBuffers(t *testing.T) {
+	if vec := iobuf.IoVecFromBuffers[iobuf.SmallBuffer](nil); vec != nil {
+		t.Error("expected nil for empty input")
+	}
+	buffers := make([]iobuf.SmallBuffer, 3)
+	vec := iobuf.IoVecFromBuffers(buffers)
+	if len(vec) != 3 {
+		t.Fatalf("expected len=3, got %d", len(vec))
+	}
+	for i, v := range vec {
+		if v.Base != &buffers[i][0] || v.Len != iobuf.BufferSizeSmall {
+			t.Errorf("vec[%d] = {%p, %d}, want {%p, %d}", i, v.Base, v.Len, &buffers[i][0], iobuf.BufferSizeSmall)
+		}
+	}
+}
+
+func TestIoVecOf(t *testing.T) {
+	var buf iobuf.MediumBuffer
+	v := iobuf.IoVecOf(&buf, 40, 100)
+	if v.Base != &buf[40] || v.Len != 100 {
+		t.Errorf("IoVecOf(40, 100) = {%p, %d}, want {%p, 100}", v.Base, v.Len, &buf[40])
+	}
+	if v := iobuf.IoVecOf(&buf, iobuf.BufferSizeMedium, 0); v != (iobuf.IoVec{}) {
+		t.Errorf("IoVecOf(end, 0) = %+v, want the zero IoVec", v)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("IoVecOf past the end should panic")
+		}
+	}()
+	iobuf.IoVecOf(&buf, 1, iobuf.BufferSizeMedium)
+}
+
+func TestIoVecFromBuffersN(t *testing.T) {
+	buffers := make([]iobuf.MicroBuffer, 3)
+	vec := iobuf.IoVecFromBuffersN(buffers, []int{iobuf.BufferSizeMicro, 100, 0})
+	for i, want := range []uint64{iobuf.BufferSizeMicro, 100, 0} {
+		if vec[i].Base != &buffers[i][0] || vec[i].Len != want {
+			t.Errorf("vec[%d] = {%p, %d}, want {%p, %d}", i, vec[i].Base, vec[i].Len, &buffers[i][0], want)
+		}
+	}
+	if vec := iobuf.IoVecFromBuffersN[iobuf.MicroBuffer](nil, nil); vec != nil {
+		t.Error("expected nil for empty input")
+	}
+
+	for name, f := range map[string]func(){
+		"mismatch":  func() { iobuf.IoVecFromBuffersN(buffers, []int{1}) },
+		"too large": func() { iobuf.IoVecFromBuffersN(buffers[:1], []int{iobuf.BufferSizeMicro + 1}) },
+		"negative":  func() { iobuf.IoVecFromBuffersN(buffers[:1], []int{-1}) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s should panic", name)
+				}
+			}()
+			f()
+		})
+	}
+}
+
 func TestIoVecFromPicoBuffers(t *testing.T) {
 	t.Run("empty slice", func(t *testing.T) {
 		vec := iobuf.IoVecFromPicoBuffers(nil)
@@ -386,3 +476,196 @@ func TestIoVecPointerStability(t *testing.T) {
 		}
 	}
 }
+
+func TestIoVecAdvance(t *testing.T) {
+	a, b, c := []byte("hello"), []byte("big"), []byte("world")
+	vec := []iobuf.IoVec{{Base: &a[0], Len: 5}, {Base: &b[0], Len: 3}, {Base: &c[0], Len: 5}}
+
+	vec = iobuf.IoVecAdvance(vec, 0)
+	if len(vec) != 3 || vec[0].Len != 5 {
+		t.Fatalf("Advance(0) changed the vector: %+v", vec)
+	}
+	vec = iobuf.IoVecAdvance(vec, 2)
+	if len(vec) != 3 || vec[0].Base != &a[2] || vec[0].Len != 3 {
+		t.Errorf("Advance(2) first element = {%p, %d}, want {%p, 3}", vec[0].Base, vec[0].Len, &a[2])
+	}
+	vec = iobuf.IoVecAdvance(vec, 6)
+	if len(vec) != 1 || vec[0].Base != &c[0] || vec[0].Len != 5 {
+		t.Errorf("Advance across elements = %+v, want the whole last element", vec)
+	}
+	if vec = iobuf.IoVecAdvance(vec, 5); len(vec) != 0 {
+		t.Errorf("Advance to the end left %d elements", len(vec))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Advance past the end should panic")
+		}
+	}()
+	iobuf.IoVecAdvance(vec, 1)
+}
+
+func TestIoVecCoalesce(t *testing.T) {
+	buffers := make([]iobuf.SmallBuffer, 3)
+	other := make([]byte, 8)
+	vec := append(iobuf.IoVecFromSmallBuffers(buffers[:2]),
+		iobuf.IoVec{Base: &other[0], Len: 0},
+		iobuf.IoVec{Base: &buffers[2][0], Len: 100},
+		iobuf.IoVec{Base: &other[0], Len: 4},
+		iobuf.IoVec{Base: &other[4], Len: 4},
+	)
+	vec = iobuf.IoVecCoalesce(vec)
+	want := []iobuf.IoVec{
+		{Base: &buffers[0][0], Len: 2*iobuf.BufferSizeSmall + 100},
+		{Base: &other[0], Len: 8},
+	}
+	if len(vec) != len(want) {
+		t.Fatalf("Coalesce() = %d elements, want %d", len(vec), len(want))
+	}
+	for i := range want {
+		if vec[i] != want[i] {
+			t.Errorf("vec[%d] = %+v, want %+v", i, vec[i], want[i])
+		}
+	}
+	if got := iobuf.IoVecCoalesce(nil); len(got) != 0 {
+		t.Errorf("Coalesce(nil) = %v, want empty", got)
+	}
+}
+
+func TestIoVecSplit(t *testing.T) {
+	data := make([]byte, 30)
+	newVec := func() []iobuf.IoVec {
+		return []iobuf.IoVec{{Base: &data[0], Len: 10}, {Base: &data[10], Len: 10}, {Base: &data[20], Len: 10}}
+	}
+
+	head, tail := iobuf.IoVecSplit(newVec(), 0, 2)
+	if len(head) != 2 || len(tail) != 1 {
+		t.Errorf("segment limit: len(head)=%d len(tail)=%d, want 2 and 1", len(head), len(tail))
+	}
+	head, tail = iobuf.IoVecSplit(newVec(), 20, 0)
+	if len(head) != 2 || len(tail) != 1 || tail[0].Base != &data[20] {
+		t.Errorf("byte limit on a boundary: len(head)=%d len(tail)=%d", len(head), len(tail))
+	}
+
+	// The byte limit falls in the middle of the second element.
+	head, tail = iobuf.IoVecSplit(newVec(), 15, iobuf.IovMax)
+	if len(head) != 2 || head[1] != (iobuf.IoVec{Base: &data[10], Len: 5}) {
+		t.Errorf("head = %+v, want the second element cut to 5 bytes", head)
+	}
+	if len(tail) != 2 || tail[0] != (iobuf.IoVec{Base: &data[15], Len: 5}) {
+		t.Errorf("tail = %+v, want the rest of the second element first", tail)
+	}
+
+	head, tail = iobuf.IoVecSplit(newVec(), 100, 5)
+	if len(head) != 3 || len(tail) != 0 {
+		t.Errorf("no limit reached: len(head)=%d len(tail)=%d", len(head), len(tail))
+	}
+}
+
+func TestIoVecValidate(t *testing.T) {
+	b := make([]byte, 8)
+	ok := []iobuf.IoVec{{Base: &b[0], Len: 8}, {Base: nil, Len: 0}}
+	if err := iobuf.IoVecValidate(ok); err != nil {
+		t.Errorf("IoVecValidate(valid) = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		vec      []iobuf.IoVec
+		nonEmpty bool
+		index    int
+	}{
+		{"nil base", []iobuf.IoVec{{Base: &b[0], Len: 1}, {Base: nil, Len: 4}}, false, 1},
+		{"empty", ok, true, 1},
+		{"overflow", []iobuf.IoVec{{Base: &b[0], Len: math.MaxInt64}, {Base: &b[0], Len: 1}}, false, 1},
+		{"too many", make([]iobuf.IoVec, iobuf.IovMax+1), false, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := iobuf.IoVecValidate(tt.vec)
+			if tt.nonEmpty {
+				err = iobuf.IoVecValidateNonEmpty(tt.vec)
+			}
+			var ve *iobuf.IoVecError
+			if !errors.As(err, &ve) || !errors.Is(err, iobuf.ErrInvalidIoVec) {
+				t.Fatalf("error = %v, want *IoVecError wrapping ErrInvalidIoVec", err)
+			}
+			if ve.Index != tt.index {
+				t.Errorf("Index = %d, want %d (%v)", ve.Index, tt.index, err)
+			}
+		})
+	}
+}
+
+func TestGatherScatterCopy(t *testing.T) {
+	a, c := make([]byte, 3), make([]byte, 4)
+	vec := []iobuf.IoVec{{Base: &a[0], Len: 3}, {Base: nil, Len: 0}, {Base: &c[0], Len: 4}}
+
+	if n := iobuf.ScatterCopy(vec, []byte("scatter")); n != 7 || string(a) != "sca" || string(c) != "tter" {
+		t.Errorf("ScatterCopy() = %d, elements %q %q", n, a, c)
+	}
+	dst := make([]byte, 10)
+	if n := iobuf.GatherCopy(dst, vec); n != 7 || string(dst[:n]) != "scatter" {
+		t.Errorf("GatherCopy() = %d, %q", n, dst[:n])
+	}
+
+	// Short destinations and sources stop early.
+	if n := iobuf.GatherCopy(dst[:5], vec); n != 5 || string(dst[:5]) != "scatt" {
+		t.Errorf("GatherCopy(short) = %d, %q", n, dst[:5])
+	}
+	if n := iobuf.ScatterCopy(vec, []byte("XY")); n != 2 || string(a) != "XYa" || string(c) != "tter" {
+		t.Errorf("ScatterCopy(short) = %d, elements %q %q", n, a, c)
+	}
+}
+
+func TestIoVecBytes(t *testing.T) {
+	vec := iobuf.IoVecFromStrings([]string{"range", "", "over", "func"})
+	var got []string
+	for b := range iobuf.IoVecBytes(vec) {
+		got = append(got, string(b))
+	}
+	if len(got) != 4 || got[0] != "range" || got[1] != "" || got[3] != "func" {
+		t.Errorf("IoVecBytes() yielded %q", got)
+	}
+	for b := range iobuf.IoVecBytes(vec) {
+		if string(b) != "range" {
+			t.Errorf("first element = %q", b)
+		}
+		break
+	}
+}
+
+func TestIoVecCheckDirect(t *testing.T) {
+	mem := iobuf.AlignedMem(2*4096, 4096)
+	ok := []iobuf.IoVec{{Base: &mem[0], Len: 4096}, {Base: &mem[4096], Len: 512}}
+	if err := iobuf.IoVecCheckDirect(ok, 512); err != nil {
+		t.Errorf("IoVecCheckDirect(aligned) = %v", err)
+	}
+	var ve *iobuf.IoVecError
+	if err := iobuf.IoVecCheckDirect(ok, 4096); !errors.As(err, &ve) || ve.Index != 1 {
+		t.Errorf("short length: error = %v, want element 1", err)
+	}
+	bad := []iobuf.IoVec{{Base: &mem[100], Len: 512}}
+	if err := iobuf.IoVecCheckDirect(bad, 512); !errors.As(err, &ve) || ve.Index != 0 {
+		t.Errorf("misaligned base: error = %v, want element 0", err)
+	}
+}
+
+func TestIoVecHandle(t *testing.T) {
+	a, b := []byte("keep"), []byte("alive")
+	h := iobuf.NewIoVecHandle([][]byte{a, b})
+	addr, n := h.AddrLen()
+	if n != 2 || addr != uintptr(unsafe.Pointer(&h.Vec()[0])) {
+		t.Fatalf("AddrLen() = %#x, %d", addr, n)
+	}
+	if v := h.Vec()[1]; v.Base != &b[0] || v.Len != 5 {
+		t.Errorf("Vec()[1] = %+v, want the second slice", v)
+	}
+	if err := h.Close(); err != nil {
+		t.Errorf("Close() failed: %v", err)
+	}
+	if addr, n := h.AddrLen(); addr != 0 || n != 0 {
+		t.Errorf("AddrLen() after Close = %#x, %d", addr, n)
+	}
+	_ = h.Close()
+}
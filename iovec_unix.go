@@ -0,0 +1,29 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package iobuf
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// The conversions below reinterpret memory, so IoVec must stay
+// layout-compatible with syscall.Iovec.
+var _ [unsafe.Sizeof(IoVec{}) - unsafe.Sizeof(syscall.Iovec{})]struct{}
+var _ [unsafe.Sizeof(syscall.Iovec{}) - unsafe.Sizeof(IoVec{})]struct{}
+
+// SyscallIovecs returns vec viewed as a []syscall.Iovec without copying.
+// Both slices share the same memory.
+func SyscallIovecs(vec []IoVec) []syscall.Iovec {
+	return unsafe.Slice((*syscall.Iovec)(unsafe.Pointer(unsafe.SliceData(vec))), len(vec))
+}
+
+// IoVecsFromSyscall returns iov viewed as an []IoVec without copying.
+// Both slices share the same memory.
+func IoVecsFromSyscall(iov []syscall.Iovec) []IoVec {
+	return unsafe.Slice((*IoVec)(unsafe.Pointer(unsafe.SliceData(iov))), len(iov))
+}
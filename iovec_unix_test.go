@@ -0,0 +1,61 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package iobuf_test
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestSyscallIovecs(t *testing.T) {
+	a, b := []byte("vectored "), []byte("write")
+	vec := []iobuf.IoVec{{Base: &a[0], Len: uint64(len(a))}, {Base: &b[0], Len: uint64(len(b))}}
+	iov := iobuf.SyscallIovecs(vec)
+	if len(iov) != 2 || unsafe.Pointer(&iov[0]) != unsafe.Pointer(&vec[0]) {
+		t.Fatal("SyscallIovecs() did not alias the vector")
+	}
+	if iov[1].Base != &b[0] || iov[1].Len != uint64(len(b)) {
+		t.Errorf("iov[1] = %+v, want the second buffer", iov[1])
+	}
+	back := iobuf.IoVecsFromSyscall(iov)
+	if &back[0] != &vec[0] {
+		t.Error("IoVecsFromSyscall() did not alias the iovecs")
+	}
+
+	// The converted vector is usable with writev.
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	n, _, errno := syscall.Syscall(syscall.SYS_WRITEV, w.Fd(), uintptr(unsafe.Pointer(&iov[0])), uintptr(len(iov)))
+	if errno != 0 || n != 14 {
+		t.Fatalf("writev = %d, %v", n, errno)
+	}
+	got := make([]byte, 14)
+	if _, err := r.Read(got); err != nil || string(got) != "vectored write" {
+		t.Errorf("read %q, %v", got, err)
+	}
+}
+
+func TestIoVecsAs(t *testing.T) {
+	vec := make([]iobuf.IoVec, 2)
+	if iov := iobuf.IoVecsAs[syscall.Iovec](vec); len(iov) != 2 || unsafe.Pointer(&iov[0]) != unsafe.Pointer(&vec[0]) {
+		t.Error("IoVecsAs() did not alias the vector")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("IoVecsAs with a mismatched type should panic")
+		}
+	}()
+	iobuf.IoVecsAs[uint64](vec)
+}
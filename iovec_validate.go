@@ -0,0 +1,88 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"unsafe"
+)
+
+// ErrInvalidIoVec is wrapped by every error returned from IoVecValidate.
+var ErrInvalidIoVec = errors.New("iobuf: invalid iovec")
+
+// IoVecError describes the element of a vector that failed validation.
+// Index is -1 for problems with the vector as a whole.
+type IoVecError struct {
+	Index  int
+	Reason string
+}
+
+func (e *IoVecError) Error() string {
+	if e.Index < 0 {
+		return "iobuf: invalid iovec: " + e.Reason
+	}
+	return "iobuf: invalid iovec[" + strconv.Itoa(e.Index) + "]: " + e.Reason
+}
+
+// Unwrap returns ErrInvalidIoVec.
+func (e *IoVecError) Unwrap() error { return ErrInvalidIoVec }
+
+// IoVecValidate checks vec before it is handed to the kernel, which
+// otherwise reports malformed vectors as a bare EFAULT or EINVAL. It
+// rejects vectors longer than IovMax, elements with a nil Base and a
+// non-zero Len, and total lengths that overflow ssize_t. Empty elements
+// are allowed; see IoVecValidateNonEmpty.
+//
+// The returned error is an *IoVecError naming the offending element.
+func IoVecValidate(vec []IoVec) error {
+	return validateIoVec(vec, true)
+}
+
+// IoVecValidateNonEmpty is like IoVecValidate but also rejects elements of
+// length zero, which waste a slot and usually indicate a bookkeeping bug.
+func IoVecValidateNonEmpty(vec []IoVec) error {
+	return validateIoVec(vec, false)
+}
+
+// IoVecCheckDirect verifies that every base address and length in vec is
+// a multiple of align, as O_DIRECT requires (typically 512 bytes or the
+// logical block size, often 4 KiB). Misaligned direct I/O otherwise fails
+// with a bare EINVAL. The returned error is an *IoVecError naming the
+// first misaligned element. Panics if align is not a power of two.
+func IoVecCheckDirect(vec []IoVec, align uintptr) error {
+	if align == 0 || align&(align-1) != 0 {
+		panic("iobuf.IoVecCheckDirect: alignment must be a power of two")
+	}
+	for i, v := range vec {
+		if uintptr(unsafe.Pointer(v.Base))&(align-1) != 0 {
+			return &IoVecError{Index: i, Reason: "base not aligned to " + strconv.FormatUint(uint64(align), 10)}
+		}
+		if v.Len&uint64(align-1) != 0 {
+			return &IoVecError{Index: i, Reason: "length " + strconv.FormatUint(v.Len, 10) + " not a multiple of " + strconv.FormatUint(uint64(align), 10)}
+		}
+	}
+	return nil
+}
+
+func validateIoVec(vec []IoVec, allowEmpty bool) error {
+	if len(vec) > IovMax {
+		return &IoVecError{Index: -1, Reason: strconv.Itoa(len(vec)) + " elements exceed IovMax"}
+	}
+	var total uint64
+	for i, v := range vec {
+		switch {
+		case v.Len == 0 && !allowEmpty:
+			return &IoVecError{Index: i, Reason: "zero length"}
+		case v.Base == nil && v.Len != 0:
+			return &IoVecError{Index: i, Reason: "nil base with length " + strconv.FormatUint(v.Len, 10)}
+		case v.Len > math.MaxInt64-total:
+			return &IoVecError{Index: i, Reason: "total length overflows"}
+		}
+		total += v.Len
+	}
+	return nil
+}
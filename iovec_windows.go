@@ -0,0 +1,59 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"math"
+	"syscall"
+	"unsafe"
+)
+
+// WSABufsFromBuffers converts a slice of tier buffers to a WSABUF array for
+// WSASend and WSARecv. The elements point directly to the buffer memory
+// without copying, and each element covers a whole buffer.
+func WSABufsFromBuffers[T BufferType](buffers []T) []syscall.WSABuf {
+	if len(buffers) == 0 {
+		return nil
+	}
+	size := uint32(unsafe.Sizeof(buffers[0]))
+	bufs := make([]syscall.WSABuf, len(buffers))
+	for i := range len(buffers) {
+		bufs[i] = syscall.WSABuf{Len: size, Buf: (*byte)(unsafe.Pointer(&buffers[i]))}
+	}
+	return bufs
+}
+
+// WSABufsFromBytesSlice converts a slice of byte slices to a WSABUF array.
+// Panics if a slice is longer than a WSABUF can describe (4 GiB - 1).
+func WSABufsFromBytesSlice(iov [][]byte) []syscall.WSABuf {
+	if len(iov) == 0 {
+		return nil
+	}
+	bufs := make([]syscall.WSABuf, len(iov))
+	for i, b := range iov {
+		if len(b) > math.MaxUint32 {
+			panic("iobuf.WSABufsFromBytesSlice: buffer too large for WSABUF")
+		}
+		bufs[i] = syscall.WSABuf{Len: uint32(len(b)), Buf: unsafe.SliceData(b)}
+	}
+	return bufs
+}
+
+// WSABufsFromIoVec converts an IoVec slice to a WSABUF array. The layouts
+// differ, so the descriptors are copied; the memory they describe is not.
+// Panics if an element is longer than a WSABUF can describe (4 GiB - 1).
+func WSABufsFromIoVec(vec []IoVec) []syscall.WSABuf {
+	if len(vec) == 0 {
+		return nil
+	}
+	bufs := make([]syscall.WSABuf, len(vec))
+	for i, v := range vec {
+		if v.Len > math.MaxUint32 {
+			panic("iobuf.WSABufsFromIoVec: element too large for WSABUF")
+		}
+		bufs[i] = syscall.WSABuf{Len: uint32(v.Len), Buf: v.Base}
+	}
+	return bufs
+}
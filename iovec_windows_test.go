@@ -0,0 +1,36 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestWSABufsFromBuffers(t *testing.T) {
+	buffers := make([]iobuf.SmallBuffer, 2)
+	bufs := iobuf.WSABufsFromBuffers(buffers)
+	if len(bufs) != 2 {
+		t.Fatalf("expected len=2, got %d", len(bufs))
+	}
+	for i, b := range bufs {
+		if b.Buf != &buffers[i][0] || b.Len != iobuf.BufferSizeSmall {
+			t.Errorf("bufs[%d] = {%d, %p}, want the whole buffer", i, b.Len, b.Buf)
+		}
+	}
+}
+
+func TestWSABufsFromBytesSlice(t *testing.T) {
+	a, b := []byte("hello"), []byte("world!")
+	bufs := iobuf.WSABufsFromBytesSlice([][]byte{a, b})
+	if len(bufs) != 2 || bufs[0].Buf != &a[0] || bufs[1].Len != 6 {
+		t.Errorf("WSABufsFromBytesSlice() = %+v", bufs)
+	}
+	vec := []iobuf.IoVec{{Base: &a[0], Len: 5}}
+	if w := iobuf.WSABufsFromIoVec(vec); len(w) != 1 || w[0].Buf != &a[0] || w[0].Len != 5 {
+		t.Errorf("WSABufsFromIoVec() = %+v", w)
+	}
+}
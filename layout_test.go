@@ -0,0 +1,34 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf/internal"
+)
+
+// TestBoundedPool_CursorLayout checks that the cursors and counters sit on
+// cache lines of their own, apart from each other and from the
+// configuration.
+func TestBoundedPool_CursorLayout(t *testing.T) {
+	var p BoundedPool[SmallBuffer]
+	head, tail := unsafe.Offsetof(p.head), unsafe.Offsetof(p.tail)
+	config := unsafe.Offsetof(p.states) + unsafe.Sizeof(p.states)
+	if head < config+internal.CacheLineSize {
+		t.Errorf("head at %d is within a cache line of the configuration ending at %d", head, config)
+	}
+	if tail < head+internal.CacheLineSize {
+		t.Errorf("tail at %d is within a cache line of head at %d", tail, head)
+	}
+	count := unsafe.Offsetof(p.count)
+	if count < tail+internal.CacheLineSize {
+		t.Errorf("count at %d is within a cache line of tail at %d", count, tail)
+	}
+	if end := unsafe.Sizeof(p); end < count+internal.CacheLineSize {
+		t.Errorf("count at %d is within a cache line of the struct end %d", count, end)
+	}
+}
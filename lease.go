@@ -0,0 +1,89 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+// Lease is a handle to a buffer acquired from a pool.
+//
+// The bare-int hand-off of Get/Put makes it easy to forget a Put on error
+// paths. A Lease carries the indirect index together with its pool and
+// implements io.Closer, so the buffer can be returned with a deferred call:
+//
+//	lease, err := pool.Acquire()
+//	if err != nil {
+//	    return err
+//	}
+//	defer lease.Close()
+//	n, err := conn.Read(lease.Bytes())
+//
+// Copies of a Lease refer to the same buffer; only one of them may be
+// closed. See SetReclaimLeaks for recovering buffers of Leases that are
+// never closed.
+type Lease struct {
+	pool     BufferPool
+	indirect int
+	guard    *leakGuard // Non-nil if the pool reclaims leaks
+}
+
+// Acquire gets a buffer from the pool and returns a Lease for it.
+// Returns iox.ErrWouldBlock if the pool is empty and nonblocking mode is set.
+func (pool *BoundedPool[T]) Acquire() (Lease, error) {
+	indirect, err := pool.Get()
+	if err != nil {
+		return Lease{}, err
+	}
+	return newLease(pool, indirect), nil
+}
+
+// AcquireLease gets a buffer from any BufferPool and returns a Lease for it.
+func AcquireLease(pool BufferPool) (Lease, error) {
+	indirect, err := pool.Get()
+	if err != nil {
+		return Lease{}, err
+	}
+	return newLease(pool, indirect), nil
+}
+
+// newLease returns a Lease for the buffer at indirect, guarded against
+// leaks if the pool reclaims them.
+func newLease(pool BufferPool, indirect int) Lease {
+	l := Lease{pool: pool, indirect: indirect}
+	if reclaimsLeaks(pool) {
+		l.guard = newLeakGuard(pool, indirect)
+	}
+	return l
+}
+
+// Indirect returns the pool index of the leased buffer.
+func (l *Lease) Indirect() int { return l.indirect }
+
+// Pool returns the pool the leased buffer belongs to, or nil once closed.
+func (l *Lease) Pool() BufferPool { return l.pool }
+
+// Bytes returns a view of the leased buffer.
+// Panics if the lease has been closed.
+func (l *Lease) Bytes() []byte {
+	if l.pool == nil {
+		panic("iobuf: use of closed lease")
+	}
+	return l.pool.Bytes(l.indirect)
+}
+
+// Close returns the buffer to its pool. Closing a closed Lease is a no-op.
+// If the pool is non-blocking and full, Close returns iox.ErrWouldBlock
+// and the Lease remains open.
+func (l *Lease) Close() error {
+	if l.pool == nil {
+		return nil
+	}
+	if err := l.pool.Put(l.indirect); err != nil {
+		return err
+	}
+	if l.guard != nil {
+		l.guard.cleanup.Stop()
+		l.guard = nil
+	}
+	l.pool = nil
+	return nil
+}
@@ -0,0 +1,81 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"io"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+	"code.hybscloud.com/iox"
+)
+
+func TestLease_AcquireClose(t *testing.T) {
+	pool := iobuf.NewSmallBufferPool(1)
+	pool.Fill(iobuf.NewSmallBuffer)
+	pool.SetNonblock(true)
+
+	lease, err := pool.Acquire()
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	if len(lease.Bytes()) != iobuf.BufferSizeSmall {
+		t.Errorf("Bytes() len = %d, want %d", len(lease.Bytes()), iobuf.BufferSizeSmall)
+	}
+	lease.Bytes()[0] = 'L'
+	if pool.Value(lease.Indirect())[0] != 'L' {
+		t.Error("Bytes() does not alias pool memory")
+	}
+	if lease.Pool() != iobuf.BufferPool(pool) {
+		t.Error("Pool() mismatch")
+	}
+
+	if _, err := pool.Acquire(); err != iox.ErrWouldBlock {
+		t.Fatalf("Acquire() on empty pool: %v, want ErrWouldBlock", err)
+	}
+
+	var c io.Closer = &lease
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("second Close() failed: %v", err)
+	}
+	if lease.Pool() != nil {
+		t.Error("Pool() after Close should be nil")
+	}
+
+	again, err := iobuf.AcquireLease(pool)
+	if err != nil {
+		t.Fatalf("AcquireLease() after Close failed: %v", err)
+	}
+	defer again.Close()
+}
+
+func TestLease_BytesAfterClose(t *testing.T) {
+	pool := iobuf.NewPicoBufferPool(1)
+	pool.Fill(iobuf.NewPicoBuffer)
+	lease, _ := pool.Acquire()
+	_ = lease.Close()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Bytes() on closed lease should panic")
+		}
+	}()
+	lease.Bytes()
+}
+
+func TestLease_AcquireLeaseEmpty(t *testing.T) {
+	pool := iobuf.NewPicoBufferPool(1)
+	pool.Fill(iobuf.NewPicoBuffer)
+	pool.SetNonblock(true)
+	held, _ := pool.Get()
+	defer pool.Put(held)
+
+	if _, err := iobuf.AcquireLease(pool); err != iox.ErrWouldBlock {
+		t.Fatalf("AcquireLease() on empty pool: %v, want ErrWouldBlock", err)
+	}
+}
@@ -0,0 +1,124 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// LogOptions configures the diagnostics a pool logs with SetLogger. A nil
+// *LogOptions, or a nil field, selects the default.
+type LogOptions struct {
+	// Exhausted is the level of the record logged the first time Get finds
+	// the pool empty. Defaults to slog.LevelWarn.
+	Exhausted slog.Leveler
+
+	// Blocked is the level of the record logged when a blocking Get or Put
+	// has waited longer than BlockThreshold. Defaults to slog.LevelWarn.
+	Blocked slog.Leveler
+
+	// Outstanding is the level of the record logged when the pool is
+	// unregistered while callers still hold buffers. Defaults to
+	// slog.LevelError.
+	Outstanding slog.Leveler
+
+	// BlockThreshold is how long a call may block before it is logged.
+	// Defaults to one second.
+	BlockThreshold time.Duration
+}
+
+// poolLog is the logging state of a pool with a logger attached.
+type poolLog struct {
+	logger          *slog.Logger
+	exhausted       slog.Leveler
+	blocked         slog.Leveler
+	outstanding     slog.Leveler
+	threshold       time.Duration
+	exhaustedLogged atomic.Bool // The first exhaustion was logged
+}
+
+func newPoolLog(logger *slog.Logger, opts *LogOptions) *poolLog {
+	l := &poolLog{
+		logger:      logger,
+		exhausted:   slog.LevelWarn,
+		blocked:     slog.LevelWarn,
+		outstanding: slog.LevelError,
+		threshold:   time.Second,
+	}
+	if opts != nil {
+		if opts.Exhausted != nil {
+			l.exhausted = opts.Exhausted
+		}
+		if opts.Blocked != nil {
+			l.blocked = opts.Blocked
+		}
+		if opts.Outstanding != nil {
+			l.outstanding = opts.Outstanding
+		}
+		if opts.BlockThreshold > 0 {
+			l.threshold = opts.BlockThreshold
+		}
+	}
+	return l
+}
+
+// SetLogger attaches logger to the pool, or detaches it if logger is nil.
+//
+// The pool then logs rare events worth an operator's attention: the first
+// time it runs out of items, calls blocking longer than the configured
+// threshold, and being unregistered with buffers still in use. Records
+// carry the pool name when the pool is registered. SetLogger must be
+// called before the pool is shared between goroutines.
+func (pool *BoundedPool[T]) SetLogger(logger *slog.Logger, opts *LogOptions) {
+	if logger == nil {
+		pool.debug &^= poolDebugLog
+		pool.log = nil
+		return
+	}
+	pool.log = newPoolLog(logger, opts)
+	pool.debug |= poolDebugLog
+}
+
+// logEvent logs msg at level with the pool's identifying attributes.
+func (pool *BoundedPool[T]) logEvent(level slog.Leveler, msg string, attrs ...slog.Attr) {
+	ctx := context.Background()
+	lv := level.Level()
+	if !pool.log.logger.Enabled(ctx, lv) {
+		return
+	}
+	if st := pool.stats; st != nil && st.name != "" {
+		attrs = append(attrs, slog.String("pool", st.name))
+	}
+	attrs = append(attrs, slog.Int("capacity", int(pool.capacity)))
+	pool.log.logger.LogAttrs(ctx, lv, msg, attrs...)
+}
+
+// logExhausted logs the first time Get finds the pool empty.
+func (pool *BoundedPool[T]) logExhausted() {
+	if pool.log.exhaustedLogged.Load() || !pool.log.exhaustedLogged.CompareAndSwap(false, true) {
+		return
+	}
+	pool.logEvent(pool.log.exhausted, "iobuf: pool exhausted")
+}
+
+// logBlocked logs a call that has been waiting for waited.
+func (pool *BoundedPool[T]) logBlocked(op PoolOp, waited time.Duration) {
+	pool.logEvent(pool.log.blocked, "iobuf: pool blocked",
+		slog.String("op", op.String()), slog.Duration("waited", waited))
+}
+
+// logUnregistered logs unregistering the pool while buffers are in use.
+func (pool *BoundedPool[T]) logUnregistered() {
+	if pool.debug&poolDebugLog == 0 || len(pool.items) != int(pool.capacity) {
+		return
+	}
+	if n := pool.Stats().InUse(); n > 0 {
+		pool.logEvent(pool.log.outstanding, "iobuf: pool unregistered with buffers in use",
+			slog.Int("in_use", n))
+	}
+}
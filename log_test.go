@@ -0,0 +1,123 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"code.hybscloud.com/iobuf"
+)
+
+// logBuffer is a bytes.Buffer safe for the concurrent writes of a handler.
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestBoundedPool_SetLoggerExhausted(t *testing.T) {
+	var out logBuffer
+	pool := iobuf.NewNanoBufferPool(1)
+	pool.Fill(iobuf.NewNanoBuffer)
+	pool.SetNonblock(true)
+	pool.SetLogger(slog.New(slog.NewTextHandler(&out, nil)), nil)
+
+	idx, _ := pool.Get()
+	for range 3 {
+		if _, err := pool.Get(); err == nil {
+			t.Fatal("Get() from an empty pool succeeded")
+		}
+	}
+	_ = pool.Put(idx)
+
+	got := out.String()
+	if n := strings.Count(got, "pool exhausted"); n != 1 {
+		t.Fatalf("logged exhaustion %d times, want once:\n%s", n, got)
+	}
+	if !strings.Contains(got, "level=WARN") || !strings.Contains(got, "capacity=1") {
+		t.Errorf("unexpected record: %s", got)
+	}
+
+	pool.SetLogger(nil, nil)
+	idx, _ = pool.Get()
+	_, _ = pool.Get()
+	_ = pool.Put(idx)
+	if out.String() != got {
+		t.Errorf("logged after SetLogger(nil): %s", out.String())
+	}
+}
+
+func TestBoundedPool_SetLoggerBlocked(t *testing.T) {
+	var out logBuffer
+	pool := iobuf.NewNanoBufferPool(1)
+	pool.Fill(iobuf.NewNanoBuffer)
+	pool.SetLogger(slog.New(slog.NewTextHandler(&out, nil)), &iobuf.LogOptions{
+		Exhausted:      slog.LevelInfo,
+		Blocked:        slog.LevelError,
+		BlockThreshold: 5 * time.Millisecond,
+	})
+
+	idx, _ := pool.Get()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		time.Sleep(50 * time.Millisecond)
+		_ = pool.Put(idx)
+	}()
+	idx, _ = pool.Get() // blocks past the threshold
+	<-done
+	_ = pool.Put(idx)
+
+	got := out.String()
+	if !strings.Contains(got, `level=INFO msg="iobuf: pool exhausted"`) {
+		t.Errorf("missing exhaustion record:\n%s", got)
+	}
+	if n := strings.Count(got, `level=ERROR msg="iobuf: pool blocked" op=Get`); n != 1 {
+		t.Errorf("logged blocking %d times, want once:\n%s", n, got)
+	}
+}
+
+func TestUnregisterPool_LogsOutstanding(t *testing.T) {
+	var out logBuffer
+	iobuf.SetRegistryLogger(slog.New(slog.NewTextHandler(&out, nil)), nil)
+	defer iobuf.SetRegistryLogger(nil, nil)
+
+	pool := iobuf.NewNanoBufferPool(4)
+	pool.Fill(iobuf.NewNanoBuffer)
+	iobuf.RegisterPool("test.leaky", pool)
+	idx, _ := pool.Get()
+	iobuf.UnregisterPool("test.leaky")
+
+	got := out.String()
+	want := `level=ERROR msg="iobuf: pool unregistered with buffers in use" in_use=1 pool=test.leaky`
+	if !strings.Contains(got, want) {
+		t.Errorf("log = %q, want it to contain %q", got, want)
+	}
+
+	// A pool unregistered with every buffer returned logs nothing.
+	_ = pool.Put(idx)
+	iobuf.RegisterPool("test.leaky", pool)
+	before := out.String()
+	iobuf.UnregisterPool("test.leaky")
+	if out.String() != before {
+		t.Errorf("logged for a pool without outstanding buffers: %s", out.String())
+	}
+}
@@ -0,0 +1,57 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+// MapFlags select the paging behavior of memory mapped with MapMem.
+// Flags a platform does not support are ignored.
+type MapFlags uint32
+
+const (
+	// MapPopulate prefaults the whole mapping (MAP_POPULATE), trading
+	// allocation latency for no page faults on first access.
+	MapPopulate MapFlags = 1 << iota
+
+	// MapLocked locks the pages into RAM like Pin (MAP_LOCKED). The pages
+	// count against RLIMIT_MEMLOCK but not against the Pin tally.
+	MapLocked
+
+	// MapNoReserve maps without reserving swap space (MAP_NORESERVE), so a
+	// large sparse region does not fail under strict overcommit.
+	MapNoReserve
+)
+
+// String returns the flags as a "|"-separated list, e.g., "Populate|Locked".
+func (f MapFlags) String() string {
+	if f == 0 {
+		return "0"
+	}
+	s := ""
+	for i, name := range [...]string{"Populate", "Locked", "NoReserve"} {
+		if f&(1<<i) != 0 {
+			if s != "" {
+				s += "|"
+			}
+			s += name
+		}
+	}
+	return s
+}
+
+// MapMem maps size bytes of page-aligned anonymous memory outside the Go
+// heap with the given flags. The memory is never moved or collected and
+// must be released with UnmapMem. Without mmap support the memory comes
+// from the Go heap and the flags are ignored. Panics if size < 1.
+func MapMem(size int, flags MapFlags) ([]byte, error) {
+	if size < 1 {
+		panic("iobuf.MapMem: size must be positive")
+	}
+	return mapRegion(size, flags)
+}
+
+// UnmapMem releases memory returned by MapMem. The memory must not be
+// used afterwards.
+func UnmapMem(mem []byte) error {
+	return unmapRegion(mem)
+}
@@ -0,0 +1,45 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestMapMem(t *testing.T) {
+	for _, flags := range []iobuf.MapFlags{0, iobuf.MapPopulate, iobuf.MapNoReserve, iobuf.MapPopulate | iobuf.MapNoReserve} {
+		t.Run(flags.String(), func(t *testing.T) {
+			mem, err := iobuf.MapMem(3*4096, flags)
+			if err != nil {
+				t.Fatalf("MapMem(%v) failed: %v", flags, err)
+			}
+			if len(mem) != 3*4096 || uintptr(unsafe.Pointer(unsafe.SliceData(mem)))%4096 != 0 {
+				t.Errorf("len=%d, want 3 page-aligned pages", len(mem))
+			}
+			mem[0], mem[len(mem)-1] = 1, 2
+			if err := iobuf.UnmapMem(mem); err != nil {
+				t.Errorf("UnmapMem() failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestMapFlags_String(t *testing.T) {
+	tests := map[iobuf.MapFlags]string{
+		0:                                      "0",
+		iobuf.MapPopulate:                      "Populate",
+		iobuf.MapLocked | iobuf.MapNoReserve:   "Locked|NoReserve",
+		iobuf.MapPopulate | iobuf.MapLocked:    "Populate|Locked",
+		iobuf.MapNoReserve | iobuf.MapPopulate: "Populate|NoReserve",
+	}
+	for f, want := range tests {
+		if got := f.String(); got != want {
+			t.Errorf("MapFlags(%d).String() = %q, want %q", uint32(f), got, want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+// Prot is a memory protection for MemRegion.Protect.
+type Prot uint32
+
+// Memory protections, combined with |. ProtNone makes the region
+// inaccessible.
+const (
+	ProtNone  Prot = 0
+	ProtRead  Prot = 1 << 0
+	ProtWrite Prot = 1 << 1
+	ProtExec  Prot = 1 << 2
+)
+
+// Advice is a paging hint for MemRegion.Advise.
+type Advice uint32
+
+// Paging hints corresponding to the madvise advice values.
+const (
+	AdviseNormal     Advice = iota // No special treatment
+	AdviseRandom                   // Expect random access; reduce read-ahead
+	AdviseSequential               // Expect sequential access; read ahead aggressively
+	AdviseWillNeed                 // Expect access soon; start faulting pages in
+	AdviseDontNeed                 // Drop the pages; they read as zero afterwards
+	AdviseHugePage                 // Prefer transparent huge pages
+	AdviseNoHugePage               // Avoid transparent huge pages
+)
+
+// MemRegion is a page-aligned memory region mapped outside the Go heap,
+// with methods for the usual memory-management system calls.
+//
+// The methods take care of the page arithmetic that raw mprotect, madvise
+// and mlock calls require. A MemRegion must be released with Free.
+type MemRegion struct {
+	mem    []byte
+	locked bool
+}
+
+// NewMemRegion maps a region of size bytes, rounded up to whole pages,
+// with the given flags. Panics if size < 1.
+func NewMemRegion(size int, flags MapFlags) (*MemRegion, error) {
+	if size < 1 {
+		panic("iobuf.NewMemRegion: size must be positive")
+	}
+	ps := int(PageSize)
+	mem, err := mapRegion((size+ps-1)/ps*ps, flags)
+	if err != nil {
+		return nil, err
+	}
+	return &MemRegion{mem: mem}, nil
+}
+
+// Bytes returns the whole region, or nil after Free.
+func (r *MemRegion) Bytes() []byte { return r.mem }
+
+// Len returns the size of the region in bytes.
+func (r *MemRegion) Len() int { return len(r.mem) }
+
+// Lock pins the region into RAM; see Pin.
+func (r *MemRegion) Lock() error {
+	if r.locked {
+		return nil
+	}
+	if err := Pin(r.mem); err != nil {
+		return err
+	}
+	r.locked = true
+	return nil
+}
+
+// Unlock undoes Lock.
+func (r *MemRegion) Unlock() {
+	if r.locked {
+		Unpin(r.mem)
+		r.locked = false
+	}
+}
+
+// Resize changes the size of the region to newSize bytes, rounded up to
+// whole pages, preserving its contents up to the smaller of the two sizes.
+//
+// On Linux the pages are remapped with mremap and may move to a new
+// address without being copied; elsewhere the contents are copied into a
+// new region. Either way Bytes must be called again afterwards, and
+// slices of the old region must not be used. A locked region stays locked.
+// Panics if newSize < 1.
+func (r *MemRegion) Resize(newSize int) error {
+	if newSize < 1 {
+		panic("iobuf.MemRegion.Resize: size must be positive")
+	}
+	ps := int(PageSize)
+	newSize = (newSize + ps - 1) / ps * ps
+	if newSize == len(r.mem) {
+		return nil
+	}
+	locked := r.locked
+	r.Unlock()
+	mem, err := remapRegion(r.mem, newSize)
+	if err != nil {
+		if locked {
+			_ = r.Lock()
+		}
+		return err
+	}
+	r.mem = mem
+	if locked {
+		return r.Lock()
+	}
+	return nil
+}
+
+// Free unlocks and unmaps the region. The region and any slice of it must
+// not be used afterwards. Freeing a freed region is a no-op.
+func (r *MemRegion) Free() error {
+	if r.mem == nil {
+		return nil
+	}
+	r.Unlock()
+	err := unmapRegion(r.mem)
+	r.mem = nil
+	return err
+}
@@ -0,0 +1,42 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "syscall"
+
+// madvise advice values indexed by Advice.
+var madviseAdvice = [...]int{
+	AdviseNormal:     syscall.MADV_NORMAL,
+	AdviseRandom:     syscall.MADV_RANDOM,
+	AdviseSequential: syscall.MADV_SEQUENTIAL,
+	AdviseWillNeed:   syscall.MADV_WILLNEED,
+	AdviseDontNeed:   syscall.MADV_DONTNEED,
+	AdviseHugePage:   syscall.MADV_HUGEPAGE,
+	AdviseNoHugePage: syscall.MADV_NOHUGEPAGE,
+}
+
+// Protect changes the access protection of the whole region (mprotect).
+func (r *MemRegion) Protect(prot Prot) error {
+	p := syscall.PROT_NONE
+	if prot&ProtRead != 0 {
+		p |= syscall.PROT_READ
+	}
+	if prot&ProtWrite != 0 {
+		p |= syscall.PROT_WRITE
+	}
+	if prot&ProtExec != 0 {
+		p |= syscall.PROT_EXEC
+	}
+	return syscall.Mprotect(r.mem, p)
+}
+
+// Advise gives the kernel a paging hint for the whole region (madvise).
+// Panics if advice is not one of the Advise constants.
+func (r *MemRegion) Advise(advice Advice) error {
+	if int(advice) >= len(madviseAdvice) {
+		panic("iobuf.MemRegion.Advise: unknown advice")
+	}
+	return syscall.Madvise(r.mem, madviseAdvice[advice])
+}
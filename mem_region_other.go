@@ -0,0 +1,24 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package iobuf
+
+import "errors"
+
+// Protect changes the access protection of the region. It is only
+// supported on Linux; elsewhere it returns errors.ErrUnsupported.
+func (r *MemRegion) Protect(prot Prot) error {
+	return errors.ErrUnsupported
+}
+
+// Advise gives the kernel a paging hint for the region. Hints are only
+// supported on Linux; elsewhere it is a no-op.
+func (r *MemRegion) Advise(advice Advice) error {
+	if advice > AdviseNoHugePage {
+		panic("iobuf.MemRegion.Advise: unknown advice")
+	}
+	return nil
+}
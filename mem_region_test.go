@@ -0,0 +1,108 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"errors"
+	"runtime"
+	"runtime/debug"
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestMemRegion_Lifecycle(t *testing.T) {
+	r, err := iobuf.NewMemRegion(5000, 0)
+	if err != nil {
+		t.Fatalf("NewMemRegion() failed: %v", err)
+	}
+	if r.Len()%int(iobuf.PageSize) != 0 || r.Len() < 5000 {
+		t.Errorf("Len() = %d, want 5000 rounded to pages", r.Len())
+	}
+	r.Bytes()[0] = 1
+
+	for _, a := range []iobuf.Advice{iobuf.AdviseSequential, iobuf.AdviseWillNeed, iobuf.AdviseNormal} {
+		if err := r.Advise(a); err != nil {
+			t.Errorf("Advise(%d) failed: %v", a, err)
+		}
+	}
+	if err := r.Advise(iobuf.AdviseDontNeed); err != nil {
+		t.Errorf("Advise(AdviseDontNeed) failed: %v", err)
+	}
+	if runtime.GOOS == "linux" && r.Bytes()[0] != 0 {
+		t.Error("AdviseDontNeed did not drop the page")
+	}
+
+	if err := r.Lock(); err == nil {
+		r.Unlock()
+	} else {
+		t.Logf("Lock: %v", err)
+	}
+	if err := r.Free(); err != nil {
+		t.Fatalf("Free() failed: %v", err)
+	}
+	if r.Bytes() != nil {
+		t.Error("Bytes() after Free should be nil")
+	}
+	if err := r.Free(); err != nil {
+		t.Errorf("second Free() = %v", err)
+	}
+}
+
+// poke writes one byte at p. It is not inlined so that a fault happens in
+// a frame of its own, after the caller's deferred recover is in place.
+//
+//go:noinline
+func poke(p unsafe.Pointer) { *(*byte)(p) = 1 }
+
+func TestMemRegion_Protect(t *testing.T) {
+	r, err := iobuf.NewMemRegion(4096, 0)
+	if err != nil {
+		t.Fatalf("NewMemRegion() failed: %v", err)
+	}
+	defer r.Free()
+	if err := r.Protect(iobuf.ProtRead); err != nil {
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skip("Protect not supported")
+		}
+		t.Fatalf("Protect(ProtRead) failed: %v", err)
+	}
+	_ = r.Bytes()[0]
+
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		if recover() == nil {
+			t.Error("write to read-only region did not fault")
+		}
+		_ = r.Protect(iobuf.ProtRead | iobuf.ProtWrite)
+	}()
+	poke(unsafe.Pointer(unsafe.SliceData(r.Bytes())))
+}
+
+func TestMemRegion_Resize(t *testing.T) {
+	ps := int(iobuf.PageSize)
+	r, err := iobuf.NewMemRegion(ps, 0)
+	if err != nil {
+		t.Fatalf("NewMemRegion() failed: %v", err)
+	}
+	defer r.Free()
+	copy(r.Bytes(), "persisted")
+
+	if err := r.Resize(16 * ps); err != nil {
+		t.Fatalf("Resize(grow) failed: %v", err)
+	}
+	if r.Len() != 16*ps || string(r.Bytes()[:9]) != "persisted" {
+		t.Fatalf("after grow Len()=%d data=%q", r.Len(), r.Bytes()[:9])
+	}
+	r.Bytes()[r.Len()-1] = 1
+
+	if err := r.Resize(2*ps - 1); err != nil {
+		t.Fatalf("Resize(shrink) failed: %v", err)
+	}
+	if r.Len() != 2*ps || string(r.Bytes()[:9]) != "persisted" {
+		t.Errorf("after shrink Len()=%d data=%q", r.Len(), r.Bytes()[:9])
+	}
+}
@@ -0,0 +1,66 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// Mmsghdr matches struct mmsghdr, one message of a sendmmsg or recvmmsg
+// batch. The kernel stores the number of bytes transferred in Len.
+type Mmsghdr struct {
+	Hdr syscall.Msghdr
+	Len uint32
+	_   [4]byte
+}
+
+// NewMsghdr returns a msghdr for sendmsg or recvmsg that transfers the
+// bytes described by vec. name, if not empty, holds a raw socket address
+// (struct sockaddr) and control, if not empty, ancillary data such as
+// SCM_RIGHTS messages; on receive the kernel fills both.
+//
+// The msghdr points into vec, name and control, which must stay reachable
+// and unmoved until the system call returns.
+func NewMsghdr(vec []IoVec, name, control []byte) syscall.Msghdr {
+	var h syscall.Msghdr
+	if len(vec) > 0 {
+		h.Iov = (*syscall.Iovec)(unsafe.Pointer(unsafe.SliceData(vec)))
+		h.Iovlen = uint64(len(vec))
+	}
+	if len(name) > 0 {
+		h.Name = unsafe.SliceData(name)
+		h.Namelen = uint32(len(name))
+	}
+	if len(control) > 0 {
+		h.Control = unsafe.SliceData(control)
+		h.SetControllen(len(control))
+	}
+	return h
+}
+
+// NewMmsghdrs returns an mmsghdr array for sendmmsg or recvmmsg with one
+// message per element of vecs. names and controls may be nil; otherwise
+// they supply the name and control buffer of each message as in NewMsghdr.
+//
+// The system call takes the address of the first element and len(msgs).
+// Panics if names or controls is non-nil and differs in length from vecs.
+func NewMmsghdrs(vecs [][]IoVec, names, controls [][]byte) []Mmsghdr {
+	if (names != nil && len(names) != len(vecs)) || (controls != nil && len(controls) != len(vecs)) {
+		panic("iobuf.NewMmsghdrs: names and controls must match the number of messages")
+	}
+	msgs := make([]Mmsghdr, len(vecs))
+	for i, vec := range vecs {
+		var name, control []byte
+		if names != nil {
+			name = names[i]
+		}
+		if controls != nil {
+			control = controls[i]
+		}
+		msgs[i].Hdr = NewMsghdr(vec, name, control)
+	}
+	return msgs
+}
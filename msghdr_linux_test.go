@@ -0,0 +1,104 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func socketpair(t *testing.T) (a, b int) {
+	t.Helper()
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_DGRAM, 0)
+	if err != nil {
+		t.Fatalf("socketpair: %v", err)
+	}
+	t.Cleanup(func() {
+		syscall.Close(fds[0])
+		syscall.Close(fds[1])
+	})
+	return fds[0], fds[1]
+}
+
+func TestNewMsghdr_SendRecv(t *testing.T) {
+	a, b := socketpair(t)
+	head, body := []byte("head:"), []byte("body")
+	out := iobuf.NewMsghdr([]iobuf.IoVec{{Base: &head[0], Len: 5}, {Base: &body[0], Len: 4}}, nil, nil)
+	if _, _, errno := syscall.Syscall(syscall.SYS_SENDMSG, uintptr(a), uintptr(unsafe.Pointer(&out)), 0); errno != 0 {
+		t.Fatalf("sendmsg: %v", errno)
+	}
+
+	// Send a descriptor alongside to exercise the control buffer.
+	f, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rights := syscall.UnixRights(int(f.Fd()))
+	out = iobuf.NewMsghdr([]iobuf.IoVec{{Base: &body[0], Len: 4}}, nil, rights)
+	if _, _, errno := syscall.Syscall(syscall.SYS_SENDMSG, uintptr(a), uintptr(unsafe.Pointer(&out)), 0); errno != 0 {
+		t.Fatalf("sendmsg with rights: %v", errno)
+	}
+
+	buf := make([]byte, 16)
+	in := iobuf.NewMsghdr([]iobuf.IoVec{{Base: &buf[0], Len: 16}}, nil, nil)
+	n, _, errno := syscall.Syscall(syscall.SYS_RECVMSG, uintptr(b), uintptr(unsafe.Pointer(&in)), 0)
+	if errno != 0 || string(buf[:n]) != "head:body" {
+		t.Fatalf("recvmsg = %q, %v", buf[:n], errno)
+	}
+
+	control := make([]byte, syscall.CmsgSpace(4))
+	in = iobuf.NewMsghdr([]iobuf.IoVec{{Base: &buf[0], Len: 16}}, nil, control)
+	if _, _, errno := syscall.Syscall(syscall.SYS_RECVMSG, uintptr(b), uintptr(unsafe.Pointer(&in)), 0); errno != 0 {
+		t.Fatalf("recvmsg with rights: %v", errno)
+	}
+	msgs, err := syscall.ParseSocketControlMessage(control[:in.Controllen])
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("control messages = %v, %v", msgs, err)
+	}
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("unix rights = %v, %v", fds, err)
+	}
+	syscall.Close(fds[0])
+}
+
+func TestNewMmsghdrs(t *testing.T) {
+	a, b := socketpair(t)
+	for _, m := range []string{"first", "second"} {
+		if err := syscall.Sendmsg(a, []byte(m), nil, nil, 0); err != nil {
+			t.Fatalf("sendmsg: %v", err)
+		}
+	}
+
+	bufs := [][]byte{make([]byte, 8), make([]byte, 8)}
+	in := iobuf.NewMmsghdrs([][]iobuf.IoVec{
+		{{Base: &bufs[0][0], Len: 8}},
+		{{Base: &bufs[1][0], Len: 8}},
+	}, nil, nil)
+	n, _, errno := syscall.Syscall6(syscall.SYS_RECVMMSG, uintptr(b), uintptr(unsafe.Pointer(&in[0])), uintptr(len(in)), 0, 0, 0)
+	if errno != 0 || n != 2 {
+		t.Fatalf("recvmmsg = %d, %v", n, errno)
+	}
+	if got := string(bufs[0][:in[0].Len]); got != "first" {
+		t.Errorf("message 0 = %q, want first", got)
+	}
+	if got := string(bufs[1][:in[1].Len]); got != "second" {
+		t.Errorf("message 1 = %q, want second", got)
+	}
+}
+
+func TestNewMmsghdrs_MismatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewMmsghdrs with mismatched names should panic")
+		}
+	}()
+	iobuf.NewMmsghdrs(make([][]iobuf.IoVec, 2), make([][]byte, 1), nil)
+}
@@ -0,0 +1,323 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"fmt"
+	"math/bits"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"code.hybscloud.com/iobuf/internal"
+	"code.hybscloud.com/iox"
+)
+
+// NodePool is an indirect pool with one BoundedPool shard per NUMA node.
+//
+// Each shard's buffers are bound to the memory of its node, and Get serves
+// the calling thread from the shard of the node it runs on, so buffers
+// stay on the socket that uses them instead of bouncing between sockets.
+// When the local shard is empty, Get borrows from the other shards before
+// reporting an empty pool. Put always returns a buffer to the shard that
+// owns it, so borrowed buffers go back to their home node.
+//
+// An indirect index of a NodePool encodes its shard; it is only valid
+// with the NodePool that returned it. On a host with a single node, or
+// where NUMA is not supported, the NodePool has one shard and behaves
+// like its BoundedPool.
+//
+// Blocking behavior is set with SetNonblock and SetWaitStrategy on the
+// NodePool; the shards are not used directly for Get. The would-block,
+// blocking and stall events of a Get are reported by the shard local to
+// the calling thread, with that shard's stats, hooks, logger, stall
+// handler and trace settings.
+type NodePool[T BoundedPoolItem] struct {
+	_ noCopy
+
+	shards      []*BoundedPool[T]
+	nodes       []int // Node of each shard
+	nodeShard   []int // Shard of each node id
+	shift       int   // log2 of the shard capacity
+	nonblocking bool
+	waiter      WaitStrategy
+	ready       func() bool // Readiness of Get, see WaitStrategy
+	borrowed    atomic.Uint64
+	procs       atomic.Pointer[[]nodePoolProc] // Cached node per P, see procNode
+	grow        sync.Mutex                     // Serializes growing procs
+}
+
+// nodeRefresh is the number of calls a P serves from its cached node
+// before querying the node again. Must be a power of two.
+const nodeRefresh = 64
+
+// nodePoolProc caches the NUMA node of one P. Padding keeps adjacent Ps
+// on separate cache lines.
+type nodePoolProc struct {
+	node  atomic.Int32
+	calls atomic.Uint32
+	_     [internal.CacheLineSize - 8]byte
+}
+
+// NewNodePool creates a NodePool with a shard of capacity items for every
+// online NUMA node, filled using newFunc. On hosts with more than one node
+// each shard is bound to its node with BindNode, and the error of a
+// failed binding is returned.
+//
+// Capacity is per shard and rounded up to the next power of two like
+// NewBoundedPool. Panics if capacity < 1 or capacity > math.MaxUint32.
+func NewNodePool[T BoundedPoolItem](capacity int, newFunc func() T) (*NodePool[T], error) {
+	p := newNodePool(onlineNodes(), capacity, newFunc)
+	if len(p.nodes) > 1 {
+		for i, shard := range p.shards {
+			if err := shard.BindNode(p.nodes[i]); err != nil {
+				return nil, fmt.Errorf("iobuf: binding shard to NUMA node %d: %w", p.nodes[i], err)
+			}
+		}
+	}
+	return p, nil
+}
+
+// newNodePool creates the filled shards of a NodePool for nodes.
+func newNodePool[T BoundedPoolItem](nodes []int, capacity int, newFunc func() T) *NodePool[T] {
+	p := &NodePool[T]{
+		shards:    make([]*BoundedPool[T], len(nodes)),
+		nodes:     nodes,
+		nodeShard: make([]int, slices.Max(nodes)+1),
+	}
+	for i, node := range nodes {
+		p.shards[i] = NewBoundedPool[T](capacity)
+		p.shards[i].Fill(newFunc)
+		p.nodeShard[node] = i
+	}
+	p.shift = bits.TrailingZeros32(p.shards[0].capacity)
+	p.ready = p.readyGet
+	p.growProcs(runtime.GOMAXPROCS(0))
+	return p
+}
+
+// Nodes returns the NUMA node of each shard, in shard order.
+func (p *NodePool[T]) Nodes() []int {
+	return slices.Clone(p.nodes)
+}
+
+// Shards returns the shard pools, in the order of Nodes. The shards can be
+// registered and configured individually, e.g., with SetStats; a Get
+// reports its would-block and blocking events to the caller's local shard.
+// Indices must not be taken from them directly.
+func (p *NodePool[T]) Shards() []*BoundedPool[T] {
+	return slices.Clone(p.shards)
+}
+
+// Borrowed returns the number of Gets served by a shard other than the
+// caller's local one. A growing count means the shards are unbalanced for
+// the load running on each node.
+func (p *NodePool[T]) Borrowed() uint64 {
+	return p.borrowed.Load()
+}
+
+// SetNonblock enables or disables the non-blocking mode of Get.
+func (p *NodePool[T]) SetNonblock(nonblocking bool) {
+	p.nonblocking = nonblocking
+}
+
+// SetWaitStrategy sets the strategy blocking Get and Put calls wait with,
+// on the NodePool and every shard, or restores the default if ws is nil.
+// A strategy waking on changes is woken by every shard. SetWaitStrategy
+// must be called before the pool is shared between goroutines.
+func (p *NodePool[T]) SetWaitStrategy(ws WaitStrategy) {
+	p.waiter = ws
+	for _, shard := range p.shards {
+		shard.SetWaitStrategy(ws)
+	}
+}
+
+// Cap returns the total capacity of all shards.
+func (p *NodePool[T]) Cap() int {
+	return len(p.shards) << p.shift
+}
+
+// Value returns the item at the specified indirect index.
+func (p *NodePool[T]) Value(indirect int) T {
+	shard, local := p.split(indirect)
+	return p.shards[shard].Value(local)
+}
+
+// SetValue sets the item at the specified indirect index.
+func (p *NodePool[T]) SetValue(indirect int, value T) {
+	shard, local := p.split(indirect)
+	p.shards[shard].SetValue(local, value)
+}
+
+// Bytes returns a byte view of the buffer at the specified indirect index,
+// see BoundedPool.Bytes.
+func (p *NodePool[T]) Bytes(indirect int) []byte {
+	shard, local := p.split(indirect)
+	return p.shards[shard].Bytes(local)
+}
+
+// Get acquires an indirect index from the shard of the calling thread's
+// NUMA node, borrowing from other shards if it is empty. Returns
+// iox.ErrWouldBlock if the pool is non-blocking and every shard is empty.
+func (p *NodePool[T]) Get() (indirect int, err error) {
+	home := p.localShard()
+	hs := p.shards[home]
+	var aw iox.Backoff
+	var wait poolWait
+	for attempt := 1; ; attempt++ {
+		indirect, ok := p.tryGet(home)
+		if ok {
+			if wait.waiting() {
+				hs.endWait(PoolGet, &wait)
+			}
+			return indirect, nil
+		}
+		if p.nonblocking {
+			if hs.debug&poolDebugStats != 0 {
+				hs.stats.wouldBlocks.Add(1)
+			}
+			if hs.debug&poolDebugLog != 0 {
+				hs.logExhausted()
+			}
+			return boundedPoolEntryEmpty, iox.ErrWouldBlock
+		}
+		if !wait.waiting() && hs.debug&poolDebugWait != 0 {
+			wait = hs.beginWait(PoolGet)
+		} else if hs.debug&poolDebugWatch != 0 {
+			hs.watchWait(PoolGet, &wait)
+		}
+		if hs.debug&poolDebugStats != 0 {
+			hs.stats.backoffs.Add(1)
+		}
+		if p.waiter != nil {
+			p.waiter.Wait(PoolGet, attempt, p.ready)
+		} else {
+			aw.Wait()
+		}
+	}
+}
+
+// readyGet reports whether any shard holds an item.
+func (p *NodePool[T]) readyGet() bool {
+	for _, shard := range p.shards {
+		if shard.count.Load() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Put returns an indirect index to the shard that owns it.
+func (p *NodePool[T]) Put(indirect int) error {
+	shard, local := p.split(indirect)
+	return p.shards[shard].Put(local)
+}
+
+// tryGet makes one pass over the home shard and then the others.
+func (p *NodePool[T]) tryGet(home int) (indirect int, ok bool) {
+	for i := range p.shards {
+		s := home + i
+		if s >= len(p.shards) {
+			s -= len(p.shards)
+		}
+		shard := p.shards[s]
+		e, err := shard.tryGet()
+		if err != nil {
+			continue
+		}
+		local := int(e & uint64(shard.mask))
+		if shard.debug != 0 {
+			shard.onGet(local)
+		}
+		if i != 0 {
+			p.borrowed.Add(1)
+		}
+		return s<<p.shift | local, true
+	}
+	return boundedPoolEntryEmpty, false
+}
+
+// localShard returns the shard of the node the calling thread runs on.
+func (p *NodePool[T]) localShard() int {
+	if len(p.shards) == 1 {
+		return 0
+	}
+	node := p.procNode()
+	if node < 0 || node >= len(p.nodeShard) {
+		return 0
+	}
+	return p.nodeShard[node]
+}
+
+// procNode returns the NUMA node of the calling thread as cached for the
+// P it runs on.
+//
+// Querying the node takes a getcpu system call, far dearer than a Get, so
+// each P keeps the node it last saw and queries it again every
+// nodeRefresh calls. A P whose thread moved to another node is served
+// from the old node's shard until then, which only costs locality.
+func (p *NodePool[T]) procNode() int {
+	pid := int(procID())
+	procs := *p.procs.Load()
+	if pid >= len(procs) {
+		procs = p.growProcs(pid + 1)
+	}
+	pp := &procs[pid]
+	if pp.calls.Add(1)&(nodeRefresh-1) == 1 {
+		pp.node.Store(int32(currentNode()))
+	}
+	return int(pp.node.Load())
+}
+
+// growProcs makes room for n Ps in the node cache, for GOMAXPROCS
+// raised after the pool was created, and returns the cache.
+func (p *NodePool[T]) growProcs(n int) []nodePoolProc {
+	p.grow.Lock()
+	defer p.grow.Unlock()
+	if cur := p.procs.Load(); cur != nil && len(*cur) >= n {
+		return *cur
+	}
+	procs := make([]nodePoolProc, max(n, runtime.GOMAXPROCS(0)))
+	for i := range procs {
+		procs[i].node.Store(-1)
+	}
+	p.procs.Store(&procs)
+	return procs
+}
+
+// split decodes an indirect index into its shard and the shard's index.
+func (p *NodePool[T]) split(indirect int) (shard, local int) {
+	shard = indirect >> p.shift
+	if indirect < 0 || shard >= len(p.shards) {
+		panic("invalid node pool indirect")
+	}
+	return shard, indirect & (1<<p.shift - 1)
+}
+
+// parseNodeList parses a kernel node list such as "0-3,5". It returns nil
+// if the list is malformed or empty.
+func parseNodeList(s string) []int {
+	var nodes []int
+	for r := range strings.SplitSeq(strings.TrimSpace(s), ",") {
+		lo, hi, isRange := strings.Cut(r, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil || first < 0 {
+			return nil
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil || last < first {
+				return nil
+			}
+		}
+		for n := first; n <= last; n++ {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,195 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"code.hybscloud.com/iox"
+)
+
+func TestParseNodeList(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []int
+	}{
+		{"0\n", []int{0}},
+		{"0-3", []int{0, 1, 2, 3}},
+		{"0-1,4,6-7\n", []int{0, 1, 4, 6, 7}},
+		{"", nil},
+		{"1-0", nil},
+		{"a", nil},
+		{"0,", nil},
+	}
+	for _, tt := range tests {
+		if got := parseNodeList(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("parseNodeList(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNodePool_GetPut(t *testing.T) {
+	p, err := NewNodePool(4, NewNanoBuffer)
+	if err != nil {
+		t.Skipf("NUMA binding not available: %v", err)
+	}
+	p.SetNonblock(true)
+	if got, want := p.Cap(), 4*len(p.Nodes()); got != want {
+		t.Fatalf("Cap() = %d, want %d", got, want)
+	}
+
+	var held []int
+	for range p.Cap() {
+		idx, err := p.Get()
+		if err != nil {
+			t.Fatalf("Get() = %v after %d buffers", err, len(held))
+		}
+		if slices.Contains(held, idx) {
+			t.Fatalf("Get() returned %d twice", idx)
+		}
+		held = append(held, idx)
+	}
+	if _, err := p.Get(); err != iox.ErrWouldBlock {
+		t.Fatalf("Get() from an empty pool = %v, want ErrWouldBlock", err)
+	}
+
+	var buf NanoBuffer
+	buf[0] = 7
+	p.SetValue(held[0], buf)
+	if v := p.Value(held[0]); v[0] != 7 {
+		t.Errorf("Value() after SetValue = %d, want 7", v[0])
+	}
+	for _, idx := range held {
+		if err := p.Put(idx); err != nil {
+			t.Fatalf("Put(%d) = %v", idx, err)
+		}
+	}
+}
+
+func TestNodePool_Borrow(t *testing.T) {
+	// Shard 0 serves the local node; shard 1 stands in for a remote node.
+	node := max(currentNode(), 0)
+	p := newNodePool([]int{node, node + 1}, 2, NewNanoBuffer)
+	p.SetNonblock(true)
+	home, remote := p.Shards()[0], p.Shards()[1]
+	remote.SetStats(true)
+
+	var local []int
+	for range home.Cap() {
+		idx, _ := p.Get()
+		if idx>>p.shift != 0 {
+			t.Fatalf("Get() = %d from shard %d, want the local shard", idx, idx>>p.shift)
+		}
+		local = append(local, idx)
+	}
+	idx, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get() with the local shard empty = %v, want a borrowed buffer", err)
+	}
+	if idx>>p.shift != 1 || p.Borrowed() != 1 {
+		t.Fatalf("Get() = %d, Borrowed() = %d, want a buffer of shard 1 borrowed once", idx, p.Borrowed())
+	}
+
+	// The borrowed buffer goes back to its own shard.
+	_ = p.Put(idx)
+	if s := remote.Stats(); s.Available != remote.Cap() {
+		t.Errorf("remote shard has %d of %d buffers after Put", s.Available, remote.Cap())
+	}
+	for _, idx := range local {
+		_ = p.Put(idx)
+	}
+}
+
+func TestNodePool_PanicInvalidIndirect(t *testing.T) {
+	p := newNodePool([]int{0}, 4, NewNanoBuffer)
+	tests := map[string]func(){
+		"negative":  func() { p.Value(-1) },
+		"too large": func() { _ = p.Put(p.Cap()) },
+	}
+	for name, f := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			f()
+		})
+	}
+}
+
+func TestNodePool_ParkWait(t *testing.T) {
+	p := newNodePool([]int{0, 1}, 1, NewNanoBuffer)
+	p.SetWaitStrategy(&ParkWait{})
+	a, _ := p.Get()
+	b, _ := p.Get()
+
+	// A Put to either shard wakes a Get parked on the whole pool.
+	got := make(chan int)
+	go func() {
+		idx, _ := p.Get()
+		got <- idx
+	}()
+	time.Sleep(5 * time.Millisecond)
+	_ = p.Put(b)
+	if idx := <-got; idx != b {
+		t.Errorf("Get() = %d, want %d", idx, b)
+	}
+	_ = p.Put(a)
+	_ = p.Put(b)
+}
+
+func TestNodePool_LocalShardInstrumentation(t *testing.T) {
+	p := newNodePool([]int{0}, 1, NewNanoBuffer)
+	p.SetNonblock(true)
+	shard := p.Shards()[0]
+	shard.SetStats(true)
+	idx, _ := p.Get()
+	if _, err := p.Get(); err != iox.ErrWouldBlock {
+		t.Fatalf("Get() from an empty pool = %v, want ErrWouldBlock", err)
+	}
+	if s := shard.Stats(); s.WouldBlocks != 1 || s.Gets != 1 {
+		t.Errorf("shard Stats(): WouldBlocks %d, Gets %d; want 1, 1", s.WouldBlocks, s.Gets)
+	}
+
+	// A blocking Get reports its wait to the shard's hooks.
+	p.SetNonblock(false)
+	var blocked, unblocked atomic.Int32
+	shard.SetHooks(&PoolHooks{
+		OnBlock:   func(PoolOp) { blocked.Add(1) },
+		OnUnblock: func(PoolOp, time.Duration) { unblocked.Add(1) },
+	})
+	done := make(chan struct{})
+	go func() {
+		got, _ := p.Get()
+		_ = p.Put(got)
+		close(done)
+	}()
+	time.Sleep(5 * time.Millisecond)
+	_ = p.Put(idx)
+	<-done
+	if blocked.Load() != 1 || unblocked.Load() != 1 {
+		t.Errorf("OnBlock called %d times, OnUnblock %d times; want 1, 1", blocked.Load(), unblocked.Load())
+	}
+}
+
+func TestNodePool_ProcNode(t *testing.T) {
+	p := newNodePool([]int{0, 1}, 1, NewNanoBuffer)
+	want := currentNode()
+	for range 2 * nodeRefresh {
+		if got := p.procNode(); got != want {
+			t.Fatalf("procNode() = %d, want %d", got, want)
+		}
+	}
+
+	// A P beyond the cache, after GOMAXPROCS was raised, grows it.
+	n := len(*p.procs.Load())
+	if procs := p.growProcs(n + 1); len(procs) != n+1 || len(*p.procs.Load()) != n+1 {
+		t.Errorf("growProcs(%d) = %d entries", n+1, len(procs))
+	}
+}
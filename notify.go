@@ -0,0 +1,80 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "errors"
+
+// poolNotify holds the descriptors signalled on occupancy transitions,
+// indexed by PoolOp, or -1 where none is attached.
+type poolNotify struct {
+	fds [2]int
+}
+
+// SetNotify attaches fd to the pool to be signalled whenever calls of op
+// can proceed again: for PoolGet, when the pool goes from empty to
+// non-empty; for PoolPut, when it goes from full to non-full. A negative
+// fd detaches the descriptor of op.
+//
+// This lets an event loop wait for buffers with epoll or io_uring
+// alongside socket readiness instead of polling a non-blocking Get. fd is
+// typically an eventfd, or the write end of a pipe watched through its
+// read end. A signal writes an 8-byte value of 1, which adds to an
+// eventfd's counter; write errors, such as a full pipe, are ignored since
+// fd is then readable anyway. The caller owns fd and must drain it before
+// retrying, so a transition between its retry and its next wait still
+// wakes it:
+//
+//	for {
+//		idx, err := pool.Get() // non-blocking pool
+//		if err == nil {
+//			break
+//		}
+//		// wait for efd to become readable, then read it to reset
+//	}
+//
+// Signals are edge-triggered and may be spurious under contention, when a
+// call rolling back its reservation completes a transition. Transfers
+// through SetElimination do not signal, since they only reach a Get that
+// is already waiting. SetNotify must be called before the pool is shared
+// between goroutines. Returns errors.ErrUnsupported on platforms without
+// Unix file descriptors.
+func (pool *BoundedPool[T]) SetNotify(op PoolOp, fd int) error {
+	if !notifySupported {
+		return errors.ErrUnsupported
+	}
+	if pool.notify == nil {
+		if fd < 0 {
+			return nil
+		}
+		pool.notify = &poolNotify{fds: [2]int{-1, -1}}
+	}
+	pool.notify.fds[op] = max(fd, -1)
+	if pool.notify.fds == [2]int{-1, -1} {
+		pool.notify = nil
+	}
+	pool.updateSignal()
+	return nil
+}
+
+// updateSignal enables the transition signals while a descriptor or a
+// wait strategy takes them.
+func (pool *BoundedPool[T]) updateSignal() {
+	if pool.notify != nil || pool.waiter != nil {
+		pool.debug |= poolSignal
+	} else {
+		pool.debug &^= poolSignal
+	}
+}
+
+// signal reports that calls of op can proceed again to the descriptor
+// attached for op and the wait strategy, if any.
+func (pool *BoundedPool[T]) signal(op PoolOp) {
+	if n := pool.notify; n != nil && n.fds[op] >= 0 {
+		signalFd(n.fds[op])
+	}
+	if w := pool.waiter; w != nil {
+		w.strategy.Wake(op)
+	}
+}
@@ -0,0 +1,14 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package iobuf
+
+// notifySupported reports whether SetNotify can signal descriptors.
+// Without Unix file descriptors it cannot.
+const notifySupported = false
+
+// signalFd is never called without Unix file descriptors.
+func signalFd(fd int) {}
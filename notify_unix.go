@@ -0,0 +1,24 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package iobuf
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// notifySupported reports whether SetNotify can signal descriptors.
+const notifySupported = true
+
+// notifyValue is the value written by signalFd, in native byte order as
+// eventfd expects.
+var notifyValue uint64 = 1
+
+// signalFd writes notifyValue to fd, ignoring errors.
+func signalFd(fd int) {
+	_, _ = syscall.Write(fd, unsafe.Slice((*byte)(unsafe.Pointer(&notifyValue)), 8))
+}
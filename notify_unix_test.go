@@ -0,0 +1,78 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package iobuf_test
+
+import (
+	"os"
+	"syscall"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+// notifyPipe returns a pipe whose read end does not block, and a function
+// returning the number of signals written to it since the last call.
+func notifyPipe(t *testing.T) (w int, signals func() int) {
+	r, wf, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close(); wf.Close() })
+	rfd := int(r.Fd())
+	if err := syscall.SetNonblock(rfd, true); err != nil {
+		t.Fatal(err)
+	}
+	return int(wf.Fd()), func() int {
+		buf := make([]byte, 64)
+		n, _ := syscall.Read(rfd, buf)
+		return max(n, 0) / 8
+	}
+}
+
+func TestBoundedPool_SetNotify(t *testing.T) {
+	pool := iobuf.NewBoundedPool[int](2)
+	pool.Fill(func() int { return 0 })
+	pool.SetNonblock(true)
+	getFd, gets := notifyPipe(t)
+	putFd, puts := notifyPipe(t)
+	if err := pool.SetNotify(iobuf.PoolGet, getFd); err != nil {
+		t.Fatalf("SetNotify(PoolGet) = %v", err)
+	}
+	if err := pool.SetNotify(iobuf.PoolPut, putFd); err != nil {
+		t.Fatalf("SetNotify(PoolPut) = %v", err)
+	}
+
+	a, _ := pool.Get()
+	if n := puts(); n != 1 {
+		t.Errorf("full to non-full: %d Put signals, want 1", n)
+	}
+	b, _ := pool.Get()
+	if _, err := pool.Get(); err == nil {
+		t.Fatal("Get() from an empty pool succeeded")
+	}
+	if n := gets() + puts(); n != 0 {
+		t.Errorf("%d signals without a transition, want 0", n)
+	}
+
+	_ = pool.Put(a)
+	if n := gets(); n != 1 {
+		t.Errorf("empty to non-empty: %d Get signals, want 1", n)
+	}
+	_ = pool.Put(b)
+	if n := gets() + puts(); n != 0 {
+		t.Errorf("%d signals without a transition, want 0", n)
+	}
+
+	// Detached descriptors are no longer signalled.
+	_ = pool.SetNotify(iobuf.PoolGet, -1)
+	_ = pool.SetNotify(iobuf.PoolPut, -1)
+	a, _ = pool.Get()
+	_ = pool.Put(a)
+	if n := gets() + puts(); n != 0 {
+		t.Errorf("%d signals after detaching, want 0", n)
+	}
+}
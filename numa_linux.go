@@ -0,0 +1,153 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"cmp"
+	"os"
+	"reflect"
+	"runtime"
+	"slices"
+	"syscall"
+	"unsafe"
+)
+
+// mbind policy mode and flags from linux/mempolicy.h.
+const (
+	mpolBind   = 2
+	mpolMfMove = 1 << 1
+)
+
+// AlignedMemOnNode returns a page-aligned byte slice of the given size whose
+// pages are bound to the NUMA node (mbind with MPOL_BIND). Pages already
+// faulted in on another node are migrated.
+//
+// Buffers touched by threads on a different socket pay cross-node memory
+// latency on every access; binding each connection group's buffers to the
+// node it runs on avoids that. The memory comes from the Go heap like
+// AlignedMem and needs no explicit release. The policy stays on the pages
+// after the memory is collected: the runtime may reuse them for unrelated
+// objects, which are then bound to the node as well. Keep node-bound
+// memory for the life of the process, or map it with mmap and mbind it
+// directly to have it released with its policy.
+//
+// Panics if size < 1 or node < 0.
+func AlignedMemOnNode(size int, node int) ([]byte, error) {
+	if size < 1 {
+		panic("iobuf.AlignedMemOnNode: size must be positive")
+	}
+	ps := syscall.Getpagesize()
+	span := (size + ps - 1) &^ (ps - 1)
+	mem := AlignedMem(span, uintptr(ps))
+	if err := mbindNode(mem, node); err != nil {
+		return nil, err
+	}
+	return mem[:size], nil
+}
+
+// BindNode binds the memory of the pool's items to a NUMA node, migrating
+// pages already faulted in. Every page holding part of an item is bound:
+// for array items the pages spanning the item array, for []byte items the
+// pages spanning each buffer, with buffers sharing pages bound together.
+// Pages at the ends may hold neighboring memory, which is bound as well.
+//
+// Items in the Go heap keep the policy on their pages after they are
+// collected, see AlignedMemOnNode. BindNode must be called after Fill and
+// before the pool is shared between goroutines. Panics if node < 0.
+func (pool *BoundedPool[T]) BindNode(node int) error {
+	if len(pool.items) == 0 {
+		panic("must Fill the pool before using it")
+	}
+	if node < 0 {
+		panic("iobuf: negative NUMA node")
+	}
+	if reflect.TypeFor[T]().Kind() != reflect.Slice {
+		size := unsafe.Sizeof(pool.items[0])
+		mem := unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(pool.items))), uintptr(len(pool.items))*size)
+		return mbindNode(mem, node)
+	}
+	spans := make([]pageSpan, 0, len(pool.items))
+	for i := range pool.items {
+		if b := pool.itemBytes(i); len(b) > 0 {
+			spans = append(spans, pagesOf(b))
+		}
+	}
+	slices.SortFunc(spans, func(a, b pageSpan) int { return cmp.Compare(a.start, b.start) })
+	for i := 0; i < len(spans); {
+		span := spans[i]
+		for i++; i < len(spans) && spans[i].start <= span.end; i++ {
+			span.end = max(span.end, spans[i].end)
+		}
+		if err := mbindSpan(span, node); err != nil {
+			return err
+		}
+	}
+	runtime.KeepAlive(pool.items)
+	return nil
+}
+
+// pageSpan is the address range [start, end) of whole pages.
+type pageSpan struct {
+	start, end uintptr
+}
+
+// pagesOf returns the span of the pages holding mem, which must not be
+// empty.
+func pagesOf(mem []byte) pageSpan {
+	ps := uintptr(syscall.Getpagesize())
+	base := uintptr(unsafe.Pointer(unsafe.SliceData(mem)))
+	return pageSpan{base &^ (ps - 1), (base + uintptr(len(mem)) + ps - 1) &^ (ps - 1)}
+}
+
+// mbindNode applies MPOL_BIND for node to every page holding part of mem.
+func mbindNode(mem []byte, node int) error {
+	if node < 0 {
+		panic("iobuf: negative NUMA node")
+	}
+	if len(mem) == 0 {
+		return nil
+	}
+	err := mbindSpan(pagesOf(mem), node)
+	runtime.KeepAlive(mem)
+	return err
+}
+
+// mbindSpan applies MPOL_BIND for node to the pages of span. The caller
+// keeps the memory alive and checks node.
+func mbindSpan(span pageSpan, node int) error {
+	mask := make([]uint64, node/64+1)
+	mask[node/64] = 1 << (node % 64)
+	_, _, errno := syscall.Syscall6(syscall.SYS_MBIND,
+		span.start, span.end-span.start, mpolBind,
+		uintptr(unsafe.Pointer(unsafe.SliceData(mask))), uintptr(len(mask)*64+1), mpolMfMove)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// onlineNodes returns the online NUMA nodes, or node 0 alone if they
+// cannot be read.
+func onlineNodes() []int {
+	b, err := os.ReadFile("/sys/devices/system/node/online")
+	if err != nil {
+		return []int{0}
+	}
+	if nodes := parseNodeList(string(b)); len(nodes) > 0 {
+		return nodes
+	}
+	return []int{0}
+}
+
+// currentNode returns the NUMA node of the CPU the calling thread runs on,
+// or -1 if getcpu fails. The thread may migrate right after.
+func currentNode() int {
+	var cpu, node uint32
+	_, _, errno := syscall.RawSyscall(sysGetcpu, uintptr(unsafe.Pointer(&cpu)), uintptr(unsafe.Pointer(&node)), 0)
+	if errno != 0 {
+		return -1
+	}
+	return int(node)
+}
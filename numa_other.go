@@ -0,0 +1,38 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package iobuf
+
+import "errors"
+
+// AlignedMemOnNode returns page-aligned memory bound to a NUMA node.
+// NUMA binding is only supported on Linux; elsewhere it returns
+// errors.ErrUnsupported.
+func AlignedMemOnNode(size int, node int) ([]byte, error) {
+	if size < 1 {
+		panic("iobuf.AlignedMemOnNode: size must be positive")
+	}
+	if node < 0 {
+		panic("iobuf: negative NUMA node")
+	}
+	return nil, errors.ErrUnsupported
+}
+
+// BindNode binds the memory of the pool's items to a NUMA node.
+// NUMA binding is only supported on Linux; elsewhere it returns
+// errors.ErrUnsupported.
+func (pool *BoundedPool[T]) BindNode(node int) error {
+	if node < 0 {
+		panic("iobuf: negative NUMA node")
+	}
+	return errors.ErrUnsupported
+}
+
+// onlineNodes returns node 0, the only node without NUMA support.
+func onlineNodes() []int { return []int{0} }
+
+// currentNode returns node 0, the only node without NUMA support.
+func currentNode() int { return 0 }
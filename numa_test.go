@@ -0,0 +1,85 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+// skipNUMA skips the test if the platform or sandbox does not allow mbind.
+func skipNUMA(t *testing.T, err error) {
+	t.Helper()
+	if errors.Is(err, errors.ErrUnsupported) || errors.Is(err, syscall.ENOSYS) || errors.Is(err, syscall.EPERM) {
+		t.Skipf("NUMA binding not available: %v", err)
+	}
+}
+
+func TestAlignedMemOnNode(t *testing.T) {
+	mem, err := iobuf.AlignedMemOnNode(10000, 0)
+	skipNUMA(t, err)
+	if err != nil {
+		t.Fatalf("AlignedMemOnNode(node 0) failed: %v", err)
+	}
+	if len(mem) != 10000 || uintptr(unsafe.Pointer(unsafe.SliceData(mem)))%4096 != 0 {
+		t.Errorf("len=%d, want 10000 page-aligned bytes", len(mem))
+	}
+	mem[len(mem)-1] = 1
+
+	if _, err := iobuf.AlignedMemOnNode(4096, 1000); err == nil {
+		t.Error("AlignedMemOnNode on a nonexistent node should fail")
+	}
+}
+
+func TestBoundedPool_BindNode(t *testing.T) {
+	arrays := iobuf.NewMediumBufferPool(4)
+	arrays.Fill(iobuf.NewMediumBuffer)
+	err := arrays.BindNode(0)
+	skipNUMA(t, err)
+	if err != nil {
+		t.Fatalf("BindNode(0) on array pool failed: %v", err)
+	}
+
+	slices := iobuf.NewBoundedPool[[]byte](4)
+	slices.Fill(func() []byte { return iobuf.AlignedMem(8192, iobuf.PageSize) })
+	if err := slices.BindNode(0); err != nil {
+		t.Fatalf("BindNode(0) on slice pool failed: %v", err)
+	}
+}
+
+func TestBoundedPool_BindNodeSmallItems(t *testing.T) {
+	// Items smaller than a page are bound through the pages holding
+	// them, so binding to a nonexistent node fails instead of binding
+	// nothing.
+	picos := iobuf.NewPicoBufferPool(4)
+	picos.Fill(iobuf.NewPicoBuffer)
+	err := picos.BindNode(0)
+	skipNUMA(t, err)
+	if err != nil {
+		t.Fatalf("BindNode(0) on small array pool failed: %v", err)
+	}
+	if err := picos.BindNode(1000); err == nil {
+		t.Error("BindNode on a nonexistent node should fail for small arrays")
+	}
+
+	mtu := iobuf.NewBoundedPool[[]byte](8)
+	mem := iobuf.AlignedMem(1500*mtu.Cap(), iobuf.PageSize)
+	i := 0
+	mtu.Fill(func() []byte {
+		b := mem[i*1500 : (i+1)*1500]
+		i++
+		return b
+	})
+	if err := mtu.BindNode(0); err != nil {
+		t.Fatalf("BindNode(0) on 1500-byte buffers failed: %v", err)
+	}
+	if err := mtu.BindNode(1000); err == nil {
+		t.Error("BindNode on a nonexistent node should fail for sub-page buffers")
+	}
+}
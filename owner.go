@@ -0,0 +1,72 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"time"
+)
+
+// Holder describes the caller holding a buffer, as recorded by ownership
+// tracking.
+type Holder struct {
+	Indirect  int       // Index of the held buffer
+	Goroutine uint64    // ID of the goroutine that called Get
+	Acquired  time.Time // When Get returned the buffer
+	Stack     []uintptr // Program counters of the Get call site, as from runtime.Callers
+}
+
+// Frames returns the frames of the Get call site.
+func (h Holder) Frames() *runtime.Frames { return runtime.CallersFrames(h.Stack) }
+
+// Holders returns the callers currently holding buffers of the pool,
+// ordered by index.
+//
+// Ownership tracking is only compiled in with the iobufdebug build tag,
+// see OwnershipTracking; otherwise Holders returns nil. With the tag,
+// every Get records the calling goroutine and its stack, and Put clears
+// the record, so a pool that slowly drains to zero can be traced back to
+// the code that keeps its buffers:
+//
+//	go build -tags iobufdebug ./cmd/server
+func (pool *BoundedPool[T]) Holders() []Holder {
+	hs := pool.owners.holders()
+	slices.SortFunc(hs, func(a, b Holder) int { return a.Indirect - b.Indirect })
+	return hs
+}
+
+// DumpHolders writes the callers currently holding buffers of the pool to
+// w, one stack trace per buffer, in the style of a goroutine dump. Without
+// the iobufdebug build tag it writes a note that tracking is disabled.
+func (pool *BoundedPool[T]) DumpHolders(w io.Writer) error {
+	if !OwnershipTracking {
+		_, err := io.WriteString(w, "iobuf: ownership tracking disabled, build with -tags iobufdebug\n")
+		return err
+	}
+	now := time.Now()
+	for _, h := range pool.Holders() {
+		if _, err := fmt.Fprintf(w, "buffer %d held by goroutine %d for %v:\n",
+			h.Indirect, h.Goroutine, now.Sub(h.Acquired).Round(time.Millisecond)); err != nil {
+			return err
+		}
+		frames := h.Frames()
+		for {
+			f, more := frames.Next()
+			if _, err := fmt.Fprintf(w, "%s\n\t%s:%d\n", f.Function, f.File, f.Line); err != nil {
+				return err
+			}
+			if !more {
+				break
+			}
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build iobufdebug
+
+package iobuf
+
+import (
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// OwnershipTracking reports whether pools record the holder of every
+// buffer. It is true in builds with the iobufdebug tag.
+const OwnershipTracking = true
+
+// ownerStackDepth is the number of frames recorded per holder.
+const ownerStackDepth = 32
+
+// ownerTable records the holder of every index of a pool.
+type ownerTable struct {
+	slots []atomic.Pointer[Holder]
+}
+
+func (t *ownerTable) init(capacity int) {
+	t.slots = make([]atomic.Pointer[Holder], capacity)
+}
+
+// acquire records the calling goroutine as the holder of indirect. The
+// recorded stack starts skip frames above the caller of acquire, leaving
+// out the pool's own frames.
+func (t *ownerTable) acquire(indirect int, skip int) {
+	var pcs [ownerStackDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	t.slots[indirect].Store(&Holder{
+		Indirect:  indirect,
+		Goroutine: goroutineID(),
+		Acquired:  time.Now(),
+		Stack:     pcs[:n:n],
+	})
+}
+
+// release clears the holder of indirect.
+func (t *ownerTable) release(indirect int) {
+	t.slots[indirect].Store(nil)
+}
+
+// holders returns the recorded holders.
+func (t *ownerTable) holders() []Holder {
+	var hs []Holder
+	for i := range t.slots {
+		if h := t.slots[i].Load(); h != nil {
+			hs = append(hs, *h)
+		}
+	}
+	return hs
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the
+// header of its stack trace.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = b[len("goroutine "):]
+	for i, c := range b {
+		if c == ' ' {
+			b = b[:i]
+			break
+		}
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
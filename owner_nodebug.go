@@ -0,0 +1,19 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !iobufdebug
+
+package iobuf
+
+// OwnershipTracking reports whether pools record the holder of every
+// buffer. It is false unless built with the iobufdebug tag.
+const OwnershipTracking = false
+
+// ownerTable is empty without ownership tracking.
+type ownerTable struct{}
+
+func (*ownerTable) init(int)          {}
+func (*ownerTable) acquire(int, int)  {}
+func (*ownerTable) release(int)       {}
+func (*ownerTable) holders() []Holder { return nil }
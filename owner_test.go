@@ -0,0 +1,65 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"strings"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+// holdBuffer gets a buffer from pool so that it shows up as the holder.
+//
+//go:noinline
+func holdBuffer(t *testing.T, pool *iobuf.NanoBufferBoundedPool) int {
+	idx, err := pool.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return idx
+}
+
+func TestBoundedPool_Holders(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(4)
+	pool.Fill(iobuf.NewNanoBuffer)
+	released := holdBuffer(t, pool)
+	held := holdBuffer(t, pool)
+	_ = pool.Put(released)
+
+	var dump strings.Builder
+	if err := pool.DumpHolders(&dump); err != nil {
+		t.Fatal(err)
+	}
+	hs := pool.Holders()
+
+	if !iobuf.OwnershipTracking {
+		if hs != nil {
+			t.Errorf("Holders() = %v without the iobufdebug tag", hs)
+		}
+		if !strings.Contains(dump.String(), "tracking disabled") {
+			t.Errorf("DumpHolders() = %q", dump.String())
+		}
+		return
+	}
+
+	if len(hs) != 1 || hs[0].Indirect != held {
+		t.Fatalf("Holders() = %+v, want buffer %d only", hs, held)
+	}
+	if hs[0].Goroutine == 0 || hs[0].Acquired.IsZero() {
+		t.Errorf("holder = %+v", hs[0])
+	}
+	if f, _ := hs[0].Frames().Next(); !strings.HasSuffix(f.Function, ".holdBuffer") {
+		t.Errorf("innermost frame = %s, want holdBuffer", f.Function)
+	}
+	if !strings.Contains(dump.String(), "held by goroutine") || !strings.Contains(dump.String(), "holdBuffer") {
+		t.Errorf("DumpHolders() = %q", dump.String())
+	}
+
+	_ = pool.Put(held)
+	if hs := pool.Holders(); len(hs) != 0 {
+		t.Errorf("Holders() after Put = %+v", hs)
+	}
+}
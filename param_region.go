@@ -0,0 +1,122 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"unsafe"
+)
+
+// ErrRegionInUse is returned when freeing memory still registered with an
+// io_uring instance.
+var ErrRegionInUse = errors.New("iobuf: region registered with a ring")
+
+// RegWait matches struct io_uring_reg_wait, the wait arguments read by
+// io_uring_enter with IORING_ENTER_EXT_ARG_REG from a registered region.
+type RegWait struct {
+	Sec         int64  // Timeout seconds
+	Nsec        int64  // Timeout nanoseconds
+	MinWaitUsec uint32 // Minimum wait before returning partial completions
+	Flags       uint32 // IORING_REG_WAIT_TS to apply the timeout
+	Sigmask     uint64 // Address of the signal mask, or zero
+	SigmaskSz   uint32 // Size of the signal mask
+	_           [3]uint32
+	_           [2]uint64
+}
+
+var _ [unsafe.Sizeof(RegWait{}) - 64]struct{}
+var _ [64 - unsafe.Sizeof(RegWait{})]struct{}
+
+// ParamRegion is page-aligned memory registered with io_uring as a
+// parameter region (IORING_REGISTER_MEM_REGION, Linux 6.13+), shared with
+// the kernel to pass wait arguments without copying them on every
+// io_uring_enter.
+//
+// The kernel offers no way to unregister the region: it stays pinned
+// until the ring is closed. ParamRegion tracks the rings it is registered
+// with and refuses to Close while any of them is still open, so the pages
+// cannot be unmapped under the kernel. Call ReleaseRing after closing each
+// ring. A ParamRegion is safe for concurrent use.
+type ParamRegion struct {
+	mu    sync.Mutex
+	mem   []byte
+	rings []int // Rings the region is registered with
+}
+
+// NewParamRegion maps a region of size bytes rounded up to whole pages.
+// Panics if size < 1.
+func NewParamRegion(size int) (*ParamRegion, error) {
+	if size < 1 {
+		panic("iobuf.NewParamRegion: size must be positive")
+	}
+	page := int(PageSize)
+	mem, err := mapRegion((size+page-1)&^(page-1), 0)
+	if err != nil {
+		return nil, err
+	}
+	return &ParamRegion{mem: mem}, nil
+}
+
+// Bytes returns the region memory.
+func (r *ParamRegion) Bytes() []byte { return r.mem }
+
+// WaitArgs returns the region viewed as an array of wait arguments; the
+// index of an entry is what io_uring_enter takes as its offset.
+func (r *ParamRegion) WaitArgs() []RegWait {
+	return unsafe.Slice((*RegWait)(unsafe.Pointer(unsafe.SliceData(r.mem))), len(r.mem)/int(unsafe.Sizeof(RegWait{})))
+}
+
+// Registered reports whether the region is registered with any ring not
+// yet released.
+func (r *ParamRegion) Registered() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.rings) > 0
+}
+
+// Register registers the region with the io_uring instance ringFd. With
+// waitArgs set the region serves wait arguments, which the kernel only
+// accepts while the ring is still disabled (IORING_SETUP_R_DISABLED).
+// Returns errors.ErrUnsupported on platforms without io_uring.
+func (r *ParamRegion) Register(ringFd int, waitArgs bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mem == nil {
+		panic("iobuf.ParamRegion.Register: region is closed")
+	}
+	if err := registerMemRegion(ringFd, r.mem, waitArgs); err != nil {
+		return err
+	}
+	r.rings = append(r.rings, ringFd)
+	return nil
+}
+
+// ReleaseRing records that ring ringFd, with which the region was
+// registered, has been closed. Panics if the region is not registered
+// with ringFd.
+func (r *ParamRegion) ReleaseRing(ringFd int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.Index(r.rings, ringFd)
+	if i < 0 {
+		panic("iobuf.ParamRegion.ReleaseRing: region not registered with ring")
+	}
+	r.rings = slices.Delete(r.rings, i, i+1)
+}
+
+// Close unmaps the region. Returns ErrRegionInUse, leaving the region
+// intact, while it is registered with a ring not yet released.
+func (r *ParamRegion) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.rings) > 0 {
+		return ErrRegionInUse
+	}
+	mem := r.mem
+	r.mem = nil
+	return unmapRegion(mem)
+}
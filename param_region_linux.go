@@ -0,0 +1,26 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// registerMemRegion registers mem as the ring's parameter region.
+func registerMemRegion(ringFd int, mem []byte, waitArgs bool) error {
+	desc := ioUringRegionDesc{
+		userAddr: uint64(uintptr(unsafe.Pointer(unsafe.SliceData(mem)))),
+		size:     uint64(len(mem)),
+		flags:    ioringMemRegionTypeUser,
+	}
+	reg := ioUringMemRegionReg{regionUptr: uint64(uintptr(unsafe.Pointer(&desc)))}
+	if waitArgs {
+		reg.flags = ioringMemRegionRegWaitArg
+	}
+	err := ioUringRegister(ringFd, ioringRegisterMemRegion, unsafe.Pointer(&reg), 1)
+	runtime.KeepAlive(&desc)
+	return err
+}
@@ -0,0 +1,62 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && !mips64 && !mips64le
+
+package iobuf_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestParamRegion_Lifetime(t *testing.T) {
+	// Wait-argument regions need a ring created disabled.
+	var params [120]byte
+	*(*uint32)(unsafe.Pointer(&params[8])) = 1 << 6 // IORING_SETUP_R_DISABLED
+	fd, _, errno := syscall.Syscall(sysIoUringSetup, 4, uintptr(unsafe.Pointer(&params[0])), 0)
+	if errno != 0 {
+		t.Skipf("io_uring not available: %v", errno)
+	}
+	ring := int(fd)
+
+	r, err := iobuf.NewParamRegion(100)
+	if err != nil {
+		t.Fatalf("NewParamRegion() failed: %v", err)
+	}
+	if len(r.Bytes()) != int(iobuf.PageSize) || len(r.WaitArgs()) != int(iobuf.PageSize)/64 {
+		t.Fatalf("region len = %d with %d wait args", len(r.Bytes()), len(r.WaitArgs()))
+	}
+	if err := r.Register(ring, true); err != nil {
+		syscall.Close(ring)
+		if errors.Is(err, syscall.EINVAL) {
+			t.Skipf("memory regions not supported: %v", err)
+		}
+		t.Fatalf("Register() failed: %v", err)
+	}
+	if !r.Registered() {
+		t.Error("Registered() = false after Register")
+	}
+	if err := r.Close(); !errors.Is(err, iobuf.ErrRegionInUse) {
+		t.Errorf("Close() while registered = %v, want ErrRegionInUse", err)
+	}
+	r.WaitArgs()[0].MinWaitUsec = 50 // still mapped
+
+	syscall.Close(ring)
+	r.ReleaseRing(ring)
+	if err := r.Close(); err != nil {
+		t.Errorf("Close() after ReleaseRing failed: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("ReleaseRing of an unknown ring should panic")
+		}
+	}()
+	r.ReleaseRing(ring)
+}
@@ -0,0 +1,14 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package iobuf
+
+import "errors"
+
+// registerMemRegion is not supported without io_uring.
+func registerMemRegion(ringFd int, mem []byte, waitArgs bool) error {
+	return errors.ErrUnsupported
+}
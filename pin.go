@@ -0,0 +1,35 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrMemlockLimit is returned by Pin when pinning the memory would exceed
+// the process's RLIMIT_MEMLOCK.
+var ErrMemlockLimit = errors.New("iobuf: RLIMIT_MEMLOCK exceeded")
+
+// pinnedBytes is the page-rounded size of all memory pinned through Pin.
+var pinnedBytes atomic.Int64
+
+// PinnedBytes returns the number of bytes currently pinned through Pin,
+// rounded to whole pages.
+func PinnedBytes() int { return int(pinnedBytes.Load()) }
+
+// reservePinned adds size to the pinned total unless it would exceed limit.
+// A negative limit means unlimited.
+func reservePinned(size, limit int64) bool {
+	for {
+		cur := pinnedBytes.Load()
+		if limit >= 0 && cur+size > limit {
+			return false
+		}
+		if pinnedBytes.CompareAndSwap(cur, cur+size) {
+			return true
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"math"
+	"runtime"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// Pin locks the pages of mem into RAM (mlock) so they cannot be paged out,
+// e.g., buffers registered with io_uring or buffers holding key material.
+//
+// Pinned memory counts against RLIMIT_MEMLOCK. Pin keeps a process-wide
+// tally of the memory it has pinned and returns ErrMemlockLimit up front
+// when the request would exceed the soft limit, instead of failing with
+// ENOMEM partway through. Memory pinned by other means is not counted.
+//
+// The pages stay pinned until Unpin is called with the same slice. Heap
+// memory may be moved by a future moving collector; pin memory that is
+// guaranteed not to move, such as AlignedMemHuge mappings, when the
+// address matters.
+func Pin(mem []byte) error {
+	size := pinSpan(mem)
+	if size == 0 {
+		return nil
+	}
+	var rlim syscall.Rlimit
+	limit := int64(-1)
+	if err := syscall.Getrlimit(rlimitMemlock(), &rlim); err == nil && rlim.Cur != math.MaxUint64 {
+		limit = int64(min(rlim.Cur, math.MaxInt64))
+	}
+	if !reservePinned(size, limit) {
+		return ErrMemlockLimit
+	}
+	if err := syscall.Mlock(mem); err != nil {
+		pinnedBytes.Add(-size)
+		return err
+	}
+	return nil
+}
+
+// Unpin unlocks the pages of mem previously pinned with Pin.
+func Unpin(mem []byte) {
+	size := pinSpan(mem)
+	if size == 0 {
+		return
+	}
+	if syscall.Munlock(mem) == nil {
+		pinnedBytes.Add(-size)
+	}
+}
+
+// pinSpan returns the size of the whole pages covering mem, which is what
+// the kernel charges against RLIMIT_MEMLOCK.
+func pinSpan(mem []byte) int64 {
+	if len(mem) == 0 {
+		return 0
+	}
+	ps := uintptr(syscall.Getpagesize())
+	base := uintptr(unsafe.Pointer(unsafe.SliceData(mem)))
+	start := base &^ (ps - 1)
+	end := (base + uintptr(len(mem)) + ps - 1) &^ (ps - 1)
+	return int64(end - start)
+}
+
+// rlimitMemlock returns RLIMIT_MEMLOCK, which package syscall does not
+// define. MIPS numbers its resource limits differently.
+func rlimitMemlock() int {
+	if strings.HasPrefix(runtime.GOARCH, "mips") {
+		return 9
+	}
+	return 8
+}
@@ -0,0 +1,22 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package iobuf
+
+import "errors"
+
+// Pin locks the pages of mem into RAM. Pinning is only supported on Linux;
+// elsewhere it returns errors.ErrUnsupported.
+func Pin(mem []byte) error {
+	if len(mem) == 0 {
+		return nil
+	}
+	return errors.ErrUnsupported
+}
+
+// Unpin unlocks the pages of mem. Pinning is only supported on Linux;
+// elsewhere it is a no-op.
+func Unpin(mem []byte) {}
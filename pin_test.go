@@ -0,0 +1,50 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"errors"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestPin_Unpin(t *testing.T) {
+	mem := iobuf.AlignedMem(4*4096, 4096)
+	before := iobuf.PinnedBytes()
+	if err := iobuf.Pin(mem); err != nil {
+		if errors.Is(err, errors.ErrUnsupported) || errors.Is(err, iobuf.ErrMemlockLimit) {
+			t.Skipf("pinning not available: %v", err)
+		}
+		t.Fatalf("Pin() failed: %v", err)
+	}
+	if got := iobuf.PinnedBytes() - before; got != len(mem) {
+		t.Errorf("PinnedBytes grew by %d, want %d", got, len(mem))
+	}
+	iobuf.Unpin(mem)
+	if iobuf.PinnedBytes() != before {
+		t.Errorf("PinnedBytes after Unpin = %d, want %d", iobuf.PinnedBytes(), before)
+	}
+	if err := iobuf.Pin(nil); err != nil {
+		t.Errorf("Pin(nil) = %v", err)
+	}
+}
+
+func TestPin_MemlockLimit(t *testing.T) {
+	// Beyond the common 8 MiB default RLIMIT_MEMLOCK; the budget check
+	// rejects it before asking the kernel to fault the pages in.
+	mem := make([]byte, 64<<20)
+	err := iobuf.Pin(mem)
+	if err == nil {
+		iobuf.Unpin(mem)
+		t.Skip("RLIMIT_MEMLOCK is unlimited")
+	}
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("pinning not supported")
+	}
+	if !errors.Is(err, iobuf.ErrMemlockLimit) {
+		t.Errorf("Pin() beyond limit = %v, want ErrMemlockLimit", err)
+	}
+}
@@ -49,6 +49,22 @@ type IndirectPool[T BufferType] interface {
 	SetValue(indirect int, item T)
 }
 
+// BufferPool is a tier-agnostic indirect pool of byte buffers.
+//
+// It lets code acquire and access pooled buffers without naming a concrete
+// tier type. *BoundedPool[T] implements BufferPool for every tier buffer
+// type and for []byte items.
+type BufferPool interface {
+	Pool[int]
+
+	// Bytes returns a view of the buffer at the given indirect index.
+	// The caller must have acquired this index via Get.
+	Bytes(indirect int) []byte
+
+	// Cap returns the number of buffers managed by the pool.
+	Cap() int
+}
+
 type (
 	// PicoBufferPool manages 32-byte buffers via indirect indexing.
 	PicoBufferPool = IndirectPool[PicoBuffer]
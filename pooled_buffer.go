@@ -0,0 +1,184 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "io"
+
+// PooledBuffer is a variable-sized byte buffer with the common bytes.Buffer
+// method set, backed by segments acquired from buffer pools.
+//
+// The pools are given in order of increasing buffer size, e.g., Small,
+// Medium and Big tier pools. The buffer starts in the smallest segment
+// that fits and moves to a larger one as it grows, returning the previous
+// segment to its pool. Growth beyond the largest pool, or while every
+// suitable pool is exhausted in non-blocking mode, falls back to a heap
+// allocation, so writes never fail.
+//
+// Close returns the current segment to its pool; the zero value is an empty
+// buffer that only uses the heap. A PooledBuffer must not be copied after
+// first use or used from multiple goroutines concurrently.
+type PooledBuffer struct {
+	_ noCopy
+
+	pools []BufferPool
+	sizes []int // Segment size per pool, 0 until first observed
+
+	pool     BufferPool // Pool of the current segment, nil for heap
+	indirect int
+	buf      []byte // Current segment
+	off, n   int    // Unread data is buf[off:n]
+}
+
+// NewPooledBuffer returns an empty PooledBuffer that acquires segments
+// from pools, which must be ordered by increasing buffer size.
+func NewPooledBuffer(pools ...BufferPool) *PooledBuffer {
+	return &PooledBuffer{pools: pools, sizes: make([]int, len(pools))}
+}
+
+// Len returns the number of unread bytes.
+func (b *PooledBuffer) Len() int { return b.n - b.off }
+
+// Cap returns the size of the current segment.
+func (b *PooledBuffer) Cap() int { return len(b.buf) }
+
+// Bytes returns the unread portion of the buffer.
+// The slice aliases pooled memory and is valid until the next modification.
+func (b *PooledBuffer) Bytes() []byte { return b.buf[b.off:b.n] }
+
+// String returns the unread portion of the buffer as a string.
+func (b *PooledBuffer) String() string { return string(b.buf[b.off:b.n]) }
+
+// Grow ensures space for another n bytes, moving to a larger segment if
+// necessary. Panics if n is negative.
+func (b *PooledBuffer) Grow(n int) {
+	if n < 0 {
+		panic("iobuf.PooledBuffer.Grow: negative count")
+	}
+	b.grow(n)
+}
+
+// Write appends p to the buffer. The returned error is always nil.
+func (b *PooledBuffer) Write(p []byte) (n int, err error) {
+	b.grow(len(p))
+	b.n += copy(b.buf[b.n:], p)
+	return len(p), nil
+}
+
+// WriteString appends s to the buffer. The returned error is always nil.
+func (b *PooledBuffer) WriteString(s string) (n int, err error) {
+	b.grow(len(s))
+	b.n += copy(b.buf[b.n:], s)
+	return len(s), nil
+}
+
+// WriteByte appends c to the buffer. The returned error is always nil.
+func (b *PooledBuffer) WriteByte(c byte) error {
+	b.grow(1)
+	b.buf[b.n] = c
+	b.n++
+	return nil
+}
+
+// Read reads up to len(p) unread bytes into p.
+// It returns io.EOF when the buffer is empty and len(p) > 0.
+func (b *PooledBuffer) Read(p []byte) (n int, err error) {
+	if b.off == b.n {
+		b.off, b.n = 0, 0
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	n = copy(p, b.buf[b.off:b.n])
+	b.off += n
+	return n, nil
+}
+
+// Next consumes and returns the next n unread bytes, or all of them if
+// fewer than n remain. The slice is valid until the next modification.
+func (b *PooledBuffer) Next(n int) []byte {
+	n = max(0, min(n, b.n-b.off))
+	p := b.buf[b.off : b.off+n]
+	b.off += n
+	return p
+}
+
+// Truncate discards all but the first n unread bytes.
+// Panics if n is negative or greater than Len.
+func (b *PooledBuffer) Truncate(n int) {
+	if n == 0 {
+		b.Reset()
+		return
+	}
+	if n < 0 || n > b.Len() {
+		panic("iobuf.PooledBuffer.Truncate: out of range")
+	}
+	b.n = b.off + n
+}
+
+// Reset empties the buffer but keeps the current segment for reuse.
+func (b *PooledBuffer) Reset() {
+	b.off, b.n = 0, 0
+}
+
+// Close empties the buffer and returns the current segment to its pool.
+// The buffer remains usable and acquires a new segment on the next write.
+func (b *PooledBuffer) Close() error {
+	b.Reset()
+	return b.release()
+}
+
+// grow makes room for another need bytes at b.n.
+func (b *PooledBuffer) grow(need int) {
+	if b.n+need <= len(b.buf) {
+		return
+	}
+	size := b.Len() + need
+	if size <= len(b.buf) {
+		b.n = copy(b.buf, b.buf[b.off:b.n])
+		b.off = 0
+		return
+	}
+	pool, indirect, seg := b.acquire(size)
+	if seg == nil {
+		seg = make([]byte, max(size, 2*len(b.buf)))
+	}
+	n := copy(seg, b.buf[b.off:b.n])
+	_ = b.release()
+	b.pool, b.indirect, b.buf = pool, indirect, seg
+	b.off, b.n = 0, n
+}
+
+// acquire gets a segment of at least size bytes from the first pool that
+// can provide one. It returns a nil segment if no pool can.
+func (b *PooledBuffer) acquire(size int) (BufferPool, int, []byte) {
+	for i, pool := range b.pools {
+		if b.sizes[i] != 0 && b.sizes[i] < size {
+			continue
+		}
+		indirect, err := pool.Get()
+		if err != nil {
+			continue
+		}
+		seg := pool.Bytes(indirect)
+		b.sizes[i] = len(seg)
+		if len(seg) >= size {
+			return pool, indirect, seg
+		}
+		_ = pool.Put(indirect)
+	}
+	return nil, 0, nil
+}
+
+// release returns the current segment to its pool, if any.
+func (b *PooledBuffer) release() error {
+	if b.pool != nil {
+		if err := b.pool.Put(b.indirect); err != nil {
+			return err
+		}
+	}
+	b.pool, b.buf = nil, nil
+	return nil
+}
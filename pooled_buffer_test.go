@@ -0,0 +1,151 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func newTieredPools(t *testing.T) (*iobuf.MicroBufferBoundedPool, *iobuf.SmallBufferBoundedPool) {
+	t.Helper()
+	micro := iobuf.NewMicroBufferPool(2)
+	micro.Fill(iobuf.NewMicroBuffer)
+	micro.SetNonblock(true)
+	small := iobuf.NewSmallBufferPool(2)
+	small.Fill(iobuf.NewSmallBuffer)
+	small.SetNonblock(true)
+	return micro, small
+}
+
+func TestPooledBuffer_BytesBufferMethods(t *testing.T) {
+	micro, small := newTieredPools(t)
+	b := iobuf.NewPooledBuffer(micro, small)
+	defer b.Close()
+
+	_, _ = b.WriteString("hello")
+	_ = b.WriteByte(' ')
+	_, _ = b.Write([]byte("world"))
+	if b.Len() != 11 || b.String() != "hello world" {
+		t.Fatalf("Len()=%d String()=%q", b.Len(), b.String())
+	}
+	if b.Cap() != iobuf.BufferSizeMicro {
+		t.Errorf("Cap() = %d, want Micro segment", b.Cap())
+	}
+
+	if got := b.Next(6); string(got) != "hello " {
+		t.Errorf("Next(6) = %q", got)
+	}
+	b.Truncate(3)
+	if got := string(b.Bytes()); got != "wor" {
+		t.Errorf("Bytes() after Truncate = %q, want %q", got, "wor")
+	}
+
+	p := make([]byte, 8)
+	n, err := b.Read(p)
+	if n != 3 || err != nil || string(p[:n]) != "wor" {
+		t.Errorf("Read() = %d, %v, %q", n, err, p[:n])
+	}
+	if _, err := b.Read(p); err != io.EOF {
+		t.Errorf("Read() on empty buffer: %v, want io.EOF", err)
+	}
+	if n, err := b.Read(nil); n != 0 || err != nil {
+		t.Errorf("Read(nil) = %d, %v", n, err)
+	}
+}
+
+func TestPooledBuffer_GrowsAcrossTiers(t *testing.T) {
+	micro, small := newTieredPools(t)
+	b := iobuf.NewPooledBuffer(micro, small)
+
+	data := bytes.Repeat([]byte("x"), iobuf.BufferSizeMicro+1)
+	_, _ = b.Write(data)
+	if b.Cap() != iobuf.BufferSizeSmall {
+		t.Fatalf("Cap() = %d, want Small segment", b.Cap())
+	}
+	if !bytes.Equal(b.Bytes(), data) {
+		t.Fatal("data lost while growing")
+	}
+
+	// The Micro segment went back to its pool.
+	for range micro.Cap() {
+		idx, err := micro.Get()
+		if err != nil {
+			t.Fatalf("Micro segment not returned: %v", err)
+		}
+		defer micro.Put(idx)
+	}
+
+	// Beyond the largest pool the buffer falls back to the heap.
+	_, _ = b.Write(bytes.Repeat([]byte("y"), iobuf.BufferSizeSmall))
+	if b.Len() != iobuf.BufferSizeMicro+1+iobuf.BufferSizeSmall {
+		t.Fatalf("Len() = %d", b.Len())
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	for range small.Cap() {
+		idx, err := small.Get()
+		if err != nil {
+			t.Fatalf("Small segment not returned: %v", err)
+		}
+		defer small.Put(idx)
+	}
+}
+
+func TestPooledBuffer_CompactsBeforeGrowing(t *testing.T) {
+	micro, _ := newTieredPools(t)
+	b := iobuf.NewPooledBuffer(micro)
+	defer b.Close()
+
+	_, _ = b.Write(bytes.Repeat([]byte("a"), iobuf.BufferSizeMicro-10))
+	b.Next(iobuf.BufferSizeMicro - 20)
+	_, _ = b.Write(bytes.Repeat([]byte("b"), 50))
+	if b.Cap() != iobuf.BufferSizeMicro {
+		t.Errorf("Cap() = %d, want compaction within the Micro segment", b.Cap())
+	}
+	want := strings.Repeat("a", 10) + strings.Repeat("b", 50)
+	if b.String() != want {
+		t.Errorf("String() = %q, want %q", b.String(), want)
+	}
+}
+
+func TestPooledBuffer_ZeroValueAndGrow(t *testing.T) {
+	var b iobuf.PooledBuffer
+	b.Grow(64)
+	if b.Cap() < 64 {
+		t.Errorf("Cap() after Grow(64) = %d", b.Cap())
+	}
+	_, _ = b.WriteString("heap only")
+	if b.String() != "heap only" {
+		t.Errorf("String() = %q", b.String())
+	}
+	b.Reset()
+	if b.Len() != 0 {
+		t.Errorf("Len() after Reset = %d", b.Len())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Grow(-1) should panic")
+		}
+	}()
+	b.Grow(-1)
+}
+
+func TestPooledBuffer_TruncatePanics(t *testing.T) {
+	var b iobuf.PooledBuffer
+	_, _ = b.WriteString("abc")
+	defer func() {
+		if recover() == nil {
+			t.Error("Truncate(4) should panic")
+		}
+	}()
+	b.Truncate(4)
+}
@@ -0,0 +1,123 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// RcBuffer is a reference-counted, copy-on-write handle to a pooled buffer.
+//
+// Several readers can share one pooled buffer, e.g., a hot response payload
+// cached directly in pool memory. Share hands out another reference, and
+// the buffer returns to its pool when the last reference is released. A
+// holder that needs to modify the data calls Mutable: if it is the only
+// holder the buffer is modified in place, otherwise it transparently gets
+// a private copy from the same pool first.
+//
+// Each RcBuffer value is one reference and must be released exactly once.
+// Bytes views obtained from a shared buffer must be treated as read-only.
+// See SetReclaimLeaks for recovering buffers whose references are dropped
+// without being released.
+type RcBuffer struct {
+	rc *rcBuffer
+}
+
+// rcBuffer is the shared state behind RcBuffer references.
+type rcBuffer struct {
+	pool     BufferPool
+	indirect int
+	refs     atomic.Int32
+	cleanup  runtime.Cleanup // Leak reclamation, if the pool reclaims leaks
+}
+
+// NewRcBuffer returns the first reference to the buffer at the given
+// indirect index of pool. Ownership of the index passes to the RcBuffer.
+func NewRcBuffer(pool BufferPool, indirect int) RcBuffer {
+	rc := &rcBuffer{pool: pool, indirect: indirect}
+	rc.refs.Store(1)
+	if reclaimsLeaks(pool) {
+		rc.cleanup = runtime.AddCleanup(rc, reclaimLeak, leakedBuffer{pool: pool, indirect: indirect})
+	}
+	return RcBuffer{rc: rc}
+}
+
+// Share returns a new reference to the same buffer.
+// Panics if b has been released.
+func (b RcBuffer) Share() RcBuffer {
+	if b.rc == nil {
+		panic("iobuf: use of released RcBuffer")
+	}
+	b.rc.refs.Add(1)
+	return RcBuffer{rc: b.rc}
+}
+
+// Refs returns the current number of references to the buffer,
+// or 0 if b has been released.
+func (b RcBuffer) Refs() int {
+	if b.rc == nil {
+		return 0
+	}
+	return int(b.rc.refs.Load())
+}
+
+// Indirect returns the pool index of the referenced buffer.
+func (b RcBuffer) Indirect() int {
+	if b.rc == nil {
+		panic("iobuf: use of released RcBuffer")
+	}
+	return b.rc.indirect
+}
+
+// Bytes returns a read-only view of the buffer.
+// Panics if b has been released.
+func (b RcBuffer) Bytes() []byte {
+	if b.rc == nil {
+		panic("iobuf: use of released RcBuffer")
+	}
+	return b.rc.pool.Bytes(b.rc.indirect)
+}
+
+// Mutable returns a writable view of the buffer, copying it first if it is
+// shared with other references. The copy comes from the same pool, so
+// Mutable returns iox.ErrWouldBlock if the pool is non-blocking and empty;
+// b is left unchanged in that case.
+func (b *RcBuffer) Mutable() ([]byte, error) {
+	if b.rc == nil {
+		panic("iobuf: use of released RcBuffer")
+	}
+	if b.rc.refs.Load() == 1 {
+		return b.rc.pool.Bytes(b.rc.indirect), nil
+	}
+	pool := b.rc.pool
+	indirect, err := pool.Get()
+	if err != nil {
+		return nil, err
+	}
+	buf := pool.Bytes(indirect)
+	copy(buf, pool.Bytes(b.rc.indirect))
+	if err := b.Release(); err != nil {
+		_ = pool.Put(indirect)
+		return nil, err
+	}
+	*b = NewRcBuffer(pool, indirect)
+	return buf, nil
+}
+
+// Release drops this reference. The buffer returns to its pool when the
+// last reference is released. Releasing a released RcBuffer is a no-op.
+func (b *RcBuffer) Release() error {
+	if b.rc == nil {
+		return nil
+	}
+	rc := b.rc
+	b.rc = nil
+	if rc.refs.Add(-1) == 0 {
+		rc.cleanup.Stop()
+		return rc.pool.Put(rc.indirect)
+	}
+	return nil
+}
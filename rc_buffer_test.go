@@ -0,0 +1,132 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"sync"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+	"code.hybscloud.com/iox"
+)
+
+func TestRcBuffer_ShareAndRelease(t *testing.T) {
+	pool := iobuf.NewSmallBufferPool(1)
+	pool.Fill(iobuf.NewSmallBuffer)
+	pool.SetNonblock(true)
+
+	idx, _ := pool.Get()
+	a := iobuf.NewRcBuffer(pool, idx)
+	copy(a.Bytes(), "cached payload")
+	b := a.Share()
+	if a.Refs() != 2 || b.Indirect() != idx {
+		t.Fatalf("Refs()=%d Indirect()=%d", a.Refs(), b.Indirect())
+	}
+	if string(b.Bytes()[:14]) != "cached payload" {
+		t.Errorf("shared view = %q", b.Bytes()[:14])
+	}
+
+	_ = a.Release()
+	if a.Refs() != 0 || b.Refs() != 1 {
+		t.Errorf("after first Release: a=%d b=%d", a.Refs(), b.Refs())
+	}
+	if _, err := pool.Get(); err != iox.ErrWouldBlock {
+		t.Fatal("buffer returned to pool while still referenced")
+	}
+	_ = b.Release()
+	_ = b.Release() // no-op
+	if _, err := pool.Get(); err != nil {
+		t.Fatalf("buffer not returned after last Release: %v", err)
+	}
+}
+
+func TestRcBuffer_CopyOnWrite(t *testing.T) {
+	pool := iobuf.NewMicroBufferPool(2)
+	pool.Fill(iobuf.NewMicroBuffer)
+	pool.SetNonblock(true)
+
+	idx, _ := pool.Get()
+	reader := iobuf.NewRcBuffer(pool, idx)
+	copy(reader.Bytes(), "original")
+	writer := reader.Share()
+
+	buf, err := writer.Mutable()
+	if err != nil {
+		t.Fatalf("Mutable() failed: %v", err)
+	}
+	if writer.Indirect() == reader.Indirect() {
+		t.Fatal("Mutable() on shared buffer must copy")
+	}
+	if string(buf[:8]) != "original" {
+		t.Errorf("private copy = %q", buf[:8])
+	}
+	copy(buf, "modified")
+	if string(reader.Bytes()[:8]) != "original" {
+		t.Error("write through Mutable leaked into shared buffer")
+	}
+	if reader.Refs() != 1 || writer.Refs() != 1 {
+		t.Errorf("Refs after COW: reader=%d writer=%d", reader.Refs(), writer.Refs())
+	}
+
+	// Sole owner mutates in place.
+	before := reader.Indirect()
+	if _, err := reader.Mutable(); err != nil || reader.Indirect() != before {
+		t.Errorf("sole-owner Mutable() moved buffer or failed: %v", err)
+	}
+	_ = reader.Release()
+	_ = writer.Release()
+}
+
+func TestRcBuffer_MutableWouldBlock(t *testing.T) {
+	pool := iobuf.NewPicoBufferPool(1)
+	pool.Fill(iobuf.NewPicoBuffer)
+	pool.SetNonblock(true)
+
+	idx, _ := pool.Get()
+	a := iobuf.NewRcBuffer(pool, idx)
+	b := a.Share()
+	if _, err := b.Mutable(); err != iox.ErrWouldBlock {
+		t.Fatalf("Mutable() with exhausted pool: %v, want ErrWouldBlock", err)
+	}
+	if b.Refs() != 2 {
+		t.Errorf("failed Mutable() must leave the reference intact, Refs()=%d", b.Refs())
+	}
+}
+
+func TestRcBuffer_ConcurrentShare(t *testing.T) {
+	pool := iobuf.NewPicoBufferPool(1)
+	pool.Fill(iobuf.NewPicoBuffer)
+	pool.SetNonblock(true)
+
+	idx, _ := pool.Get()
+	root := iobuf.NewRcBuffer(pool, idx)
+	var wg sync.WaitGroup
+	for range 8 {
+		ref := root.Share()
+		wg.Go(func() {
+			_ = ref.Bytes()[0]
+			_ = ref.Release()
+		})
+	}
+	wg.Wait()
+	_ = root.Release()
+	if _, err := pool.Get(); err != nil {
+		t.Fatalf("buffer not returned: %v", err)
+	}
+}
+
+func TestRcBuffer_UseAfterRelease(t *testing.T) {
+	pool := iobuf.NewPicoBufferPool(1)
+	pool.Fill(iobuf.NewPicoBuffer)
+	idx, _ := pool.Get()
+	a := iobuf.NewRcBuffer(pool, idx)
+	_ = a.Release()
+	defer func() {
+		if recover() == nil {
+			t.Error("Share() after Release should panic")
+		}
+	}()
+	a.Share()
+}
@@ -0,0 +1,95 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// reclaimedLeaks counts the buffers returned by leak reclamation in every
+// pool.
+var reclaimedLeaks atomic.Uint64
+
+// ReclaimedLeaks returns the number of leaked buffers the garbage collector
+// has returned to their pools, across all pools. A growing count means
+// Leases or RcBuffers are dropped without being closed or released.
+func ReclaimedLeaks() uint64 { return reclaimedLeaks.Load() }
+
+// SetReclaimLeaks enables or disables returning leaked buffers to the pool.
+//
+// With reclamation enabled, a Lease or RcBuffer created for the pool that
+// becomes unreachable without being closed or released has its buffer put
+// back by a runtime cleanup, and the leak is counted in ReclaimedLeaks and
+// in the pool's Stats. A forgotten Close then shows up as a metric instead
+// of slowly draining the pool until Get blocks forever.
+//
+// Reclamation is a safety net, not a substitute for releasing buffers: it
+// runs at the garbage collector's pace, costs an allocation and a cleanup
+// registration per handle, and cannot help if a view from Bytes outlives
+// its handle. SetReclaimLeaks must be called before the pool is shared
+// between goroutines.
+func (pool *BoundedPool[T]) SetReclaimLeaks(enabled bool) {
+	if enabled {
+		pool.debug |= poolReclaimLeaks
+	} else {
+		pool.debug &^= poolReclaimLeaks
+	}
+}
+
+// reclaimsLeaks reports whether leak reclamation is enabled.
+func (pool *BoundedPool[T]) reclaimsLeaks() bool {
+	return pool.debug&poolReclaimLeaks != 0
+}
+
+// countReclaimed records a buffer returned by leak reclamation.
+func (pool *BoundedPool[T]) countReclaimed() {
+	if pool.debug&poolDebugStats != 0 {
+		pool.stats.reclaimed.Add(1)
+	}
+}
+
+// leakReclaimer is implemented by pools supporting SetReclaimLeaks.
+type leakReclaimer interface {
+	reclaimsLeaks() bool
+	countReclaimed()
+}
+
+// leakedBuffer identifies the buffer a cleanup returns to its pool. It must
+// not reference the object the cleanup is attached to.
+type leakedBuffer struct {
+	pool     BufferPool
+	indirect int
+}
+
+// leakGuard is the heap object whose collection signals a leaked Lease.
+// Holding the cleanup handle also keeps it out of the tiny allocator, whose
+// shared blocks would delay the cleanup.
+type leakGuard struct {
+	cleanup runtime.Cleanup
+}
+
+// reclaimsLeaks reports whether pool has leak reclamation enabled.
+func reclaimsLeaks(pool BufferPool) bool {
+	r, ok := pool.(leakReclaimer)
+	return ok && r.reclaimsLeaks()
+}
+
+// newLeakGuard returns a guard that reclaims the buffer at indirect once
+// it is collected, unless stopped first.
+func newLeakGuard(pool BufferPool, indirect int) *leakGuard {
+	g := &leakGuard{}
+	g.cleanup = runtime.AddCleanup(g, reclaimLeak, leakedBuffer{pool: pool, indirect: indirect})
+	return g
+}
+
+// reclaimLeak returns a leaked buffer to its pool.
+func reclaimLeak(b leakedBuffer) {
+	reclaimedLeaks.Add(1)
+	if r, ok := b.pool.(leakReclaimer); ok {
+		r.countReclaimed()
+	}
+	_ = b.pool.Put(b.indirect)
+}
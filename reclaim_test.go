@@ -0,0 +1,93 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"code.hybscloud.com/iobuf"
+)
+
+// waitReclaimed runs the garbage collector until pool reports want
+// reclaimed leaks, failing the test after a deadline.
+func waitReclaimed(t *testing.T, pool *iobuf.NanoBufferBoundedPool, want uint64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for pool.Stats().Reclaimed < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Reclaimed = %d, want %d", pool.Stats().Reclaimed, want)
+		}
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// leakLease acquires a lease and drops it without closing it.
+//
+//go:noinline
+func leakLease(t *testing.T, pool *iobuf.NanoBufferBoundedPool) {
+	if _, err := pool.Acquire(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// leakRcBuffer creates a shared RcBuffer and drops both references.
+//
+//go:noinline
+func leakRcBuffer(t *testing.T, pool *iobuf.NanoBufferBoundedPool) {
+	idx, err := pool.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := iobuf.NewRcBuffer(pool, idx)
+	_ = b.Share()
+}
+
+func TestBoundedPool_SetReclaimLeaks(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(2)
+	pool.Fill(iobuf.NewNanoBuffer)
+	pool.SetNonblock(true)
+	pool.SetStats(true)
+	pool.SetReclaimLeaks(true)
+	before := iobuf.ReclaimedLeaks()
+
+	leakLease(t, pool)
+	leakRcBuffer(t, pool)
+	waitReclaimed(t, pool, 2)
+	if s := pool.Stats(); s.Available != 2 {
+		t.Errorf("Available = %d after reclaiming, want 2", s.Available)
+	}
+	if n := iobuf.ReclaimedLeaks() - before; n < 2 {
+		t.Errorf("ReclaimedLeaks() advanced by %d, want at least 2", n)
+	}
+}
+
+func TestBoundedPool_SetReclaimLeaksReleased(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(2)
+	pool.Fill(iobuf.NewNanoBuffer)
+	pool.SetNonblock(true)
+	pool.SetStats(true)
+	pool.SetReclaimLeaks(true)
+
+	lease, err := pool.Acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = lease.Close()
+	idx, _ := pool.Get()
+	rc := iobuf.NewRcBuffer(pool, idx)
+	_ = rc.Release()
+
+	// Released handles must not be reclaimed a second time.
+	for range 3 {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	if s := pool.Stats(); s.Reclaimed != 0 || s.Available != 2 {
+		t.Errorf("Stats() = %+v, want no reclaims and 2 available", s)
+	}
+}
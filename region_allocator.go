@@ -0,0 +1,122 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"slices"
+	"sync"
+	"unsafe"
+)
+
+// RegionAllocator carves page-aligned chunks out of one caller-owned
+// region, typically memory already registered with io_uring.
+//
+// Registering a single large region and sub-allocating from it is far
+// cheaper than registering hundreds of separate buffers. Every chunk is
+// reported both as a slice and as its byte offset from the start of the
+// region, which is what registered-buffer and registered-region operations
+// address. Chunks are whole pages; free space is kept as a sorted list of
+// extents, allocated first fit and coalesced with its neighbors on Free.
+//
+// The allocator never maps or unmaps memory itself: the region stays owned
+// by the caller and must outlive every chunk.
+//
+// A RegionAllocator is safe for concurrent use.
+type RegionAllocator struct {
+	mu    sync.Mutex
+	mem   []byte
+	free  []regionExtent // Free extents sorted by offset
+	sizes map[int]int    // Allocated chunk sizes by offset
+	used  int
+}
+
+// regionExtent is a run of free bytes within the region.
+type regionExtent struct {
+	off, n int
+}
+
+// NewRegionAllocator returns an allocator over mem, which must start on a
+// page boundary. Any trailing partial page is left unused.
+// Panics if mem is not page-aligned or shorter than one page.
+func NewRegionAllocator(mem []byte) *RegionAllocator {
+	ps := int(PageSize)
+	if len(mem) < ps || uintptr(unsafe.Pointer(unsafe.SliceData(mem)))%PageSize != 0 {
+		panic("iobuf.NewRegionAllocator: region must be page-aligned and at least one page")
+	}
+	mem = mem[: len(mem)/ps*ps : len(mem)/ps*ps]
+	return &RegionAllocator{
+		mem:   mem,
+		free:  []regionExtent{{0, len(mem)}},
+		sizes: make(map[int]int),
+	}
+}
+
+// Bytes returns the usable part of the region.
+func (a *RegionAllocator) Bytes() []byte { return a.mem }
+
+// Used returns the number of bytes currently allocated, counting whole
+// pages.
+func (a *RegionAllocator) Used() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.used
+}
+
+// Alloc returns a chunk of at least n bytes and its offset from the start
+// of the region. The slice has length n and the capacity of the whole
+// chunk, a multiple of PageSize. Returns nil and -1 if no free extent is
+// large enough. Panics if n < 1.
+func (a *RegionAllocator) Alloc(n int) (b []byte, off int) {
+	if n < 1 {
+		panic("iobuf.RegionAllocator.Alloc: size must be positive")
+	}
+	ps := int(PageSize)
+	if n > len(a.mem) {
+		return nil, -1
+	}
+	size := (n + ps - 1) / ps * ps
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i := range a.free {
+		e := &a.free[i]
+		if e.n < size {
+			continue
+		}
+		off = e.off
+		e.off += size
+		e.n -= size
+		if e.n == 0 {
+			a.free = slices.Delete(a.free, i, i+1)
+		}
+		a.sizes[off] = size
+		a.used += size
+		return a.mem[off : off+n : off+size], off
+	}
+	return nil, -1
+}
+
+// Free returns the chunk at offset off to the allocator.
+// Panics if off is not the offset of an allocated chunk.
+func (a *RegionAllocator) Free(off int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	size, ok := a.sizes[off]
+	if !ok {
+		panic("iobuf.RegionAllocator.Free: double free or not a chunk offset")
+	}
+	delete(a.sizes, off)
+	a.used -= size
+	i, _ := slices.BinarySearchFunc(a.free, off, func(e regionExtent, off int) int { return e.off - off })
+	if i > 0 && a.free[i-1].off+a.free[i-1].n == off {
+		i--
+		a.free[i].n += size
+	} else {
+		a.free = slices.Insert(a.free, i, regionExtent{off, size})
+	}
+	if i+1 < len(a.free) && a.free[i].off+a.free[i].n == a.free[i+1].off {
+		a.free[i].n += a.free[i+1].n
+		a.free = slices.Delete(a.free, i+1, i+2)
+	}
+}
@@ -0,0 +1,70 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestRegionAllocator_AllocFree(t *testing.T) {
+	ps := int(iobuf.PageSize)
+	mem := iobuf.AlignedMem(8*ps, iobuf.PageSize)
+	a := iobuf.NewRegionAllocator(mem)
+
+	b1, off1 := a.Alloc(1)
+	b2, off2 := a.Alloc(ps + 1)
+	b3, off3 := a.Alloc(ps)
+	if off1 != 0 || off2 != ps || off3 != 3*ps {
+		t.Fatalf("offsets = %d, %d, %d, want 0, %d, %d", off1, off2, off3, ps, 3*ps)
+	}
+	if len(b2) != ps+1 || cap(b2) != 2*ps {
+		t.Errorf("Alloc(%d) len=%d cap=%d, want cap %d", ps+1, len(b2), cap(b2), 2*ps)
+	}
+	if unsafe.SliceData(b3) != &mem[off3] || len(b1) != 1 {
+		t.Errorf("chunk slice does not match its offset")
+	}
+	if a.Used() != 4*ps {
+		t.Errorf("Used() = %d, want %d", a.Used(), 4*ps)
+	}
+	if b, off := a.Alloc(5 * ps); b != nil || off != -1 {
+		t.Errorf("Alloc beyond free space returned off=%d", off)
+	}
+
+	// Freeing the middle chunk and its neighbors coalesces the space.
+	a.Free(off2)
+	a.Free(off1)
+	a.Free(off3)
+	if a.Used() != 0 {
+		t.Errorf("Used() after Free = %d, want 0", a.Used())
+	}
+	if b, off := a.Alloc(8 * ps); b == nil || off != 0 {
+		t.Errorf("Alloc(whole region) after Free = off %d, want 0", off)
+	}
+}
+
+func TestRegionAllocator_Panics(t *testing.T) {
+	ps := int(iobuf.PageSize)
+	mem := iobuf.AlignedMem(2*ps, iobuf.PageSize)
+	a := iobuf.NewRegionAllocator(mem)
+	_, off := a.Alloc(1)
+	a.Free(off)
+	for name, f := range map[string]func(){
+		"unaligned":   func() { iobuf.NewRegionAllocator(mem[1:]) },
+		"zero size":   func() { a.Alloc(0) },
+		"double free": func() { a.Free(off) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s should panic", name)
+				}
+			}()
+			f()
+		})
+	}
+}
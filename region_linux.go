@@ -0,0 +1,91 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// mremapMayMove is MREMAP_MAYMOVE from linux/mman.h.
+const mremapMayMove = 1
+
+// mapRegion maps size bytes of anonymous memory outside the Go heap.
+func mapRegion(size int, flags MapFlags) ([]byte, error) {
+	mflags := syscall.MAP_PRIVATE | syscall.MAP_ANONYMOUS
+	if flags&MapPopulate != 0 {
+		mflags |= syscall.MAP_POPULATE
+	}
+	if flags&MapLocked != 0 {
+		mflags |= syscall.MAP_LOCKED
+	}
+	if flags&MapNoReserve != 0 {
+		mflags |= syscall.MAP_NORESERVE
+	}
+	return mmapAnon(size, mflags)
+}
+
+// mmapAnon maps size bytes of readable and writable anonymous memory with
+// the given mmap flags.
+func mmapAnon(size int, mflags int) ([]byte, error) {
+	return mmap(-1, 0, size, syscall.PROT_READ|syscall.PROT_WRITE, mflags)
+}
+
+// mmap maps size bytes of fd at off, or anonymous memory if fd is -1.
+//
+// The mapping is made with syscall.Mmap, which issues the mmap call each
+// architecture expects, e.g., with an argument block on s390x. Its
+// bookkeeping is bypassed afterwards: regions are resized and released
+// with raw mremap and munmap, since a region moved by remapRegion is
+// unknown to syscall.Munmap. The stale entry syscall.Mmap keeps is only
+// consulted by syscall.Munmap, which is never called on a region.
+func mmap(fd int, off int64, size int, prot, flags int) ([]byte, error) {
+	mem, err := syscall.Mmap(fd, off, size, prot, flags)
+	if err != nil {
+		return nil, os.NewSyscallError("mmap", err)
+	}
+	return mem, nil
+}
+
+// remapRegion resizes a region returned by mapRegion in place or by moving
+// its pages (mremap), without copying. The old slice must not be used
+// afterwards.
+func remapRegion(mem []byte, size int) ([]byte, error) {
+	addr, _, errno := syscall.Syscall6(syscall.SYS_MREMAP, uintptr(unsafe.Pointer(unsafe.SliceData(mem))),
+		uintptr(cap(mem)), uintptr(size), mremapMayMove, 0, 0)
+	if errno != 0 {
+		return nil, os.NewSyscallError("mremap", errno)
+	}
+	return regionSlice(addr, size), nil
+}
+
+// unmapRegion releases memory returned by mapRegion or remapRegion.
+func unmapRegion(mem []byte) error {
+	if mem == nil {
+		return nil
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_MUNMAP, uintptr(unsafe.Pointer(unsafe.SliceData(mem))), uintptr(cap(mem)), 0)
+	if errno != 0 {
+		return os.NewSyscallError("munmap", errno)
+	}
+	return nil
+}
+
+// regionSlice returns the mapping at addr as a byte slice.
+func regionSlice(addr uintptr, size int) []byte {
+	return unsafe.Slice(*(**byte)(unsafe.Pointer(&addr)), size)
+}
+
+// discardPages drops the whole pages inside mem from the resident set
+// (MADV_DONTNEED). The pages read as zero when next touched. It returns
+// the number of bytes discarded.
+func discardPages(mem []byte) int {
+	mem = pageAligned(mem)
+	if len(mem) == 0 || syscall.Madvise(mem, syscall.MADV_DONTNEED) != nil {
+		return 0
+	}
+	return len(mem)
+}
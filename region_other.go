@@ -0,0 +1,31 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package iobuf
+
+// mapRegion allocates size bytes of page-aligned memory. Without mmap
+// support the region comes from the Go heap and flags are ignored.
+func mapRegion(size int, flags MapFlags) ([]byte, error) {
+	return AlignedMem(size, PageSize), nil
+}
+
+// remapRegion resizes a region returned by mapRegion. Without mremap the
+// contents are copied into a new region.
+func remapRegion(mem []byte, size int) ([]byte, error) {
+	m, err := mapRegion(size, 0)
+	if err != nil {
+		return nil, err
+	}
+	copy(m, mem)
+	return m, unmapRegion(mem)
+}
+
+// unmapRegion releases memory returned by mapRegion. Heap regions are
+// reclaimed by the garbage collector.
+func unmapRegion(mem []byte) error { return nil }
+
+// discardPages is a no-op without madvise support.
+func discardPages(mem []byte) int { return 0 }
@@ -0,0 +1,81 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// RegisterBuffersSparse registers a table of n empty buffer slots with the
+// io_uring instance ringFd (IORING_RSRC_REGISTER_SPARSE, Linux 5.19+).
+// Slots are then filled and replaced at runtime with UpdateBuffers without
+// tearing down the table. Panics if n < 1.
+func RegisterBuffersSparse(ringFd int, n int) error {
+	if n < 1 {
+		panic("iobuf.RegisterBuffersSparse: table size must be positive")
+	}
+	reg := ioUringRsrcRegister{nr: uint32(n), flags: ioringRsrcRegisterSparse}
+	return ioUringRegister(ringFd, ioringRegisterBuffers2, unsafe.Pointer(&reg), uint32(unsafe.Sizeof(reg)))
+}
+
+// UpdateBuffers registers the buffers described by vec in consecutive
+// slots of the ring's buffer table starting at offset
+// (IORING_REGISTER_BUFFERS_UPDATE), replacing whatever the slots held. A
+// zero IoVec clears its slot. The kernel pins the memory, so vec itself
+// need not outlive the call.
+func UpdateBuffers(ringFd int, offset int, vec []IoVec) error {
+	if len(vec) == 0 {
+		return nil
+	}
+	upd := ioUringRsrcUpdate2{
+		offset: uint32(offset),
+		data:   uint64(uintptr(unsafe.Pointer(unsafe.SliceData(vec)))),
+		nr:     uint32(len(vec)),
+	}
+	err := ioUringRegister(ringFd, ioringRegisterBuffersUpdate, unsafe.Pointer(&upd), uint32(unsafe.Sizeof(upd)))
+	runtime.KeepAlive(vec)
+	return err
+}
+
+// UpdatePoolBuffers registers every buffer of pool in the ring's buffer
+// table starting at slot offset, so the buffer at indirect index i is
+// registered buffer offset+i. Panics if the pool is not filled.
+func UpdatePoolBuffers(ringFd int, pool *RegisterBufferPool, offset int) error {
+	return UpdateBuffers(ringFd, offset, RegisterIoVecs(pool))
+}
+
+// UnregisterBuffers removes the ring's whole buffer table.
+func UnregisterBuffers(ringFd int) error {
+	return ioUringRegister(ringFd, ioringUnregisterBuffers, nil, 0)
+}
+
+// CloneBuffers installs the whole registered buffer table of ring srcFd
+// into ring dstFd (IORING_REGISTER_CLONE_BUFFERS, Linux 6.12+). Both rings
+// then refer to the same pinned pages, so per-core rings of a sharded
+// server can share one pool-backed registration instead of pinning the
+// memory once per ring. The destination must not have a buffer table.
+func CloneBuffers(dstFd, srcFd int) error {
+	arg := ioUringCloneBuffers{srcFd: uint32(srcFd)}
+	return ioUringRegister(dstFd, ioringRegisterCloneBuffers, unsafe.Pointer(&arg), 1)
+}
+
+// CloneBufferRange clones n slots of the buffer table of ring srcFd,
+// starting at srcOff, into the table of ring dstFd at dstOff (Linux
+// 6.13+). With replace set, an existing destination table is replaced;
+// otherwise the destination must not have one. n == 0 clones every slot
+// from srcOff on.
+func CloneBufferRange(dstFd, srcFd int, srcOff, dstOff, n int, replace bool) error {
+	arg := ioUringCloneBuffers{
+		srcFd:  uint32(srcFd),
+		srcOff: uint32(srcOff),
+		dstOff: uint32(dstOff),
+		nr:     uint32(n),
+	}
+	if replace {
+		arg.flags = ioringRegisterDstReplace
+	}
+	return ioUringRegister(dstFd, ioringRegisterCloneBuffers, unsafe.Pointer(&arg), 1)
+}
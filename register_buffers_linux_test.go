@@ -0,0 +1,68 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && !mips64 && !mips64le
+
+package iobuf_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestRegisterBuffersSparse_Update(t *testing.T) {
+	ring := newTestRing(t)
+	if err := iobuf.RegisterBuffersSparse(ring, 16); err != nil {
+		if errors.Is(err, syscall.EINVAL) {
+			t.Skipf("sparse buffer tables not supported: %v", err)
+		}
+		t.Fatalf("RegisterBuffersSparse() failed: %v", err)
+	}
+
+	pool := iobuf.NewRegisterBufferPool(4)
+	pool.Fill(func() iobuf.RegisterBuffer { return iobuf.RegisterBuffer{} })
+	if err := iobuf.UpdatePoolBuffers(ring, pool, 8); err != nil {
+		t.Fatalf("UpdatePoolBuffers() failed: %v", err)
+	}
+
+	// Replace one slot and clear another at runtime.
+	extra := iobuf.AlignedMem(4096, iobuf.PageSize)
+	if err := iobuf.UpdateBuffers(ring, 0, []iobuf.IoVec{{Base: &extra[0], Len: 4096}, {}}); err != nil {
+		t.Fatalf("UpdateBuffers() failed: %v", err)
+	}
+	if err := iobuf.UpdateBuffers(ring, 16, []iobuf.IoVec{{Base: &extra[0], Len: 4096}}); err == nil {
+		t.Error("UpdateBuffers() beyond the table should fail")
+	}
+	if err := iobuf.UnregisterBuffers(ring); err != nil {
+		t.Errorf("UnregisterBuffers() failed: %v", err)
+	}
+}
+
+func TestCloneBuffers(t *testing.T) {
+	src, dst := newTestRing(t), newTestRing(t)
+	if err := iobuf.RegisterBuffersSparse(src, 4); err != nil {
+		t.Skipf("sparse buffer tables not supported: %v", err)
+	}
+	pool := iobuf.NewRegisterBufferPool(2)
+	pool.Fill(func() iobuf.RegisterBuffer { return iobuf.RegisterBuffer{} })
+	if err := iobuf.UpdatePoolBuffers(src, pool, 0); err != nil {
+		t.Fatalf("UpdatePoolBuffers() failed: %v", err)
+	}
+
+	if err := iobuf.CloneBuffers(dst, src); err != nil {
+		if errors.Is(err, syscall.EINVAL) {
+			t.Skipf("buffer cloning not supported: %v", err)
+		}
+		t.Fatalf("CloneBuffers() failed: %v", err)
+	}
+	if err := iobuf.CloneBuffers(dst, src); !errors.Is(err, syscall.EBUSY) {
+		t.Errorf("CloneBuffers() into a registered table = %v, want EBUSY", err)
+	}
+	if err := iobuf.CloneBufferRange(dst, src, 0, 0, 2, true); err != nil {
+		t.Errorf("CloneBufferRange(replace) failed: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package iobuf
+
+import "errors"
+
+// RegisterBuffersSparse registers an empty io_uring buffer table.
+// io_uring is only available on Linux; elsewhere it returns
+// errors.ErrUnsupported. Panics if n < 1.
+func RegisterBuffersSparse(ringFd int, n int) error {
+	if n < 1 {
+		panic("iobuf.RegisterBuffersSparse: table size must be positive")
+	}
+	return errors.ErrUnsupported
+}
+
+// UpdateBuffers replaces slots of an io_uring buffer table. It returns
+// errors.ErrUnsupported outside Linux.
+func UpdateBuffers(ringFd int, offset int, vec []IoVec) error {
+	return errors.ErrUnsupported
+}
+
+// UpdatePoolBuffers registers the buffers of pool in an io_uring buffer
+// table. It returns errors.ErrUnsupported outside Linux.
+func UpdatePoolBuffers(ringFd int, pool *RegisterBufferPool, offset int) error {
+	return errors.ErrUnsupported
+}
+
+// UnregisterBuffers removes an io_uring buffer table. It returns
+// errors.ErrUnsupported outside Linux.
+func UnregisterBuffers(ringFd int) error {
+	return errors.ErrUnsupported
+}
+
+// CloneBuffers shares the registered buffer table of one io_uring
+// instance with another. It returns errors.ErrUnsupported outside Linux.
+func CloneBuffers(dstFd, srcFd int) error {
+	return errors.ErrUnsupported
+}
+
+// CloneBufferRange shares slots of a registered buffer table between
+// io_uring instances. It returns errors.ErrUnsupported outside Linux.
+func CloneBufferRange(dstFd, srcFd int, srcOff, dstOff, n int, replace bool) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,136 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "math"
+
+// RegisteredIndexMap translates between io_uring registered buffer indices
+// and the indirect indices of a RegisterBufferPool.
+//
+// With RegisterIoVecs the two coincide, but a sparse buffer table
+// (RegisterBuffersSparse) lets a pool occupy any slots, possibly shared with
+// other pools or updated piecemeal. The map records where each pooled
+// buffer is registered so completion handlers can turn the buf_index of a
+// fixed operation back into the indirect index to Put, and submitters can
+// find the buf_index of a buffer they hold.
+//
+// Lookups may run concurrently with each other, but Bind and Unbind must
+// not run concurrently with any other method.
+type RegisteredIndexMap struct {
+	pool       *RegisterBufferPool
+	toIndirect []int32 // by registered index, -1 if unbound
+	toIndex    []int32 // by indirect index, -1 if unbound
+}
+
+// NewRegisteredIndexMap returns an empty map for pool and a buffer table of
+// tableSize slots. Panics if the pool is not filled or tableSize is not in
+// [1, 65536].
+func NewRegisteredIndexMap(pool *RegisterBufferPool, tableSize int) *RegisteredIndexMap {
+	if len(pool.items) != int(pool.capacity) {
+		panic("must Fill the pool before using it")
+	}
+	if tableSize < 1 || tableSize > math.MaxUint16+1 {
+		panic("iobuf.NewRegisteredIndexMap: table size out of range")
+	}
+	m := &RegisteredIndexMap{
+		pool:       pool,
+		toIndirect: make([]int32, tableSize),
+		toIndex:    make([]int32, pool.capacity),
+	}
+	for i := range m.toIndirect {
+		m.toIndirect[i] = -1
+	}
+	for i := range m.toIndex {
+		m.toIndex[i] = -1
+	}
+	return m
+}
+
+// Pool returns the pool whose buffers the map tracks.
+func (m *RegisteredIndexMap) Pool() *RegisterBufferPool { return m.pool }
+
+// Bind records that the buffer at indirect index is registered at index,
+// replacing any previous binding of either. Panics if index or indirect is
+// out of range.
+func (m *RegisteredIndexMap) Bind(index int, indirect int) {
+	if index < 0 || index >= len(m.toIndirect) {
+		panic("iobuf.RegisteredIndexMap.Bind: registered index out of range")
+	}
+	if indirect < 0 || indirect >= len(m.toIndex) {
+		panic("iobuf.RegisteredIndexMap.Bind: indirect index out of range")
+	}
+	m.Unbind(index)
+	if old := m.toIndex[indirect]; old >= 0 {
+		m.toIndirect[old] = -1
+	}
+	m.toIndirect[index] = int32(indirect)
+	m.toIndex[indirect] = int32(index)
+}
+
+// BindRange binds n consecutive registered indices starting at index to
+// the indirect indices starting at indirect, matching an UpdateBuffers
+// call with a contiguous slice of the pool's vector.
+func (m *RegisteredIndexMap) BindRange(index int, indirect int, n int) {
+	for i := range n {
+		m.Bind(index+i, indirect+i)
+	}
+}
+
+// Unbind removes the binding of the registered index, if any, as after
+// clearing the slot with a zero IoVec. Panics if index is out of range.
+func (m *RegisteredIndexMap) Unbind(index int) {
+	if index < 0 || index >= len(m.toIndirect) {
+		panic("iobuf.RegisteredIndexMap.Unbind: registered index out of range")
+	}
+	if old := m.toIndirect[index]; old >= 0 {
+		m.toIndex[old] = -1
+		m.toIndirect[index] = -1
+	}
+}
+
+// Indirect returns the indirect index of the buffer registered at index.
+// ok is false if the slot is not bound to a buffer of the pool or index is
+// out of range.
+func (m *RegisteredIndexMap) Indirect(index int) (indirect int, ok bool) {
+	if index < 0 || index >= len(m.toIndirect) {
+		return -1, false
+	}
+	v := m.toIndirect[index]
+	return int(v), v >= 0
+}
+
+// Index returns the registered index of the buffer at indirect index.
+// ok is false if the buffer is not registered or indirect is out of range.
+func (m *RegisteredIndexMap) Index(indirect int) (index int, ok bool) {
+	if indirect < 0 || indirect >= len(m.toIndex) {
+		return -1, false
+	}
+	v := m.toIndex[indirect]
+	return int(v), v >= 0
+}
+
+// FixedIoVecOf returns the FixedIoVec for the first n bytes of the buffer
+// at indirect index, using its recorded registered index. The caller must
+// hold the index. Panics if the buffer is not bound or n is out of range.
+func (m *RegisteredIndexMap) FixedIoVecOf(indirect int, n int) FixedIoVec {
+	index, ok := m.Index(indirect)
+	if !ok {
+		panic("iobuf.RegisteredIndexMap.FixedIoVecOf: buffer is not registered")
+	}
+	v := FixedIoVecOf(m.pool, indirect, n)
+	v.BufIndex = uint16(index)
+	return v
+}
+
+// FixedIOOf returns the FixedIO for registered file fileIndex and the first
+// n bytes of the buffer at indirect index, using the buffer's recorded
+// registered index. The caller must hold the index. Panics if fileIndex is
+// negative, the buffer is not bound or n is out of range.
+func (m *RegisteredIndexMap) FixedIOOf(fileIndex int, indirect int, n int) FixedIO {
+	if fileIndex < 0 || fileIndex > math.MaxInt32 {
+		panic("iobuf.RegisteredIndexMap.FixedIOOf: file index out of range")
+	}
+	return FixedIO{FixedIoVec: m.FixedIoVecOf(indirect, n), FileIndex: uint32(fileIndex)}
+}
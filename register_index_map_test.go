@@ -0,0 +1,69 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestRegisteredIndexMap_Bind(t *testing.T) {
+	pool := iobuf.NewRegisterBufferPool(4)
+	pool.Fill(func() iobuf.RegisterBuffer { return iobuf.RegisterBuffer{} })
+	m := iobuf.NewRegisteredIndexMap(pool, 16)
+
+	m.BindRange(8, 0, 4)
+	for i := range 4 {
+		if got, ok := m.Indirect(8 + i); !ok || got != i {
+			t.Errorf("Indirect(%d) = %d, %v; want %d", 8+i, got, ok, i)
+		}
+		if got, ok := m.Index(i); !ok || got != 8+i {
+			t.Errorf("Index(%d) = %d, %v; want %d", i, got, ok, 8+i)
+		}
+	}
+	if _, ok := m.Indirect(0); ok {
+		t.Error("unbound slot reported as bound")
+	}
+
+	// Rebinding a buffer moves it; the old slot becomes empty.
+	m.Bind(3, 2)
+	if _, ok := m.Indirect(10); ok {
+		t.Error("old slot still bound after rebinding its buffer")
+	}
+	if v := m.FixedIoVecOf(2, 100); v.BufIndex != 3 || v.Len != 100 {
+		t.Errorf("FixedIoVecOf() = index %d len %d, want 3 and 100", v.BufIndex, v.Len)
+	}
+
+	m.Unbind(3)
+	if _, ok := m.Index(2); ok {
+		t.Error("buffer still registered after Unbind")
+	}
+	if _, ok := m.Indirect(-1); ok {
+		t.Error("out-of-range lookup reported as bound")
+	}
+}
+
+func TestRegisteredIndexMap_Panics(t *testing.T) {
+	pool := iobuf.NewRegisterBufferPool(2)
+	pool.Fill(func() iobuf.RegisterBuffer { return iobuf.RegisterBuffer{} })
+	m := iobuf.NewRegisteredIndexMap(pool, 4)
+	for name, f := range map[string]func(){
+		"index range":    func() { m.Bind(4, 0) },
+		"indirect range": func() { m.Bind(0, 2) },
+		"unbound fixed":  func() { m.FixedIoVecOf(0, 1) },
+		"table size":     func() { iobuf.NewRegisteredIndexMap(pool, 0) },
+		"unfilled":       func() { iobuf.NewRegisteredIndexMap(iobuf.NewRegisterBufferPool(2), 4) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s should panic", name)
+				}
+			}()
+			f()
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+// ReleaseMemory returns the physical memory of the buffers currently idle
+// in the pool to the operating system (madvise MADV_DONTNEED), so the
+// resident set shrinks between traffic peaks without destroying the pool.
+// Only whole pages inside each buffer are released; released buffers read
+// as zero when next touched and are faulted back in on demand.
+//
+// Idle buffers are taken out of the pool while they are released and put
+// back afterwards, so concurrent Get calls may briefly see fewer buffers.
+// Buffers held by callers, or cached in a CachedPool shard, are not
+// affected; call CachedPool.Flush first to include the latter.
+//
+// ReleaseMemory is meant for Huge-tier and larger pools, where a buffer
+// spans many pages. It returns the number of bytes released, which is zero
+// on platforms without madvise. Panics unless the items are byte arrays or
+// byte slices.
+func (pool *BoundedPool[T]) ReleaseMemory() int {
+	requireByteItems[T]("ReleaseMemory")
+	if len(pool.items) != int(pool.capacity) {
+		panic("must Fill the pool before using it")
+	}
+	idle := make([]uint64, 0, pool.capacity)
+	for {
+		e, err := pool.tryGet()
+		if err != nil {
+			break
+		}
+		idle = append(idle, e)
+	}
+	released := 0
+	for _, e := range idle {
+		indirect := int(e & uint64(pool.mask))
+		released += discardPages(pool.itemBytes(indirect))
+		if pool.debug&poolDebugChecksum != 0 {
+			// Released pages read as zero; drop the stale checksum
+			// rather than hash the buffer, which would fault them in.
+			pool.sums[indirect] = 0
+		}
+	}
+	for _, e := range idle {
+		_ = pool.put(int(e & uint64(pool.mask)))
+	}
+	return released
+}
@@ -0,0 +1,52 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"runtime"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestBoundedPool_ReleaseMemory(t *testing.T) {
+	const size = 64 << 10
+	pool := iobuf.NewBoundedPool[[]byte](2)
+	pool.Fill(func() []byte { return iobuf.AlignedMem(size, iobuf.PageSize) })
+
+	held, _ := pool.Get()
+	idle, _ := pool.Get()
+	pool.Bytes(held)[0] = 'H'
+	pool.Bytes(idle)[0] = 'I'
+	_ = pool.Put(idle)
+
+	released := pool.ReleaseMemory()
+	if runtime.GOOS != "linux" {
+		if released != 0 {
+			t.Errorf("ReleaseMemory() = %d without madvise support", released)
+		}
+		return
+	}
+	if released != size {
+		t.Errorf("ReleaseMemory() = %d, want the idle buffer's %d bytes", released, size)
+	}
+	if pool.Bytes(held)[0] != 'H' {
+		t.Error("held buffer was released")
+	}
+	if got, _ := pool.Get(); pool.Bytes(got)[0] != 0 {
+		t.Error("released buffer should read as zero")
+	}
+}
+
+func TestBoundedPool_ReleaseMemoryRequiresBytes(t *testing.T) {
+	pool := iobuf.NewBoundedPool[int](2)
+	pool.Fill(func() int { return 0 })
+	defer func() {
+		if recover() == nil {
+			t.Error("ReleaseMemory on non-byte items should panic")
+		}
+	}()
+	pool.ReleaseMemory()
+}
@@ -0,0 +1,82 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+// incrementalGroup is implemented by buffer groups that can consume a
+// buffer across several completions, such as an incremental BufRing.
+type incrementalGroup interface {
+	Consume(bid uint16, n int, more bool) (indirect int, data []byte, final bool)
+}
+
+// Replenisher drives a BufferGroup from the completion side.
+//
+// Replenishing by hand is easy to get wrong: forget it and receives stall
+// with ENOBUFS once the group runs dry, call it on every completion and
+// each buffer costs a ring publish or a PROVIDE_BUFFERS submission.
+// Replenisher hands each completion's buffer to the caller and, once batch
+// buffers have left the kernel, refills the group from whatever the pool
+// has available, so buffers Put back by the application return to the
+// kernel in batches.
+//
+// A Replenisher must not be used from multiple goroutines concurrently.
+type Replenisher struct {
+	g       BufferGroup
+	batch   int
+	pending int // Buffers taken from the kernel since the last Replenish
+}
+
+// NewReplenisher returns a Replenisher refilling g after every batch
+// buffers taken from the kernel. Panics if batch < 1.
+func NewReplenisher(g BufferGroup, batch int) *Replenisher {
+	if batch < 1 {
+		panic("iobuf.NewReplenisher: batch must be positive")
+	}
+	return &Replenisher{g: g, batch: batch}
+}
+
+// Group returns the buffer group being replenished.
+func (r *Replenisher) Group() BufferGroup { return r.g }
+
+// OnCompletion handles a completion of a buffer-select operation with
+// result res and CQE flags.
+//
+// If the completion carries a buffer, it returns the received data and
+// the pool indirect index of its buffer. final reports whether the caller
+// now holds the index and must Put it back when done; it is false only
+// while an incremental BufRing keeps filling the buffer.
+//
+// A completion without a buffer returns indirect -1. A failed receive
+// often means the group ran dry (ENOBUFS), so any pending refill happens
+// right away. err reports a failed refill only; res is left to the caller.
+func (r *Replenisher) OnCompletion(res int32, flags uint32) (indirect int, data []byte, final bool, err error) {
+	bid, ok := CQEBufferID(flags)
+	if !ok {
+		if r.pending > 0 {
+			_, err = r.Flush()
+		}
+		return -1, nil, false, err
+	}
+	n := max(int(res), 0)
+	if ig, inc := r.g.(incrementalGroup); inc {
+		indirect, data, final = ig.Consume(bid, n, CQEBufMore(flags))
+	} else {
+		indirect, data = r.g.Completed(bid, n)
+		final = true
+	}
+	if final {
+		r.pending++
+		if r.pending >= r.batch {
+			_, err = r.Flush()
+		}
+	}
+	return indirect, data, final, err
+}
+
+// Flush refills the group from the pool now and returns how many buffers
+// were handed to the kernel.
+func (r *Replenisher) Flush() (int, error) {
+	r.pending = 0
+	return r.g.Replenish()
+}
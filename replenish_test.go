@@ -0,0 +1,61 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestReplenisher_Batches(t *testing.T) {
+	pool := iobuf.NewSmallBufferPool(8)
+	pool.Fill(iobuf.NewSmallBuffer)
+	submits := 0
+	g := iobuf.NewProvidedBuffers(pool, 1, func(op iobuf.ProvideBuffersOp) error {
+		submits++
+		return nil
+	})
+	r := iobuf.NewReplenisher(g, 3)
+	if _, err := r.Flush(); err != nil || g.Provided() != 8 {
+		t.Fatalf("Flush() = %v, Provided() = %d", err, g.Provided())
+	}
+
+	submits = 0
+	for i, bid := range []uint32{0, 1, 2} {
+		idx, data, final, err := r.OnCompletion(10, bid<<16|1)
+		if err != nil || !final || idx != int(bid) || len(data) != 10 {
+			t.Fatalf("OnCompletion(%d) = %d, %d bytes, %v, %v", bid, idx, len(data), final, err)
+		}
+		// The application is done with the data right away.
+		_ = pool.Put(idx)
+		if want := i / 2; submits != want {
+			t.Errorf("after %d completions: %d submits, want %d", i+1, submits, want)
+		}
+	}
+	// The refill ran before the third buffer was Put back.
+	if g.Provided() != 7 {
+		t.Errorf("Provided() after a batch = %d, want 7", g.Provided())
+	}
+
+	// A completion without a buffer triggers any pending refill.
+	idx, _, _, _ := r.OnCompletion(10, 3<<16|1)
+	_ = pool.Put(idx)
+	if idx, _, _, err := r.OnCompletion(-105, 0); idx != -1 || err != nil {
+		t.Errorf("OnCompletion(ENOBUFS) = %d, %v", idx, err)
+	}
+	if g.Provided() != 8 {
+		t.Errorf("Provided() after ENOBUFS = %d, want 8", g.Provided())
+	}
+}
+
+func TestReplenisher_BatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewReplenisher with batch 0 should panic")
+		}
+	}()
+	iobuf.NewReplenisher(nil, 0)
+}
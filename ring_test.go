@@ -0,0 +1,36 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "testing"
+
+// TestBoundedPool_CursorWrap checks that Get and Put keep matching turns
+// when the cursors wrap around the uint32 range.
+func TestBoundedPool_CursorWrap(t *testing.T) {
+	pool := NewBoundedPool[SmallBuffer](256)
+	pool.Fill(func() SmallBuffer { return SmallBuffer{} })
+	pool.SetNonblock(true)
+
+	start := 0 - 2*pool.capacity
+	for c := start; c != start+pool.capacity; c++ {
+		turn := (c / pool.capacity) & boundedPoolEntryTurnMask
+		pool.entries[pool.remap(c&pool.mask)].Store(uint64(turn)<<32 | uint64(c-start))
+	}
+	pool.head.Store(start)
+	pool.tail.Store(start + pool.capacity)
+
+	for i := range 8 * int(pool.capacity) {
+		idx, err := pool.Get()
+		if err != nil {
+			t.Fatalf("Get() #%d at head %d: %v", i, pool.head.Load(), err)
+		}
+		if err := pool.Put(idx); err != nil {
+			t.Fatalf("Put(%d) #%d at tail %d: %v", idx, i, pool.tail.Load(), err)
+		}
+	}
+	if h := pool.head.Load(); h >= start {
+		t.Fatalf("head %d did not wrap", h)
+	}
+}
@@ -0,0 +1,60 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"os"
+	"unsafe"
+)
+
+// SharedMem is a memory region backed by an anonymous shared-memory file
+// (memfd) that can be mapped by cooperating processes.
+//
+// The file descriptor returned by File can be passed to another process,
+// e.g., over a Unix socket with SCM_RIGHTS, which maps the same physical
+// pages with MapSharedMem. Buffers are then handed off by offset instead
+// of being copied. NewBufferPool carves a pool of buffers from the region.
+type SharedMem struct {
+	f   *os.File
+	mem []byte
+}
+
+// Bytes returns the mapped region.
+func (m *SharedMem) Bytes() []byte { return m.mem }
+
+// File returns the shared-memory file backing the region.
+// Its descriptor can be sent to a peer process.
+func (m *SharedMem) File() *os.File { return m.f }
+
+// Offset returns the offset of buf within the region, for handing a buffer
+// off to a peer process, or -1 if buf does not start inside the region.
+func (m *SharedMem) Offset(buf []byte) int {
+	if len(m.mem) == 0 || cap(buf) == 0 {
+		return -1
+	}
+	off := uintptr(unsafe.Pointer(unsafe.SliceData(buf))) - uintptr(unsafe.Pointer(unsafe.SliceData(m.mem)))
+	if off >= uintptr(len(m.mem)) {
+		return -1
+	}
+	return int(off)
+}
+
+// NewBufferPool returns a filled pool of []byte buffers of the given size
+// carved from the start of the region, so both processes can locate a
+// buffer by its indirect index as indirect*size.
+// Panics if the region is too small for capacity rounded up to a power of two.
+func (m *SharedMem) NewBufferPool(size int, capacity int) *BoundedPool[[]byte] {
+	pool := NewBoundedPool[[]byte](capacity)
+	if size < 1 || size*pool.Cap() > len(m.mem) {
+		panic("iobuf.SharedMem.NewBufferPool: region too small")
+	}
+	i := 0
+	pool.Fill(func() []byte {
+		b := m.mem[i*size : (i+1)*size : (i+1)*size]
+		i++
+		return b
+	})
+	return pool
+}
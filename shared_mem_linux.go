@@ -0,0 +1,73 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// mfdCloexec is MFD_CLOEXEC from linux/memfd.h.
+const mfdCloexec = 0x1
+
+// NewSharedMem creates a shared-memory region of size bytes backed by a
+// memfd with the given name, which appears in /proc/self/fd for debugging.
+// The region is rounded up to whole pages. Panics if size < 1.
+func NewSharedMem(name string, size int) (*SharedMem, error) {
+	if size < 1 {
+		panic("iobuf.NewSharedMem: size must be positive")
+	}
+	p, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	fd, _, errno := syscall.Syscall(sysMemfdCreate, uintptr(unsafe.Pointer(p)), mfdCloexec, 0)
+	if errno != 0 {
+		return nil, os.NewSyscallError("memfd_create", errno)
+	}
+	f := os.NewFile(fd, "memfd:"+name)
+	ps := syscall.Getpagesize()
+	if err := f.Truncate(int64((size + ps - 1) &^ (ps - 1))); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	m, err := mapShared(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// MapSharedMem maps a shared-memory file received from a peer process,
+// e.g., over SCM_RIGHTS. The SharedMem takes ownership of f.
+func MapSharedMem(f *os.File) (*SharedMem, error) {
+	return mapShared(f)
+}
+
+// mapShared maps the whole of f read-write and shared.
+func mapShared(f *os.File) (*SharedMem, error) {
+	st, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	mem, err := syscall.Mmap(int(f.Fd()), 0, int(st.Size()), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, os.NewSyscallError("mmap", err)
+	}
+	return &SharedMem{f: f, mem: mem}, nil
+}
+
+// Close unmaps the region and closes the file. Pools carved from the
+// region must not be used afterwards.
+func (m *SharedMem) Close() error {
+	err := syscall.Munmap(m.mem)
+	m.mem = nil
+	if cerr := m.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
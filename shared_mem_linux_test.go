@@ -0,0 +1,69 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestSharedMem_MapTwice(t *testing.T) {
+	m, err := iobuf.NewSharedMem("iobuf-test", 10000)
+	if errors.Is(err, syscall.ENOSYS) {
+		t.Skipf("shared memory not available: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("NewSharedMem() failed: %v", err)
+	}
+	defer m.Close()
+	if len(m.Bytes()) < 10000 {
+		t.Fatalf("len(Bytes()) = %d, want >= 10000", len(m.Bytes()))
+	}
+
+	// A second mapping of the same file, as a peer process would create
+	// from a descriptor received over SCM_RIGHTS.
+	fd, err := syscall.Dup(int(m.File().Fd()))
+	if err != nil {
+		t.Fatalf("dup: %v", err)
+	}
+	peer, err := iobuf.MapSharedMem(os.NewFile(uintptr(fd), "peer"))
+	if err != nil {
+		t.Fatalf("MapSharedMem() failed: %v", err)
+	}
+	defer peer.Close()
+
+	pool := m.NewBufferPool(1024, 8)
+	idx, _ := pool.Get()
+	buf := pool.Value(idx)
+	copy(buf, "zero-copy hand-off")
+	off := m.Offset(buf)
+	if off != idx*1024 {
+		t.Errorf("Offset() = %d, want %d", off, idx*1024)
+	}
+	if got := string(peer.Bytes()[off : off+18]); got != "zero-copy hand-off" {
+		t.Errorf("peer mapping sees %q", got)
+	}
+	if m.Offset(make([]byte, 8)) != -1 {
+		t.Error("Offset() of foreign buffer should be -1")
+	}
+}
+
+func TestSharedMem_PoolTooLarge(t *testing.T) {
+	m, err := iobuf.NewSharedMem("iobuf-test", 4096)
+	if err != nil {
+		t.Skipf("shared memory not available: %v", err)
+	}
+	defer m.Close()
+	defer func() {
+		if recover() == nil {
+			t.Error("NewBufferPool beyond region should panic")
+		}
+	}()
+	m.NewBufferPool(4096, 2)
+}
@@ -0,0 +1,33 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package iobuf
+
+import (
+	"errors"
+	"os"
+)
+
+// NewSharedMem creates a memfd-backed shared-memory region. memfd is only
+// supported on Linux; elsewhere it returns errors.ErrUnsupported.
+func NewSharedMem(name string, size int) (*SharedMem, error) {
+	if size < 1 {
+		panic("iobuf.NewSharedMem: size must be positive")
+	}
+	return nil, errors.ErrUnsupported
+}
+
+// MapSharedMem maps a shared-memory file received from a peer process.
+// It is only supported on Linux; elsewhere it returns errors.ErrUnsupported.
+func MapSharedMem(f *os.File) (*SharedMem, error) {
+	return nil, errors.ErrUnsupported
+}
+
+// Close closes the file backing the region.
+func (m *SharedMem) Close() error {
+	m.mem = nil
+	return m.f.Close()
+}
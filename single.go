@@ -0,0 +1,106 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "code.hybscloud.com/iox"
+
+// SetSingleProducer asserts that at most one goroutine at a time calls
+// Put, enabling a wait-free Put while Get stays multi-consumer.
+//
+// With a single producer the tail cursor has one writer, so Put advances
+// it with a plain store instead of a fetch-and-add, and checks its slot
+// before reserving room instead of after. A Put therefore never waits:
+// if the Get taking the slot's previous entry has reserved it but not yet
+// emptied the slot, Put treats the pool as full, returning
+// iox.ErrWouldBlock in nonblocking mode or waiting with the pool's
+// WaitStrategy like any full pool.
+//
+// Typical receive pipelines have exactly one producer, the reactor
+// returning buffers. The assertion covers every path into the pool,
+// including CachedPool flushes; concurrent Puts corrupt the ring.
+// SetSingleProducer must be called before the pool is shared between
+// goroutines.
+func (pool *BoundedPool[T]) SetSingleProducer(enabled bool) {
+	pool.singleProducer = enabled
+}
+
+// SetSingleConsumer asserts that at most one goroutine at a time calls
+// Get, enabling a wait-free Get while Put stays multi-producer.
+//
+// It mirrors SetSingleProducer: Get advances the head cursor with a plain
+// store and takes its slot only once the entry is stored, treating the
+// pool as empty while the Put filling the slot is still in flight. The
+// assertion covers every path out of the pool, including CachedPool
+// refills and NodePool borrowing. SetSingleConsumer must be called before
+// the pool is shared between goroutines.
+func (pool *BoundedPool[T]) SetSingleConsumer(enabled bool) {
+	pool.singleConsumer = enabled
+}
+
+// tryGetSingle is tryGet for a single consumer.
+//
+// The consumer alone takes positions in order, so every position before
+// head has been emptied by it. An entry stored at head was counted by its
+// Put, which makes the reservation below always succeed.
+func (pool *BoundedPool[T]) tryGetSingle() (entry uint64, err error) {
+	h := pool.head.Load()
+	slot := &pool.entries[pool.remap(h&pool.mask)]
+	turn := (h / pool.capacity) & boundedPoolEntryTurnMask
+	e := slot.Load()
+	if e&boundedPoolEntryEmpty != 0 || uint32(e>>32) != turn {
+		return boundedPoolEntryEmpty, iox.ErrWouldBlock
+	}
+	n := pool.count.Add(-1)
+	slot.Store(pool.empty((h + pool.capacity) / pool.capacity))
+	pool.head.Store(h + 1)
+	if n == int64(pool.capacity)-1 && pool.debug&poolSignal != 0 {
+		pool.signal(PoolPut)
+	}
+	return e, nil
+}
+
+// tryPutSingle is tryPut for a single producer.
+//
+// An empty slot at tail means the Get of the slot's previous turn has
+// finished, so fewer than capacity items are counted and the count can be
+// raised without a check. It is raised after the store, so a Get
+// reserving the item finds it in place.
+func (pool *BoundedPool[T]) tryPutSingle(e uint64) error {
+	t := pool.tail.Load()
+	slot := &pool.entries[pool.remap(t&pool.mask)]
+	turn := (t / pool.capacity) & boundedPoolEntryTurnMask
+	if slot.Load() != pool.empty(turn) {
+		return iox.ErrWouldBlock
+	}
+	slot.Store(uint64(turn)<<32 | e)
+	pool.tail.Store(t + 1)
+	if pool.count.Add(1) == 1 && pool.debug&poolSignal != 0 {
+		pool.signal(PoolGet)
+	}
+	return nil
+}
+
+// putWouldBlock decides up front whether a Put would fail, so the debug
+// actions, which zero the buffer and record it as released, never run for
+// a Put that hands the index back.
+//
+// Every index a caller holds has room in the pool, so only a nonblocking
+// single producer can fail, when the Get of its slot is still in flight.
+// Only the producer fills the slot at tail, so once it is empty it stays
+// empty until the Put that follows.
+func (pool *BoundedPool[T]) putWouldBlock() bool {
+	if !pool.nonblocking || !pool.singleProducer {
+		return false
+	}
+	t := pool.tail.Load()
+	turn := (t / pool.capacity) & boundedPoolEntryTurnMask
+	if pool.entries[pool.remap(t&pool.mask)].Load() == pool.empty(turn) {
+		return false
+	}
+	if pool.debug&poolDebugStats != 0 {
+		pool.stats.wouldBlocks.Add(1)
+	}
+	return true
+}
@@ -0,0 +1,164 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"sync"
+	"testing"
+)
+
+// checkAllIndices drains a non-blocking pool and checks that it holds
+// every index once.
+func checkAllIndices[T BoundedPoolItem](t *testing.T, pool *BoundedPool[T]) {
+	t.Helper()
+	pool.SetNonblock(true)
+	seen := make([]bool, pool.Cap())
+	for i := range pool.Cap() {
+		idx, err := pool.Get()
+		if err != nil {
+			t.Fatalf("Get() = %v with %d of %d indices drained", err, i, pool.Cap())
+		}
+		if seen[idx] {
+			t.Fatalf("Get() returned %d twice", idx)
+		}
+		seen[idx] = true
+	}
+	if _, err := pool.Get(); err == nil {
+		t.Fatal("Get() from a drained pool succeeded")
+	}
+}
+
+func TestBoundedPool_SingleProducer(t *testing.T) {
+	const consumers, iterations = 8, 2000
+	pool := NewBoundedPool[int](8)
+	pool.Fill(func() int { return 0 })
+	pool.SetSingleProducer(true)
+	if !pool.Config().SingleProducer {
+		t.Fatal("Config().SingleProducer = false after SetSingleProducer(true)")
+	}
+
+	// Consumers hand their indices to the one goroutine calling Put.
+	returned := make(chan int)
+	var wg sync.WaitGroup
+	for range consumers {
+		wg.Go(func() {
+			for range iterations {
+				idx, _ := pool.Get()
+				returned <- idx
+			}
+		})
+	}
+	done := make(chan struct{})
+	go func() {
+		for idx := range returned {
+			if err := pool.Put(idx); err != nil {
+				t.Errorf("Put(%d) = %v", idx, err)
+			}
+		}
+		close(done)
+	}()
+	wg.Wait()
+	close(returned)
+	<-done
+	checkAllIndices(t, pool)
+}
+
+func TestBoundedPool_SingleConsumer(t *testing.T) {
+	const producers, iterations = 8, 2000
+	pool := NewBoundedPool[int](8)
+	pool.Fill(func() int { return 0 })
+	pool.SetSingleConsumer(true)
+
+	// The one goroutine calling Get hands indices out to the producers.
+	handed := make(chan int)
+	go func() {
+		for range producers * iterations {
+			idx, _ := pool.Get()
+			handed <- idx
+		}
+		close(handed)
+	}()
+	var wg sync.WaitGroup
+	for range producers {
+		wg.Go(func() {
+			for idx := range handed {
+				if err := pool.Put(idx); err != nil {
+					t.Errorf("Put(%d) = %v", idx, err)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	checkAllIndices(t, pool)
+}
+
+// TestBoundedPool_SingleCursorWrap checks the single-producer and
+// single-consumer paths across the wrap of the uint32 cursors.
+func TestBoundedPool_SingleCursorWrap(t *testing.T) {
+	pool := NewBoundedPool[int](16)
+	pool.Fill(func() int { return 0 })
+	pool.SetNonblock(true)
+	pool.SetSingleProducer(true)
+	pool.SetSingleConsumer(true)
+
+	start := 0 - 2*pool.capacity
+	for c := start; c != start+pool.capacity; c++ {
+		turn := (c / pool.capacity) & boundedPoolEntryTurnMask
+		pool.entries[pool.remap(c&pool.mask)].Store(uint64(turn)<<32 | uint64(c-start))
+	}
+	pool.head.Store(start)
+	pool.tail.Store(start + pool.capacity)
+
+	if err := pool.Put(0); err == nil {
+		t.Fatal("Put() into a full pool succeeded")
+	}
+	for i := range 8 * int(pool.capacity) {
+		idx, err := pool.Get()
+		if err != nil {
+			t.Fatalf("Get() #%d at head %d: %v", i, pool.head.Load(), err)
+		}
+		if err := pool.Put(idx); err != nil {
+			t.Fatalf("Put(%d) #%d at tail %d: %v", idx, i, pool.tail.Load(), err)
+		}
+	}
+	if h := pool.head.Load(); h >= start {
+		t.Fatalf("head %d did not wrap", h)
+	}
+	checkAllIndices(t, pool)
+}
+
+func TestBoundedPool_SingleProducerWouldBlockKeepsBuffer(t *testing.T) {
+	pool := NewBoundedPool[[]byte](2)
+	pool.Fill(func() []byte { return make([]byte, 16) })
+	pool.SetSingleProducer(true)
+	pool.SetNonblock(true)
+	pool.SetZeroOnPut(true)
+	idx, err := pool.Get()
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	copy(pool.Bytes(idx), "payload")
+
+	// Put the slot back in the state of a Get that has reserved the
+	// entry but not emptied the slot yet.
+	t0 := pool.tail.Load()
+	slot := &pool.entries[pool.remap(t0&pool.mask)]
+	empty := slot.Load()
+	slot.Store(uint64(idx))
+	if err := pool.Put(idx); err == nil {
+		t.Fatal("Put() into an in-flight slot succeeded")
+	}
+	if string(pool.Bytes(idx)[:7]) != "payload" {
+		t.Error("failed Put zeroed the buffer")
+	}
+
+	slot.Store(empty)
+	if err := pool.Put(idx); err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+	if string(pool.Bytes(idx)[:7]) == "payload" {
+		t.Error("buffer not zeroed on Put")
+	}
+}
@@ -0,0 +1,107 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// TierCustom is the first tier index assigned to size classes registered
+// with RegisterBufferSize. Custom tiers are numbered in registration order.
+const TierCustom BufferTier = 1 << 8
+
+var (
+	customSizesMu sync.Mutex
+	customSizes   atomic.Pointer[[]int]
+)
+
+// RegisterBufferSize registers a custom buffer size class and returns its
+// tier.
+//
+// The power-of-4 tier ladder wastes memory for common network sizes such as
+// a 9 KiB jumbo frame (rounded up to 32 KiB). Registered sizes take part in
+// TierFor and NewBufferPoolFor like the built-in tiers; their pools hold
+// []byte buffers carved from one page-aligned allocation.
+//
+// Registering a size that matches a built-in tier returns that tier, and
+// registering the same size twice returns the same tier. Registration is
+// intended for program initialization but is safe for concurrent use.
+//
+// Panics if size < 1 or size > BufferSizeTitan.
+func RegisterBufferSize(size int) BufferTier {
+	if size < 1 || size > BufferSizeTitan {
+		panic("buffer size must be between 1 and BufferSizeTitan")
+	}
+	if t := TierBySize(size); t.Size() == size {
+		return t
+	}
+	customSizesMu.Lock()
+	defer customSizesMu.Unlock()
+	var sizes []int
+	if p := customSizes.Load(); p != nil {
+		sizes = *p
+	}
+	for i, s := range sizes {
+		if s == size {
+			return TierCustom + BufferTier(i)
+		}
+	}
+	next := make([]int, len(sizes), len(sizes)+1)
+	copy(next, sizes)
+	next = append(next, size)
+	customSizes.Store(&next)
+	return TierCustom + BufferTier(len(sizes))
+}
+
+// TierFor returns the smallest built-in or registered tier that can hold
+// size bytes. Returns TierTitan for sizes larger than BufferSizeTitan.
+//
+// Without registered sizes TierFor is equivalent to TierBySize.
+func TierFor(size int) BufferTier {
+	best := TierBySize(size)
+	p := customSizes.Load()
+	if p == nil {
+		return best
+	}
+	for i, s := range *p {
+		if s >= size && s < best.Size() {
+			best = TierCustom + BufferTier(i)
+		}
+	}
+	return best
+}
+
+// IsCustom reports whether t is a tier registered with RegisterBufferSize.
+func (t BufferTier) IsCustom() bool {
+	_, ok := customSize(t)
+	return ok
+}
+
+// customSize returns the buffer size of a registered tier.
+func customSize(t BufferTier) (size int, ok bool) {
+	if t < TierCustom {
+		return 0, false
+	}
+	p := customSizes.Load()
+	if p == nil || int(t-TierCustom) >= len(*p) {
+		return 0, false
+	}
+	return (*p)[t-TierCustom], true
+}
+
+// newCustomPool creates a filled pool of []byte buffers of the given size,
+// all carved from a single page-aligned allocation.
+func newCustomPool(size int, capacity int) *BoundedPool[[]byte] {
+	pool := NewBoundedPool[[]byte](capacity)
+	mem := AlignedMem(size*pool.Cap(), PageSize)
+	i := 0
+	pool.Fill(func() []byte {
+		b := mem[i*size : (i+1)*size : (i+1)*size]
+		i++
+		return b
+	})
+	return pool
+}
@@ -0,0 +1,109 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestRegisterBufferSize(t *testing.T) {
+	jumbo := iobuf.RegisterBufferSize(9216)
+	if !jumbo.IsCustom() {
+		t.Fatalf("RegisterBufferSize(9216) = %v, want a custom tier", jumbo)
+	}
+	if jumbo.Size() != 9216 {
+		t.Errorf("Size() = %d, want 9216", jumbo.Size())
+	}
+	if got := jumbo.String(); got != "Custom(9216)" {
+		t.Errorf("String() = %q, want %q", got, "Custom(9216)")
+	}
+	if again := iobuf.RegisterBufferSize(9216); again != jumbo {
+		t.Errorf("re-registering returned %v, want %v", again, jumbo)
+	}
+	if builtin := iobuf.RegisterBufferSize(iobuf.BufferSizeSmall); builtin != iobuf.TierSmall {
+		t.Errorf("registering a built-in size returned %v, want TierSmall", builtin)
+	}
+	if iobuf.TierSmall.IsCustom() {
+		t.Error("TierSmall.IsCustom() = true")
+	}
+
+	if got := iobuf.TierFor(9000); got != jumbo {
+		t.Errorf("TierFor(9000) = %v, want %v", got, jumbo)
+	}
+	if got := iobuf.TierFor(9217); got != iobuf.TierBig {
+		t.Errorf("TierFor(9217) = %v, want TierBig", got)
+	}
+	if got := iobuf.TierFor(100); got != iobuf.TierNano {
+		t.Errorf("TierFor(100) = %v, want TierNano", got)
+	}
+}
+
+func TestRegisterBufferSize_Invalid(t *testing.T) {
+	for _, size := range []int{0, -1, iobuf.BufferSizeTitan + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterBufferSize(%d) should panic", size)
+				}
+			}()
+			iobuf.RegisterBufferSize(size)
+		}()
+	}
+}
+
+func TestNewBufferPoolFor_CustomTier(t *testing.T) {
+	mtu := iobuf.RegisterBufferSize(1536)
+	pool, tier := iobuf.NewBufferPoolFor(1500, 8)
+	if tier != mtu {
+		t.Fatalf("NewBufferPoolFor(1500) tier = %v, want %v", tier, mtu)
+	}
+
+	indices := make([]int, 0, pool.Cap())
+	for range pool.Cap() {
+		idx, err := pool.Get()
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		if n := len(pool.Bytes(idx)); n != 1536 {
+			t.Errorf("buffer len = %d, want 1536", n)
+		}
+		indices = append(indices, idx)
+	}
+
+	vec := iobuf.IoVecFromPool(pool, indices)
+	if len(vec) != len(indices) {
+		t.Fatalf("IoVecFromPool len = %d, want %d", len(vec), len(indices))
+	}
+	for i, v := range vec {
+		if v.Len != 1536 {
+			t.Errorf("vec[%d].Len = %d, want 1536", i, v.Len)
+		}
+		if v.Base != unsafe.SliceData(pool.Bytes(indices[i])) {
+			t.Errorf("vec[%d].Base does not point at the pooled buffer", i)
+		}
+	}
+
+	// Writing a full buffer must not spill into its neighbor.
+	b0, b1 := pool.Bytes(indices[0]), pool.Bytes(indices[1])
+	for i := range b0 {
+		b0[i] = 0xFF
+	}
+	if b1[0] != 0 {
+		t.Error("custom tier buffers overlap")
+	}
+	if cap(b0) != len(b0) {
+		t.Errorf("cap = %d, want %d", cap(b0), len(b0))
+	}
+}
+
+func TestIoVecFromPool_Empty(t *testing.T) {
+	pool, _ := iobuf.NewBufferPoolFor(64, 1)
+	if vec := iobuf.IoVecFromPool(pool, nil); vec != nil {
+		t.Errorf("IoVecFromPool(nil) = %v, want nil", vec)
+	}
+}
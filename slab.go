@@ -0,0 +1,269 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"math/bits"
+	"slices"
+	"sync"
+	"unsafe"
+)
+
+// SlabAllocator allocates odd-sized objects from fixed-size slots.
+//
+// Memory is reserved in slabs of one aligned region each. Every slab is
+// divided into equally sized slots of one size class and tracks occupancy
+// in a bitmap. Per class, slabs are kept on partial, full and empty lists:
+// allocations are served from a partial slab, a slab that becomes empty is
+// kept for reuse, and surplus empty slabs are released to the garbage
+// collector. This fills the gap between the fixed tier pools and general
+// heap allocation for objects like 300-byte descriptors.
+//
+// A SlabAllocator is safe for concurrent use; each size class has its own
+// lock.
+type SlabAllocator struct {
+	slabSize int
+	classes  []slabClass
+	sizes    []int // Slot size per class, increasing
+
+	mu    sync.RWMutex
+	slabs map[uintptr][]*slab // Slabs overlapping each slabSize-aligned bucket
+	n     int                 // Number of slabs held
+}
+
+// slabClass holds the slabs of one slot size.
+type slabClass struct {
+	mu      sync.Mutex
+	size    int
+	partial *slab
+	full    *slab
+	empty   *slab
+	nEmpty  int
+}
+
+// slab is one region divided into slots of a single size.
+type slab struct {
+	mem        []byte
+	class      *slabClass
+	bitmap     []uint64 // Set bits mark allocated slots
+	used       int
+	slots      int
+	list       **slab // Head of the list the slab is on
+	prev, next *slab
+}
+
+// slabMaxEmpty is the number of empty slabs kept per class for reuse.
+const slabMaxEmpty = 1
+
+// NewSlabAllocator returns a SlabAllocator with slabs of slabSize bytes,
+// rounded up to a power of two, and the given slot sizes as size classes.
+// Slabs are aligned to PageSize.
+//
+// Panics if slabSize < 1, no size class is given, or a size class is < 1
+// or larger than the slab.
+func NewSlabAllocator(slabSize int, sizeClasses ...int) *SlabAllocator {
+	if slabSize < 1 {
+		panic("iobuf.NewSlabAllocator: slab size must be positive")
+	}
+	if len(sizeClasses) == 0 {
+		panic("iobuf.NewSlabAllocator: no size classes")
+	}
+	slabSize = 1 << bits.Len(uint(slabSize-1))
+	sizes := slices.Clone(sizeClasses)
+	slices.Sort(sizes)
+	sizes = slices.Compact(sizes)
+	if sizes[0] < 1 || sizes[len(sizes)-1] > slabSize {
+		panic("iobuf.NewSlabAllocator: size class out of range")
+	}
+	a := &SlabAllocator{
+		slabSize: slabSize,
+		classes:  make([]slabClass, len(sizes)),
+		sizes:    sizes,
+		slabs:    make(map[uintptr][]*slab),
+	}
+	for i, size := range sizes {
+		a.classes[i].size = size
+	}
+	return a
+}
+
+// SlabSize returns the size of each slab.
+func (a *SlabAllocator) SlabSize() int { return a.slabSize }
+
+// Alloc returns a slot of the smallest size class that holds n bytes,
+// sliced to length n with capacity of the slot. The contents are
+// undefined. Returns nil if n exceeds the largest size class.
+// Panics if n < 1.
+func (a *SlabAllocator) Alloc(n int) []byte {
+	if n < 1 {
+		panic("iobuf.SlabAllocator.Alloc: size must be positive")
+	}
+	i, _ := slices.BinarySearch(a.sizes, n)
+	if i == len(a.sizes) {
+		return nil
+	}
+	c := &a.classes[i]
+	c.mu.Lock()
+	s := c.partial
+	if s == nil {
+		s = c.empty
+		if s != nil {
+			c.nEmpty--
+		} else {
+			s = a.newSlab(c)
+		}
+		s.move(&c.partial)
+	}
+	slot := s.alloc()
+	if s.used == s.slots {
+		s.move(&c.full)
+	}
+	c.mu.Unlock()
+	off := slot * c.size
+	return s.mem[off : off+n : off+c.size]
+}
+
+// Free returns a slot obtained from Alloc. Panics if b was not allocated
+// by a or was already freed.
+func (a *SlabAllocator) Free(b []byte) {
+	addr := uintptr(unsafe.Pointer(unsafe.SliceData(b)))
+	s := a.lookup(addr)
+	if s == nil {
+		panic("iobuf.SlabAllocator.Free: memory not allocated by this allocator")
+	}
+	c := s.class
+	off := int(addr - s.base())
+	slot := off / c.size
+	if off%c.size != 0 || slot >= s.slots {
+		panic("iobuf.SlabAllocator.Free: not the start of a slot")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !s.free(slot) {
+		panic("iobuf.SlabAllocator.Free: double free")
+	}
+	switch {
+	case s.used == 0 && c.nEmpty < slabMaxEmpty:
+		s.move(&c.empty)
+		c.nEmpty++
+	case s.used == 0:
+		s.move(nil)
+		a.unregister(s)
+	case s.used == s.slots-1:
+		s.move(&c.partial)
+	}
+}
+
+// Slabs returns the number of slabs currently held, including empty ones.
+func (a *SlabAllocator) Slabs() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.n
+}
+
+// newSlab reserves a slab for class c. The caller must hold c.mu.
+func (a *SlabAllocator) newSlab(c *slabClass) *slab {
+	slots := a.slabSize / c.size
+	s := &slab{
+		mem:    AlignedMem(a.slabSize, PageSize),
+		class:  c,
+		bitmap: make([]uint64, (slots+63)/64),
+		slots:  slots,
+	}
+	a.mu.Lock()
+	for _, k := range a.buckets(s) {
+		a.slabs[k] = append(a.slabs[k], s)
+	}
+	a.n++
+	a.mu.Unlock()
+	return s
+}
+
+// unregister forgets a released slab.
+func (a *SlabAllocator) unregister(s *slab) {
+	a.mu.Lock()
+	for _, k := range a.buckets(s) {
+		a.slabs[k] = slices.DeleteFunc(a.slabs[k], func(x *slab) bool { return x == s })
+		if len(a.slabs[k]) == 0 {
+			delete(a.slabs, k)
+		}
+	}
+	a.n--
+	a.mu.Unlock()
+}
+
+// buckets returns the slabSize-aligned buckets a slab overlaps. A slab
+// spans at most two of them, so a lookup by address checks one bucket.
+func (a *SlabAllocator) buckets(s *slab) []uintptr {
+	first := s.base() / uintptr(a.slabSize)
+	last := (s.base() + uintptr(a.slabSize) - 1) / uintptr(a.slabSize)
+	if first == last {
+		return []uintptr{first}
+	}
+	return []uintptr{first, last}
+}
+
+// lookup returns the slab containing addr, or nil.
+func (a *SlabAllocator) lookup(addr uintptr) *slab {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for _, s := range a.slabs[addr/uintptr(a.slabSize)] {
+		if addr-s.base() < uintptr(a.slabSize) {
+			return s
+		}
+	}
+	return nil
+}
+
+// base returns the start address of the slab.
+func (s *slab) base() uintptr { return uintptr(unsafe.Pointer(unsafe.SliceData(s.mem))) }
+
+// alloc marks the first free slot as used and returns its index.
+// The slab must not be full.
+func (s *slab) alloc() int {
+	for i, w := range s.bitmap {
+		if w != ^uint64(0) {
+			bit := bits.TrailingZeros64(^w)
+			s.bitmap[i] |= 1 << bit
+			s.used++
+			return i*64 + bit
+		}
+	}
+	panic("iobuf: slab full")
+}
+
+// free marks a slot as unused. It reports false if the slot was not in use.
+func (s *slab) free(slot int) bool {
+	w, bit := slot/64, uint(slot%64)
+	if s.bitmap[w]&(1<<bit) == 0 {
+		return false
+	}
+	s.bitmap[w] &^= 1 << bit
+	s.used--
+	return true
+}
+
+// move unlinks s from its current list and pushes it onto list, or leaves
+// it unlinked if list is nil.
+func (s *slab) move(list **slab) {
+	if s.list != nil {
+		if s.prev != nil {
+			s.prev.next = s.next
+		} else {
+			*s.list = s.next
+		}
+		if s.next != nil {
+			s.next.prev = s.prev
+		}
+	}
+	s.prev, s.next, s.list = nil, nil, list
+	if list != nil {
+		s.next = *list
+		if s.next != nil {
+			s.next.prev = s
+		}
+		*list = s
+	}
+}
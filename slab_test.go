@@ -0,0 +1,106 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"sync"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestSlabAllocator_AllocFree(t *testing.T) {
+	a := iobuf.NewSlabAllocator(4096, 300, 64, 1000)
+	b := a.Alloc(200)
+	if len(b) != 200 || cap(b) != 300 {
+		t.Fatalf("Alloc(200) len=%d cap=%d, want 200 and 300-byte slot", len(b), cap(b))
+	}
+	if a.Alloc(4097) != nil {
+		t.Error("Alloc beyond the largest class should return nil")
+	}
+
+	// 4096/300 = 13 slots per slab; the 14th allocation opens a new slab.
+	slots := [][]byte{b}
+	for range 13 {
+		slots = append(slots, a.Alloc(300))
+	}
+	if a.Slabs() != 2 {
+		t.Fatalf("Slabs() = %d, want 2", a.Slabs())
+	}
+	seen := make(map[*byte]bool)
+	for _, s := range slots {
+		if seen[&s[:1][0]] {
+			t.Fatal("slot handed out twice")
+		}
+		seen[&s[:1][0]] = true
+	}
+
+	// Freeing everything keeps one empty slab for reuse and releases the rest.
+	for _, s := range slots {
+		a.Free(s)
+	}
+	if a.Slabs() != 1 {
+		t.Errorf("Slabs() after freeing all = %d, want 1 cached empty slab", a.Slabs())
+	}
+	again := a.Alloc(300)
+	a.Free(again)
+	if a.Slabs() != 1 {
+		t.Errorf("reuse of empty slab allocated a new one: Slabs() = %d", a.Slabs())
+	}
+}
+
+func TestSlabAllocator_FreePanics(t *testing.T) {
+	a := iobuf.NewSlabAllocator(4096, 64)
+	b := a.Alloc(64)
+	keep := a.Alloc(64)
+	defer a.Free(keep)
+	a.Free(b)
+	for name, f := range map[string]func(){
+		"double free": func() { a.Free(b) },
+		"foreign":     func() { a.Free(make([]byte, 64)) },
+		"misaligned":  func() { a.Free(keep[1:]) },
+		"bad class":   func() { iobuf.NewSlabAllocator(4096, 8192) },
+		"no classes":  func() { iobuf.NewSlabAllocator(4096) },
+		"zero size":   func() { a.Alloc(0) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s should panic", name)
+				}
+			}()
+			f()
+		})
+	}
+}
+
+func TestSlabAllocator_Concurrent(t *testing.T) {
+	a := iobuf.NewSlabAllocator(8192, 48, 200)
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Go(func() {
+			var held [][]byte
+			for i := range 500 {
+				b := a.Alloc(40 + (g+i)%150)
+				b[0] = byte(g)
+				held = append(held, b)
+				if i%3 == 0 {
+					a.Free(held[0])
+					held = held[1:]
+				}
+			}
+			for _, b := range held {
+				if b[0] != byte(g) {
+					t.Error("slot shared between goroutines")
+				}
+				a.Free(b)
+			}
+		})
+	}
+	wg.Wait()
+	if a.Slabs() > 2 {
+		t.Errorf("Slabs() after freeing all = %d, want at most one per class", a.Slabs())
+	}
+}
@@ -0,0 +1,142 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "unsafe"
+
+// SQE matches the layout of struct io_uring_sqe, the 64-byte io_uring
+// submission queue entry.
+//
+// iobuf does not own a ring. SQE gives the Prep helpers a fixed layout to
+// write to, so ring libraries can convert a pointer to their own entry
+// with (*iobuf.SQE)(unsafe.Pointer(sqe)) and let iobuf fill the address,
+// length and buffer fields from pool state.
+type SQE struct {
+	Opcode      uint8
+	Flags       uint8
+	Ioprio      uint16
+	Fd          int32
+	Off         uint64
+	Addr        uint64
+	Len         uint32
+	OpFlags     uint32
+	UserData    uint64
+	BufIndex    uint16 // buf_index, or buf_group with IOSQE_BUFFER_SELECT
+	Personality uint16
+	SpliceFdIn  int32
+	Addr3       uint64
+	_           uint64
+}
+
+var _ [unsafe.Sizeof(SQE{}) - 64]struct{}
+var _ [64 - unsafe.Sizeof(SQE{})]struct{}
+
+// io_uring opcodes and SQE flags used by the Prep helpers.
+const (
+	ioringOpReadFixed      = 4
+	ioringOpWriteFixed     = 5
+	ioringOpRecv           = 27
+	ioringOpProvideBuffers = 31
+	ioringOpRemoveBuffers  = 32
+
+	iosqeFixedFile    = 1 << 0
+	iosqeBufferSelect = 1 << 5
+)
+
+// PrepReadFixed prepares sqe for an IORING_OP_READ_FIXED reading into the
+// whole buffer at indirect index of pool from fd at file offset off,
+// assuming the pool was registered in the order of RegisterIoVecs. The
+// entry is reset first; set UserData afterwards.
+func PrepReadFixed(sqe *SQE, fd int, indirect int, pool *RegisterBufferPool, off uint64) {
+	prepFixed(sqe, ioringOpReadFixed, fd, FixedIoVecOf(pool, indirect, registerBufferSize), off)
+}
+
+// PrepWriteFixed prepares sqe for an IORING_OP_WRITE_FIXED writing the
+// first n bytes of the buffer at indirect index of pool to fd at file
+// offset off, assuming the pool was registered in the order of
+// RegisterIoVecs. The entry is reset first; set UserData afterwards.
+// Panics if n is out of range.
+func PrepWriteFixed(sqe *SQE, fd int, indirect int, pool *RegisterBufferPool, n int, off uint64) {
+	prepFixed(sqe, ioringOpWriteFixed, fd, FixedIoVecOf(pool, indirect, n), off)
+}
+
+// PrepReadFixedIO prepares sqe for an IORING_OP_READ_FIXED into d from
+// its registered file (IOSQE_FIXED_FILE) at file offset off.
+func PrepReadFixedIO(sqe *SQE, d FixedIO, off uint64) {
+	prepFixed(sqe, ioringOpReadFixed, int(d.FileIndex), d.FixedIoVec, off)
+	sqe.Flags = iosqeFixedFile
+}
+
+// PrepWriteFixedIO prepares sqe for an IORING_OP_WRITE_FIXED of d to its
+// registered file (IOSQE_FIXED_FILE) at file offset off.
+func PrepWriteFixedIO(sqe *SQE, d FixedIO, off uint64) {
+	prepFixed(sqe, ioringOpWriteFixed, int(d.FileIndex), d.FixedIoVec, off)
+	sqe.Flags = iosqeFixedFile
+}
+
+// prepFixed resets sqe to a fixed-buffer read or write of v.
+func prepFixed(sqe *SQE, op uint8, fd int, v FixedIoVec, off uint64) {
+	*sqe = SQE{
+		Opcode:   op,
+		Fd:       int32(fd),
+		Off:      off,
+		Addr:     uint64(uintptr(unsafe.Pointer(v.Base))),
+		Len:      uint32(v.Len),
+		BufIndex: v.BufIndex,
+	}
+}
+
+// PrepRecvBufferSelect prepares sqe for an IORING_OP_RECV on socket fd
+// that lets the kernel pick a buffer from group g (IOSQE_BUFFER_SELECT).
+// Pass the buffer ID of the completion to g.Completed. The entry is reset
+// first; set UserData afterwards.
+func PrepRecvBufferSelect(sqe *SQE, fd int, g BufferGroup) {
+	*sqe = SQE{
+		Opcode:   ioringOpRecv,
+		Flags:    iosqeBufferSelect,
+		Fd:       int32(fd),
+		BufIndex: g.GroupID(),
+	}
+}
+
+// PrepProvideBuffers prepares sqe for the IORING_OP_PROVIDE_BUFFERS or
+// IORING_OP_REMOVE_BUFFERS described by op, as handed to the submit
+// function of a ProvidedBuffers. The entry is reset first.
+func PrepProvideBuffers(sqe *SQE, op ProvideBuffersOp) {
+	if op.Remove {
+		*sqe = SQE{Opcode: ioringOpRemoveBuffers, Fd: int32(op.Nbufs), BufIndex: op.Bgid}
+		return
+	}
+	*sqe = SQE{
+		Opcode:   ioringOpProvideBuffers,
+		Fd:       int32(op.Nbufs),
+		Off:      uint64(op.Bid),
+		Addr:     uint64(op.Addr),
+		Len:      op.Len,
+		BufIndex: op.Bgid,
+	}
+}
+
+// CQEBufferID returns the buffer ID carried in the flags of a completion
+// for a buffer-select operation. ok is false if the completion did not
+// consume a buffer (IORING_CQE_F_BUFFER unset).
+func CQEBufferID(flags uint32) (bid uint16, ok bool) {
+	if flags&cqeFBuffer == 0 {
+		return 0, false
+	}
+	return uint16(flags >> cqeBufferShift), true
+}
+
+// CQEBufMore reports whether a completion from an incremental BufRing
+// leaves its buffer with the kernel (IORING_CQE_F_BUF_MORE), as passed to
+// BufRing.Consume.
+func CQEBufMore(flags uint32) bool { return flags&cqeFBufMore != 0 }
+
+// Completion flag bits from linux/io_uring.h.
+const (
+	cqeFBuffer     = 1 << 0
+	cqeFBufMore    = 1 << 4
+	cqeBufferShift = 16
+)
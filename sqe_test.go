@@ -0,0 +1,70 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestPrepFixed(t *testing.T) {
+	pool := iobuf.NewRegisterBufferPool(4)
+	pool.Fill(func() iobuf.RegisterBuffer { return iobuf.RegisterBuffer{} })
+	idx, _ := pool.Get()
+	addr := uint64(uintptr(unsafe.Pointer(&pool.Bytes(idx)[0])))
+
+	sqe := iobuf.SQE{UserData: 99}
+	iobuf.PrepReadFixed(&sqe, 3, idx, pool, 4096)
+	if sqe.Opcode != 4 || sqe.Fd != 3 || sqe.Off != 4096 || sqe.Addr != addr ||
+		sqe.Len != iobuf.BufferSizeLarge || int(sqe.BufIndex) != idx || sqe.UserData != 0 {
+		t.Errorf("PrepReadFixed() = %+v", sqe)
+	}
+	iobuf.PrepWriteFixed(&sqe, 3, idx, pool, 100, 0)
+	if sqe.Opcode != 5 || sqe.Len != 100 || sqe.Addr != addr {
+		t.Errorf("PrepWriteFixed() = %+v", sqe)
+	}
+
+	m := iobuf.NewRegisteredIndexMap(pool, 8)
+	m.Bind(5, idx)
+	iobuf.PrepWriteFixedIO(&sqe, m.FixedIOOf(2, idx, 10), 0)
+	if sqe.Flags != 1 || sqe.Fd != 2 || sqe.BufIndex != 5 || sqe.Len != 10 {
+		t.Errorf("PrepWriteFixedIO() = %+v", sqe)
+	}
+}
+
+func TestPrepBufferGroup(t *testing.T) {
+	pool := iobuf.NewSmallBufferPool(2)
+	pool.Fill(iobuf.NewSmallBuffer)
+	var sqe iobuf.SQE
+	g := iobuf.NewProvidedBuffers(pool, 9, func(op iobuf.ProvideBuffersOp) error {
+		iobuf.PrepProvideBuffers(&sqe, op)
+		return nil
+	})
+	g.Replenish()
+	if sqe.Opcode != 31 || sqe.Fd != 2 || sqe.BufIndex != 9 || sqe.Len != iobuf.BufferSizeSmall || sqe.Off != 0 {
+		t.Errorf("PrepProvideBuffers() = %+v", sqe)
+	}
+	g.Close()
+	if sqe.Opcode != 32 || sqe.Fd != 2 || sqe.BufIndex != 9 {
+		t.Errorf("PrepProvideBuffers(remove) = %+v", sqe)
+	}
+
+	iobuf.PrepRecvBufferSelect(&sqe, 7, g)
+	if sqe.Opcode != 27 || sqe.Flags != 1<<5 || sqe.Fd != 7 || sqe.BufIndex != 9 {
+		t.Errorf("PrepRecvBufferSelect() = %+v", sqe)
+	}
+
+	if bid, ok := iobuf.CQEBufferID(3<<16 | 1); !ok || bid != 3 {
+		t.Errorf("CQEBufferID() = %d, %v; want 3, true", bid, ok)
+	}
+	if _, ok := iobuf.CQEBufferID(3 << 16); ok {
+		t.Error("CQEBufferID() without IORING_CQE_F_BUFFER reported a buffer")
+	}
+	if !iobuf.CQEBufMore(1<<4) || iobuf.CQEBufMore(1) {
+		t.Error("CQEBufMore() misreads IORING_CQE_F_BUF_MORE")
+	}
+}
@@ -0,0 +1,64 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import "time"
+
+// PoolStall describes a blocking Get or Put that has waited longer than
+// the threshold set with SetStallHandler.
+type PoolStall struct {
+	Op     PoolOp        // Operation that is waiting
+	Waited time.Duration // Time waited so far
+	Stats  PoolStats     // Pool identity and occupancy at detection
+}
+
+// poolStall is the stall detector of a pool.
+type poolStall struct {
+	threshold time.Duration
+	fn        func(PoolStall)
+}
+
+// SetStallHandler installs a stall detector calling fn when a blocking Get
+// or Put has waited longer than threshold, or removes it if fn is nil.
+//
+// An exhausted pool otherwise looks like a hung goroutine. The detector
+// reports each stalled call once, while it is still waiting, with the
+// pool's occupancy and, if it is registered, its name, so a stuck
+// pipeline can be told apart from an idle one. fn runs on the waiting
+// goroutine and must not call back into the pool. Loggers attached with
+// SetLogger report stalls on their own.
+//
+// SetStallHandler must be called before the pool is shared between
+// goroutines. Panics if fn is not nil and threshold is not positive.
+func (pool *BoundedPool[T]) SetStallHandler(threshold time.Duration, fn func(PoolStall)) {
+	if fn == nil {
+		pool.debug &^= poolDebugStall
+		pool.stall = nil
+		return
+	}
+	if threshold <= 0 {
+		panic("iobuf.BoundedPool.SetStallHandler: threshold must be positive")
+	}
+	pool.stall = &poolStall{threshold: threshold, fn: fn}
+	pool.debug |= poolDebugStall
+}
+
+// watchWait checks a wait against the stall thresholds, reporting it to
+// each watch mode at most once.
+func (pool *BoundedPool[T]) watchWait(op PoolOp, w *poolWait) {
+	pending := pool.debug & poolDebugWatch &^ w.fired
+	if pending == 0 {
+		return
+	}
+	waited := time.Since(w.start)
+	if pending&poolDebugLog != 0 && waited >= pool.log.threshold {
+		pool.logBlocked(op, waited)
+		w.fired |= poolDebugLog
+	}
+	if pending&poolDebugStall != 0 && waited >= pool.stall.threshold {
+		pool.stall.fn(PoolStall{Op: op, Waited: waited, Stats: pool.Stats()})
+		w.fired |= poolDebugStall
+	}
+}
@@ -0,0 +1,79 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestBoundedPool_SetStallHandler(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(2)
+	pool.Fill(iobuf.NewNanoBuffer)
+	iobuf.RegisterPool("test.stall", pool)
+	defer iobuf.UnregisterPool("test.stall")
+
+	var mu sync.Mutex
+	var stalls []iobuf.PoolStall
+	pool.SetStallHandler(5*time.Millisecond, func(s iobuf.PoolStall) {
+		mu.Lock()
+		stalls = append(stalls, s)
+		mu.Unlock()
+	})
+
+	a, _ := pool.Get()
+	b, _ := pool.Get()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		time.Sleep(50 * time.Millisecond)
+		_ = pool.Put(a)
+	}()
+	c, _ := pool.Get() // stalls until the goroutine puts
+	<-done
+	_ = pool.Put(b)
+	_ = pool.Put(c)
+
+	if len(stalls) != 1 {
+		t.Fatalf("reported %d stalls, want 1", len(stalls))
+	}
+	s := stalls[0]
+	if s.Op != iobuf.PoolGet || s.Waited < 5*time.Millisecond {
+		t.Errorf("stall = %v after %v, want Get after at least 5ms", s.Op, s.Waited)
+	}
+	if s.Stats.Name != "test.stall" || s.Stats.Capacity != 2 || s.Stats.InUse() != 2 {
+		t.Errorf("stall stats = %+v", s.Stats)
+	}
+
+	// A short wait is not reported.
+	pool.SetStallHandler(time.Hour, func(s iobuf.PoolStall) { t.Errorf("unexpected stall %+v", s) })
+	a, _ = pool.Get()
+	b, _ = pool.Get()
+	go func() { _ = pool.Put(a) }()
+	c, _ = pool.Get()
+	_ = pool.Put(b)
+	_ = pool.Put(c)
+}
+
+func TestBoundedPool_SetStallHandlerPanics(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(1)
+	tests := map[string]func(){
+		"zero threshold":     func() { pool.SetStallHandler(0, func(iobuf.PoolStall) {}) },
+		"negative threshold": func() { pool.SetStallHandler(-time.Second, func(iobuf.PoolStall) {}) },
+	}
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PoolStats is a snapshot of a BoundedPool's occupancy and activity.
+//
+// Capacity and Available are always reported. The activity counters only
+// advance while statistics are enabled with SetStats or RegisterPool, and
+// count operations through Get and Put. Hold is recorded independently,
+// while SetHoldTime is enabled.
+type PoolStats struct {
+	Name        string        // Name the pool is registered under, or empty
+	Tier        BufferTier    // Tier matching the item size, or TierEnd if none
+	Capacity    int           // Number of items the pool holds
+	Available   int           // Items currently in the pool
+	Gets        uint64        // Successful Get calls
+	Puts        uint64        // Successful Put calls
+	WouldBlocks uint64        // Non-blocking Get/Put calls that returned iox.ErrWouldBlock
+	Blocks      uint64        // Blocking Get/Put calls that had to wait
+	BlockTime   time.Duration // Total time spent waiting in blocking calls
+	Reclaimed   uint64        // Leaked buffers returned by SetReclaimLeaks
+	Eliminated  uint64        // Puts handed directly to a waiting Get, see SetElimination
+	Hold        HoldHistogram // Hold times, recorded while SetHoldTime is enabled
+
+	// Contention counters. A retry is a spin-wait of a Get or Put on a
+	// claimed slot whose previous occupant is still in flight; a backoff
+	// is one adaptive wait of a blocking call on an empty or full pool.
+	GetRetries uint64
+	PutRetries uint64
+	Backoffs   uint64
+}
+
+// InUse returns the number of items currently held by callers.
+func (s PoolStats) InUse() int { return s.Capacity - s.Available }
+
+// poolStats holds the activity counters of a pool with statistics enabled.
+type poolStats struct {
+	name        string
+	gets        atomic.Uint64
+	puts        atomic.Uint64
+	wouldBlocks atomic.Uint64
+	blocks      atomic.Uint64
+	blockNanos  atomic.Int64
+	reclaimed   atomic.Uint64
+	eliminated  atomic.Uint64
+	getRetries  atomic.Uint64
+	putRetries  atomic.Uint64
+	backoffs    atomic.Uint64
+}
+
+// SetStats enables or disables the activity counters reported by Stats.
+//
+// Counting costs an atomic add on a shared cache line per Get and Put, so
+// it is off by default. Disabling resets the counters. SetStats must be
+// called before the pool is shared between goroutines.
+func (pool *BoundedPool[T]) SetStats(enabled bool) {
+	if enabled {
+		if pool.stats == nil {
+			pool.stats = &poolStats{}
+		}
+		pool.debug |= poolDebugStats
+	} else {
+		pool.debug &^= poolDebugStats
+		pool.stats = nil
+	}
+}
+
+// Stats returns a snapshot of the pool's occupancy and activity. Counters
+// are read individually, so under concurrent use the snapshot is only
+// approximately consistent.
+func (pool *BoundedPool[T]) Stats() PoolStats {
+	s := PoolStats{
+		Tier:     pool.Config().Tier,
+		Capacity: int(pool.capacity),
+	}
+	if len(pool.items) == int(pool.capacity) {
+		s.Available = int(min(max(pool.count.Load(), 0), int64(pool.capacity)))
+	}
+	if st := pool.stats; st != nil {
+		s.Name = st.name
+		s.Gets = st.gets.Load()
+		s.Puts = st.puts.Load()
+		s.WouldBlocks = st.wouldBlocks.Load()
+		s.Blocks = st.blocks.Load()
+		s.BlockTime = time.Duration(st.blockNanos.Load())
+		s.Reclaimed = st.reclaimed.Load()
+		s.Eliminated = st.eliminated.Load()
+		s.GetRetries = st.getRetries.Load()
+		s.PutRetries = st.putRetries.Load()
+		s.Backoffs = st.backoffs.Load()
+	}
+	if h := pool.hold; h != nil {
+		s.Hold = h.snapshot()
+	}
+	return s
+}
+
+// registeredPool is the type-erased view of a pool in the registry.
+type registeredPool interface {
+	Stats() PoolStats
+	logUnregistered()
+}
+
+// registry holds the pools registered with RegisterPool.
+var registry struct {
+	sync.RWMutex
+	pools   map[string]registeredPool
+	logger  *slog.Logger
+	logOpts *LogOptions
+}
+
+// SetRegistryLogger sets the logger RegisterPool attaches, as with
+// SetLogger, to pools registered afterwards that have no logger of their
+// own. A nil logger stops attaching one; pools already registered keep
+// theirs.
+func SetRegistryLogger(logger *slog.Logger, opts *LogOptions) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.logger, registry.logOpts = logger, opts
+}
+
+// RegisterPool enables statistics on pool and registers it under name, so
+// its stats are reported by RegisteredPoolStats and the exporters built on
+// it. It also attaches the logger set with SetRegistryLogger, if the pool
+// has none. Pools are registered for the life of the process unless
+// removed with UnregisterPool. Like SetStats, RegisterPool must be called
+// before the pool is shared between goroutines. Panics if name is empty
+// or already registered.
+func RegisterPool[T BoundedPoolItem](name string, pool *BoundedPool[T]) {
+	if name == "" {
+		panic("iobuf.RegisterPool: empty name")
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.pools[name]; ok {
+		panic("iobuf.RegisterPool: name " + name + " already registered")
+	}
+	if registry.pools == nil {
+		registry.pools = make(map[string]registeredPool)
+	}
+	pool.SetStats(true)
+	pool.stats.name = name
+	if pool.log == nil && registry.logger != nil {
+		pool.SetLogger(registry.logger, registry.logOpts)
+	}
+	registry.pools[name] = pool
+}
+
+// UnregisterPool removes the pool registered under name, if any.
+// Statistics stay enabled on the pool. If the pool has a logger and callers
+// still hold buffers, the leak is logged.
+func UnregisterPool(name string) {
+	registry.Lock()
+	p, ok := registry.pools[name]
+	delete(registry.pools, name)
+	registry.Unlock()
+	if ok {
+		p.logUnregistered()
+	}
+}
+
+// RegisteredPoolStats returns the stats of every registered pool, sorted
+// by name.
+func RegisteredPoolStats() []PoolStats {
+	registry.RLock()
+	all := make([]PoolStats, 0, len(registry.pools))
+	for _, p := range registry.pools {
+		all = append(all, p.Stats())
+	}
+	registry.RUnlock()
+	slices.SortFunc(all, func(a, b PoolStats) int { return strings.Compare(a.Name, b.Name) })
+	return all
+}
+
+// TierStats aggregates the stats of the registered pools of one tier.
+//
+// Capacity is planned per tier rather than per pool instance, so TierStats
+// answers whether a tier as a whole is short of buffers.
+type TierStats struct {
+	Tier        BufferTier    // Tier of the pools, or TierEnd for pools matching none
+	Pools       int           // Number of registered pools of the tier
+	Capacity    int           // Total items the pools hold
+	Available   int           // Total items currently in the pools
+	Gets        uint64        // Total successful Get calls
+	Puts        uint64        // Total successful Put calls
+	WouldBlocks uint64        // Total calls that returned iox.ErrWouldBlock
+	Blocks      uint64        // Total blocking calls that had to wait
+	BlockTime   time.Duration // Total time spent waiting in blocking calls
+}
+
+// InUse returns the number of items of the tier currently held by callers.
+func (s TierStats) InUse() int { return s.Capacity - s.Available }
+
+// RegisteredTierStats returns the stats of the registered pools summed per
+// tier, sorted by tier. Tiers without registered pools are omitted.
+func RegisteredTierStats() []TierStats {
+	var tiers []TierStats
+	for _, p := range RegisteredPoolStats() {
+		i := slices.IndexFunc(tiers, func(s TierStats) bool { return s.Tier == p.Tier })
+		if i < 0 {
+			i = len(tiers)
+			tiers = append(tiers, TierStats{Tier: p.Tier})
+		}
+		s := &tiers[i]
+		s.Pools++
+		s.Capacity += p.Capacity
+		s.Available += p.Available
+		s.Gets += p.Gets
+		s.Puts += p.Puts
+		s.WouldBlocks += p.WouldBlocks
+		s.Blocks += p.Blocks
+		s.BlockTime += p.BlockTime
+	}
+	slices.SortFunc(tiers, func(a, b TierStats) int { return int(a.Tier - b.Tier) })
+	return tiers
+}
@@ -0,0 +1,168 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestBoundedPool_Stats(t *testing.T) {
+	pool := iobuf.NewSmallBufferPool(4)
+	pool.Fill(iobuf.NewSmallBuffer)
+	if s := pool.Stats(); s.Capacity != 4 || s.Available != 4 || s.Gets != 0 || s.Tier != iobuf.TierSmall {
+		t.Fatalf("Stats() before enabling = %+v", s)
+	}
+	pool.SetStats(true)
+	pool.SetNonblock(true)
+
+	var held []int
+	for range 4 {
+		idx, _ := pool.Get()
+		held = append(held, idx)
+	}
+	if _, err := pool.Get(); err == nil {
+		t.Fatal("Get() on an empty pool succeeded")
+	}
+	_ = pool.Put(held[0])
+	s := pool.Stats()
+	if s.Gets != 4 || s.Puts != 1 || s.WouldBlocks != 1 || s.Available != 1 || s.InUse() != 3 {
+		t.Errorf("Stats() = %+v", s)
+	}
+
+	// A blocking Get waits until another goroutine returns a buffer.
+	pool.SetNonblock(false)
+	_, _ = pool.Get()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		time.Sleep(10 * time.Millisecond)
+		_ = pool.Put(held[1])
+	}()
+	if _, err := pool.Get(); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	if s := pool.Stats(); s.Blocks != 1 || s.BlockTime <= 0 {
+		t.Errorf("Stats() after blocking = %d blocks, %v", s.Blocks, s.BlockTime)
+	}
+
+	pool.SetStats(false)
+	if s := pool.Stats(); s.Gets != 0 {
+		t.Errorf("Stats() after disabling = %+v", s)
+	}
+}
+
+func TestRegisterPool(t *testing.T) {
+	a := iobuf.NewNanoBufferPool(2)
+	a.Fill(iobuf.NewNanoBuffer)
+	b := iobuf.NewMicroBufferPool(2)
+	b.Fill(iobuf.NewMicroBuffer)
+	iobuf.RegisterPool("test.b", b)
+	iobuf.RegisterPool("test.a", a)
+	defer iobuf.UnregisterPool("test.a")
+	defer iobuf.UnregisterPool("test.b")
+
+	idx, _ := a.Get()
+	defer a.Put(idx)
+	var names []string
+	for _, s := range iobuf.RegisteredPoolStats() {
+		names = append(names, s.Name)
+		if s.Name == "test.a" && (s.Gets != 1 || s.InUse() != 1) {
+			t.Errorf("stats of test.a = %+v", s)
+		}
+	}
+	if len(names) < 2 || names[0] > names[1] {
+		t.Errorf("RegisteredPoolStats() names = %v, want sorted", names)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a name twice should panic")
+		}
+	}()
+	iobuf.RegisterPool("test.a", b)
+}
+
+func TestBoundedPool_StatsContention(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(2)
+	pool.Fill(iobuf.NewNanoBuffer)
+	pool.SetStats(true)
+
+	// Uncontended calls never retry or back off.
+	for range 100 {
+		idx, _ := pool.Get()
+		_ = pool.Put(idx)
+	}
+	if s := pool.Stats(); s.GetRetries != 0 || s.PutRetries != 0 || s.Backoffs != 0 {
+		t.Fatalf("uncontended Stats() = %+v", s)
+	}
+
+	// Goroutines sharing two buffers contend and wait for each other.
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for range 1000 {
+				idx, err := pool.Get()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				_ = pool.Put(idx)
+			}
+		})
+	}
+	wg.Wait()
+	s := pool.Stats()
+	if s.Backoffs < s.Blocks {
+		t.Errorf("Backoffs = %d, want at least one per blocking call (%d)", s.Backoffs, s.Blocks)
+	}
+	t.Logf("retries: get %d, put %d; backoffs %d", s.GetRetries, s.PutRetries, s.Backoffs)
+}
+
+func TestRegisteredTierStats(t *testing.T) {
+	a := iobuf.NewNanoBufferPool(2)
+	a.Fill(iobuf.NewNanoBuffer)
+	b := iobuf.NewNanoBufferPool(4)
+	b.Fill(iobuf.NewNanoBuffer)
+	b.SetNonblock(true)
+	c := iobuf.NewMicroBufferPool(2)
+	c.Fill(iobuf.NewMicroBuffer)
+	iobuf.RegisterPool("test.tier.a", a)
+	iobuf.RegisterPool("test.tier.b", b)
+	iobuf.RegisterPool("test.tier.c", c)
+	defer iobuf.UnregisterPool("test.tier.a")
+	defer iobuf.UnregisterPool("test.tier.b")
+	defer iobuf.UnregisterPool("test.tier.c")
+
+	var held []int
+	for range 4 {
+		idx, _ := b.Get()
+		held = append(held, idx)
+	}
+	_, _ = b.Get() // would block
+	idx, _ := a.Get()
+	defer a.Put(idx)
+	defer func() {
+		for _, idx := range held {
+			_ = b.Put(idx)
+		}
+	}()
+
+	tiers := iobuf.RegisteredTierStats()
+	if len(tiers) != 2 || tiers[0].Tier != iobuf.TierNano || tiers[1].Tier != iobuf.TierMicro {
+		t.Fatalf("RegisteredTierStats() = %+v, want Nano and Micro", tiers)
+	}
+	nano := tiers[0]
+	if nano.Pools != 2 || nano.Capacity != 6 || nano.InUse() != 5 || nano.Gets != 5 || nano.WouldBlocks != 1 {
+		t.Errorf("Nano tier = %+v", nano)
+	}
+	if micro := tiers[1]; micro.Pools != 1 || micro.Capacity != 2 || micro.InUse() != 0 {
+		t.Errorf("Micro tier = %+v", micro)
+	}
+}
@@ -0,0 +1,11 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+// System call numbers missing from package syscall on this architecture.
+const (
+	sysMemfdCreate = 319
+	sysGetcpu      = 309
+)
@@ -0,0 +1,14 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && !amd64 && !ppc64 && !ppc64le
+
+package iobuf
+
+import "syscall"
+
+const (
+	sysMemfdCreate = syscall.SYS_MEMFD_CREATE
+	sysGetcpu      = syscall.SYS_GETCPU
+)
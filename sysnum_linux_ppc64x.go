@@ -0,0 +1,13 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && (ppc64 || ppc64le)
+
+package iobuf
+
+// System call numbers missing from package syscall on this architecture.
+const (
+	sysMemfdCreate = 360
+	sysGetcpu      = 302
+)
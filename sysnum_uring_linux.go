@@ -0,0 +1,14 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && !mips64 && !mips64le
+
+package iobuf
+
+// io_uring system call numbers, which package syscall does not define.
+// They are the same on every architecture using the unified table.
+const (
+	sysIoUringSetup    = 425
+	sysIoUringRegister = 427
+)
@@ -0,0 +1,13 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && (mips64 || mips64le)
+
+package iobuf
+
+// io_uring system call numbers, which package syscall does not define.
+const (
+	sysIoUringSetup    = 5425
+	sysIoUringRegister = 5427
+)
@@ -0,0 +1,160 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// TierHistogram records requested buffer sizes per tier so pool capacities
+// can be provisioned from observed demand instead of guesswork.
+//
+// Call sites that pick a tier for a request use TierFor on the histogram in
+// place of the package-level TierFor; the chosen tier is counted. Size
+// classes registered with RegisterBufferSize are counted like the built-in
+// tiers. SuggestTiers then distributes a capacity budget across tiers in
+// proportion to the recorded demand, and NewPools provisions filled pools
+// with those capacities.
+//
+// The zero value is ready to use. TierHistogram is safe for concurrent use.
+type TierHistogram struct {
+	counts [TierEnd]atomic.Uint64
+
+	// Counters of registered tiers, indexed by t - TierCustom and grown
+	// under mu as new tiers are observed.
+	mu     sync.Mutex
+	custom atomic.Pointer[[]*atomic.Uint64]
+}
+
+// Observe records one request of size bytes.
+func (h *TierHistogram) Observe(size int) {
+	h.TierFor(size)
+}
+
+// TierFor returns the smallest built-in or registered tier that can hold
+// size bytes and records the request.
+func (h *TierHistogram) TierFor(size int) BufferTier {
+	t := TierFor(size)
+	h.counter(t).Add(1)
+	return t
+}
+
+// counter returns the counter of tier t, which must be a built-in or a
+// registered tier.
+func (h *TierHistogram) counter(t BufferTier) *atomic.Uint64 {
+	if t < TierEnd {
+		return &h.counts[t]
+	}
+	i := int(t - TierCustom)
+	if p := h.custom.Load(); p != nil && i < len(*p) {
+		return (*p)[i]
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var cs []*atomic.Uint64
+	if p := h.custom.Load(); p != nil {
+		cs = *p
+	}
+	if i < len(cs) {
+		return cs[i]
+	}
+	next := make([]*atomic.Uint64, i+1)
+	copy(next, cs)
+	for j := len(cs); j < len(next); j++ {
+		next[j] = new(atomic.Uint64)
+	}
+	h.custom.Store(&next)
+	return next[i]
+}
+
+// Count returns the number of requests recorded for tier t.
+// Returns 0 for tiers that are neither built-in nor registered.
+func (h *TierHistogram) Count(t BufferTier) uint64 {
+	if t >= 0 && t < TierEnd {
+		return h.counts[t].Load()
+	}
+	if p := h.custom.Load(); p != nil && t >= TierCustom && int(t-TierCustom) < len(*p) {
+		return (*p)[t-TierCustom].Load()
+	}
+	return 0
+}
+
+// Counts returns a snapshot of the request counts of every tier that has
+// seen at least one request.
+func (h *TierHistogram) Counts() map[BufferTier]uint64 {
+	counts := make(map[BufferTier]uint64)
+	for t := range TierEnd {
+		if c := h.counts[t].Load(); c != 0 {
+			counts[t] = c
+		}
+	}
+	if p := h.custom.Load(); p != nil {
+		for i, cnt := range *p {
+			if c := cnt.Load(); c != 0 {
+				counts[TierCustom+BufferTier(i)] = c
+			}
+		}
+	}
+	return counts
+}
+
+// Reset clears all recorded counts.
+func (h *TierHistogram) Reset() {
+	for t := range TierEnd {
+		h.counts[t].Store(0)
+	}
+	if p := h.custom.Load(); p != nil {
+		for _, cnt := range *p {
+			cnt.Store(0)
+		}
+	}
+}
+
+// SuggestTiers distributes total buffers across tiers in proportion to the
+// observed demand and returns the suggested capacity for each tier that
+// has seen at least one request.
+//
+// Every such tier is suggested a capacity of at least one, so the sum may
+// slightly exceed total when demand is very skewed. If nothing has been
+// observed yet or total < 1, the result is empty.
+//
+// The capacities can be passed directly to NewBufferPoolFor and the tier
+// pool constructors, which round them up to the next power of two.
+func (h *TierHistogram) SuggestTiers(total int) map[BufferTier]int {
+	counts := h.Counts()
+	var sum uint64
+	for _, c := range counts {
+		sum += c
+	}
+	caps := make(map[BufferTier]int, len(counts))
+	if sum == 0 || total < 1 {
+		return caps
+	}
+	for t, c := range counts {
+		// total*c/sum in 128 bits; c <= sum keeps the quotient in range.
+		hi, lo := bits.Mul64(uint64(total), c)
+		q, _ := bits.Div64(hi, lo, sum)
+		caps[t] = max(1, int(q))
+	}
+	return caps
+}
+
+// NewPools provisions capacity from the observed demand: it creates one
+// filled pool per tier suggested by SuggestTiers(total), sized with the
+// suggested capacity, and returns them keyed by tier.
+//
+// Pools of registered tiers hold []byte buffers, as with NewBufferPoolFor.
+// Typical use is to record a warm-up period, or the previous run's demand,
+// and build the production pools from it.
+func (h *TierHistogram) NewPools(total int) map[BufferTier]BufferPool {
+	caps := h.SuggestTiers(total)
+	pools := make(map[BufferTier]BufferPool, len(caps))
+	for t, c := range caps {
+		pools[t], _ = NewBufferPoolFor(t.Size(), c)
+	}
+	return pools
+}
@@ -0,0 +1,163 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestTierHistogram_Observe(t *testing.T) {
+	var h iobuf.TierHistogram
+
+	h.Observe(10)
+	h.Observe(iobuf.BufferSizeSmall)
+	h.Observe(iobuf.BufferSizeSmall)
+	if got := h.TierFor(100); got != iobuf.TierNano {
+		t.Errorf("TierFor(100) = %d, want TierNano", got)
+	}
+
+	if got := h.Count(iobuf.TierPico); got != 1 {
+		t.Errorf("Count(TierPico) = %d, want 1", got)
+	}
+	if got := h.Count(iobuf.TierSmall); got != 2 {
+		t.Errorf("Count(TierSmall) = %d, want 2", got)
+	}
+	if got := h.Count(iobuf.TierNano); got != 1 {
+		t.Errorf("Count(TierNano) = %d, want 1", got)
+	}
+	if got := h.Count(iobuf.TierEnd); got != 0 {
+		t.Errorf("Count(TierEnd) = %d, want 0", got)
+	}
+
+	if got := len(h.Counts()); got != 3 {
+		t.Errorf("len(Counts()) = %d, want 3", got)
+	}
+
+	h.Reset()
+	if counts := h.Counts(); len(counts) != 0 {
+		t.Errorf("after Reset, Counts() = %v, want empty", counts)
+	}
+}
+
+func TestTierHistogram_CustomTier(t *testing.T) {
+	jumbo := iobuf.RegisterBufferSize(9216)
+	var h iobuf.TierHistogram
+
+	if got := h.TierFor(9000); got != jumbo {
+		t.Fatalf("TierFor(9000) = %v, want %v", got, jumbo)
+	}
+	h.Observe(9216)
+	if got := h.Count(jumbo); got != 2 {
+		t.Errorf("Count(%v) = %d, want 2", jumbo, got)
+	}
+	if got := h.Count(iobuf.TierLarge); got != 0 {
+		t.Errorf("Count(TierLarge) = %d, want 0", got)
+	}
+	if caps := h.SuggestTiers(8); caps[jumbo] != 8 {
+		t.Errorf("SuggestTiers(8) = %v, want %v: 8", caps, jumbo)
+	}
+
+	h.Reset()
+	if got := h.Count(jumbo); got != 0 {
+		t.Errorf("Count(%v) after Reset = %d, want 0", jumbo, got)
+	}
+}
+
+func TestTierHistogram_SuggestTiers(t *testing.T) {
+	var h iobuf.TierHistogram
+
+	if caps := h.SuggestTiers(100); len(caps) != 0 {
+		t.Errorf("SuggestTiers on empty histogram = %v, want empty", caps)
+	}
+
+	for range 75 {
+		h.Observe(iobuf.BufferSizeSmall)
+	}
+	for range 24 {
+		h.Observe(iobuf.BufferSizeMedium)
+	}
+	h.Observe(iobuf.BufferSizeHuge)
+
+	caps := h.SuggestTiers(100)
+	if caps[iobuf.TierSmall] != 75 {
+		t.Errorf("caps[TierSmall] = %d, want 75", caps[iobuf.TierSmall])
+	}
+	if caps[iobuf.TierMedium] != 24 {
+		t.Errorf("caps[TierMedium] = %d, want 24", caps[iobuf.TierMedium])
+	}
+	if caps[iobuf.TierHuge] != 1 {
+		t.Errorf("caps[TierHuge] = %d, want 1", caps[iobuf.TierHuge])
+	}
+	if _, ok := caps[iobuf.TierPico]; ok {
+		t.Errorf("caps[TierPico] = %d, want no entry", caps[iobuf.TierPico])
+	}
+
+	// Rare tiers still get at least one buffer.
+	caps = h.SuggestTiers(10)
+	if caps[iobuf.TierHuge] != 1 {
+		t.Errorf("caps[TierHuge] with small total = %d, want 1", caps[iobuf.TierHuge])
+	}
+}
+
+func TestTierHistogram_SuggestTiersLargeCounts(t *testing.T) {
+	var h iobuf.TierHistogram
+	// total*count overflows 64 bits; the suggestion must not.
+	for range 3 {
+		h.Observe(iobuf.BufferSizeSmall)
+	}
+	h.Observe(iobuf.BufferSizeMedium)
+	total := math.MaxInt
+	caps := h.SuggestTiers(total)
+	if want := total/4*3 + total%4*3/4; caps[iobuf.TierSmall] != want {
+		t.Errorf("caps[TierSmall] = %d, want %d", caps[iobuf.TierSmall], want)
+	}
+}
+
+func TestTierHistogram_NewPools(t *testing.T) {
+	var h iobuf.TierHistogram
+	for range 3 {
+		h.Observe(iobuf.BufferSizeNano)
+	}
+	h.Observe(iobuf.BufferSizeMicro)
+
+	pools := h.NewPools(8)
+	if len(pools) != 2 {
+		t.Fatalf("NewPools() returned %d pools, want 2", len(pools))
+	}
+	nano := pools[iobuf.TierNano]
+	if nano == nil || nano.Cap() != 8 {
+		t.Fatalf("Nano pool = %v, want capacity 8", nano)
+	}
+	if got := len(nano.Bytes(0)); got != iobuf.BufferSizeNano {
+		t.Errorf("Nano pool buffer size = %d, want %d", got, iobuf.BufferSizeNano)
+	}
+	micro := pools[iobuf.TierMicro]
+	if micro == nil || micro.Cap() != 2 {
+		t.Fatalf("Micro pool = %v, want capacity 2", micro)
+	}
+	if _, err := micro.Get(); err != nil {
+		t.Errorf("Get() from provisioned pool failed: %v", err)
+	}
+}
+
+func TestTierHistogram_Concurrent(t *testing.T) {
+	var h iobuf.TierHistogram
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for range 1000 {
+				h.Observe(iobuf.BufferSizeMicro)
+			}
+		})
+	}
+	wg.Wait()
+	if got := h.Count(iobuf.TierMicro); got != 8000 {
+		t.Errorf("Count(TierMicro) = %d, want 8000", got)
+	}
+}
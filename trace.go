@@ -0,0 +1,50 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"context"
+	"runtime/trace"
+)
+
+// Region types of blocking waits in execution traces.
+const (
+	traceRegionGet = "iobuf.BoundedPool.Get wait"
+	traceRegionPut = "iobuf.BoundedPool.Put wait"
+)
+
+// SetTrace enables or disables marking blocking waits in execution traces.
+//
+// While a runtime/trace execution trace is being recorded, every blocking
+// Get on an empty pool and Put on a full pool is wrapped in a user region,
+// "iobuf.BoundedPool.Get wait" or "iobuf.BoundedPool.Put wait", preceded
+// by a log message in category "iobuf" naming the pool if it is
+// registered. go tool trace then shows where goroutines stall on buffer
+// exhaustion relative to the rest of the schedule. Calls that do not wait
+// are not traced. SetTrace must be called before the pool is shared
+// between goroutines.
+func (pool *BoundedPool[T]) SetTrace(enabled bool) {
+	if enabled {
+		pool.debug |= poolDebugTrace
+	} else {
+		pool.debug &^= poolDebugTrace
+	}
+}
+
+// traceWait starts the trace region of a blocking wait. It returns nil if
+// no trace is being recorded.
+func (pool *BoundedPool[T]) traceWait(op PoolOp) *trace.Region {
+	if !trace.IsEnabled() {
+		return nil
+	}
+	ctx := context.Background()
+	if st := pool.stats; st != nil && st.name != "" {
+		trace.Log(ctx, "iobuf", "pool "+st.name)
+	}
+	if op == PoolPut {
+		return trace.StartRegion(ctx, traceRegionPut)
+	}
+	return trace.StartRegion(ctx, traceRegionGet)
+}
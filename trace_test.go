@@ -0,0 +1,54 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"bytes"
+	"runtime/trace"
+	"testing"
+	"time"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestBoundedPool_SetTrace(t *testing.T) {
+	pool := iobuf.NewNanoBufferPool(1)
+	pool.Fill(iobuf.NewNanoBuffer)
+	pool.SetTrace(true)
+	iobuf.RegisterPool("test.trace", pool)
+	defer iobuf.UnregisterPool("test.trace")
+
+	// Waits outside a recorded trace are not marked.
+	idx, _ := pool.Get()
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		_ = pool.Put(idx)
+	}()
+	idx, _ = pool.Get()
+
+	var buf bytes.Buffer
+	if err := trace.Start(&buf); err != nil {
+		t.Skipf("trace.Start: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		time.Sleep(10 * time.Millisecond)
+		_ = pool.Put(idx)
+	}()
+	idx, _ = pool.Get() // waits inside the trace
+	<-done
+	trace.Stop()
+	_ = pool.Put(idx)
+
+	for _, want := range []string{"iobuf.BoundedPool.Get wait", "pool test.trace"} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Errorf("trace does not contain %q", want)
+		}
+	}
+	if bytes.Contains(buf.Bytes(), []byte("iobuf.BoundedPool.Put wait")) {
+		t.Error("trace contains a Put wait, but no Put blocked")
+	}
+}
@@ -4,13 +4,17 @@
 
 package iobuf
 
-import "net"
+import (
+	"net"
+	"os"
+)
 
 // PageSize is the memory page size used for aligned allocations.
 //
-// The default value (4 KiB) matches the typical x86-64 and ARM64 page size.
-// Use SetPageSize to configure for systems with different page sizes.
-var PageSize uintptr = 4096
+// It defaults to the system page size reported by os.Getpagesize, e.g.,
+// 4 KiB on x86-64 and 4, 16 or 64 KiB on ARM64 depending on the kernel.
+// Use SetPageSize to override it, for example to align to huge pages.
+var PageSize = uintptr(os.Getpagesize())
 
 // SetPageSize updates the package-level page size used for aligned allocations.
 //
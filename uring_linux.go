@@ -0,0 +1,180 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// io_uring_register opcodes from linux/io_uring.h.
+const (
+	ioringUnregisterBuffers     = 1
+	ioringRegisterProbe         = 8
+	ioringRegisterBuffers2      = 15
+	ioringRegisterBuffersUpdate = 16
+	ioringRegisterPbufRing      = 22
+	ioringUnregisterPbufRing    = 23
+	ioringRegisterCloneBuffers  = 30
+	ioringRegisterZcrxIfq       = 32
+	ioringRegisterMemRegion     = 34
+)
+
+// ioringOpRecvZC is IORING_OP_RECV_ZC, the zero-copy receive opcode.
+const ioringOpRecvZC = 58
+
+// ioUringOpSupported is IO_URING_OP_SUPPORTED.
+const ioUringOpSupported = 1 << 0
+
+// ioUringProbe matches struct io_uring_probe with room for every opcode.
+type ioUringProbe struct {
+	lastOp uint8
+	opsLen uint8
+	_      uint16
+	_      [3]uint32
+	ops    [256]ioUringProbeOp
+}
+
+// ioUringProbeOp matches struct io_uring_probe_op.
+type ioUringProbeOp struct {
+	op    uint8
+	_     uint8
+	flags uint16
+	_     uint32
+}
+
+// ioringRsrcRegisterSparse is IORING_RSRC_REGISTER_SPARSE.
+const ioringRsrcRegisterSparse = 1 << 0
+
+// iouPbufRingInc is IOU_PBUF_RING_INC.
+const iouPbufRingInc = 1 << 1
+
+// ioUringBufReg matches struct io_uring_buf_reg.
+type ioUringBufReg struct {
+	ringAddr    uint64
+	ringEntries uint32
+	bgid        uint16
+	flags       uint16
+	_           [3]uint64
+}
+
+// ioringMemRegionTypeUser is IORING_MEM_REGION_TYPE_USER: the region is
+// memory supplied by the application.
+const ioringMemRegionTypeUser = 1 << 0
+
+// ioUringRegionDesc matches struct io_uring_region_desc.
+type ioUringRegionDesc struct {
+	userAddr   uint64
+	size       uint64
+	flags      uint32
+	id         uint32
+	mmapOffset uint64
+	_          [4]uint64
+}
+
+// ioringMemRegionRegWaitArg is IORING_MEM_REGION_REG_WAIT_ARG.
+const ioringMemRegionRegWaitArg = 1 << 0
+
+// ioUringMemRegionReg matches struct io_uring_mem_region_reg.
+type ioUringMemRegionReg struct {
+	regionUptr uint64
+	flags      uint64
+	_          [2]uint64
+}
+
+// ioUringZcrxAreaReg matches struct io_uring_zcrx_area_reg.
+type ioUringZcrxAreaReg struct {
+	addr        uint64
+	len         uint64
+	rqAreaToken uint64
+	flags       uint32
+	dmabufFd    uint32
+	_           [2]uint64
+}
+
+// ioUringZcrxOffsets matches struct io_uring_zcrx_offsets.
+type ioUringZcrxOffsets struct {
+	head uint32
+	tail uint32
+	rqes uint32
+	_    uint32
+	_    [2]uint64
+}
+
+// ioUringZcrxIfqReg matches struct io_uring_zcrx_ifq_reg.
+type ioUringZcrxIfqReg struct {
+	ifIdx     uint32
+	ifRxq     uint32
+	rqEntries uint32
+	flags     uint32
+	areaPtr   uint64
+	regionPtr uint64
+	offsets   ioUringZcrxOffsets
+	zcrxID    uint32
+	_         uint32
+	_         [3]uint64
+}
+
+// ioringRegisterDstReplace is IORING_REGISTER_DST_REPLACE.
+const ioringRegisterDstReplace = 1 << 1
+
+// ioUringCloneBuffers matches struct io_uring_clone_buffers.
+type ioUringCloneBuffers struct {
+	srcFd  uint32
+	flags  uint32
+	srcOff uint32
+	dstOff uint32
+	nr     uint32
+	_      [3]uint32
+}
+
+// ioUringRsrcRegister matches struct io_uring_rsrc_register.
+type ioUringRsrcRegister struct {
+	nr    uint32
+	flags uint32
+	_     uint64
+	data  uint64
+	tags  uint64
+}
+
+// ioUringRsrcUpdate2 matches struct io_uring_rsrc_update2.
+type ioUringRsrcUpdate2 struct {
+	offset uint32
+	_      uint32
+	data   uint64
+	tags   uint64
+	nr     uint32
+	_      uint32
+}
+
+// The argument structs must match the kernel ABI sizes exactly.
+var (
+	_ [unsafe.Sizeof(ioUringBufReg{}) - 40]struct{}
+	_ [40 - unsafe.Sizeof(ioUringBufReg{})]struct{}
+	_ [unsafe.Sizeof(ioUringRegionDesc{}) - 64]struct{}
+	_ [64 - unsafe.Sizeof(ioUringRegionDesc{})]struct{}
+	_ [unsafe.Sizeof(ioUringMemRegionReg{}) - 32]struct{}
+	_ [32 - unsafe.Sizeof(ioUringMemRegionReg{})]struct{}
+	_ [unsafe.Sizeof(ioUringZcrxAreaReg{}) - 48]struct{}
+	_ [48 - unsafe.Sizeof(ioUringZcrxAreaReg{})]struct{}
+	_ [unsafe.Sizeof(ioUringZcrxIfqReg{}) - 96]struct{}
+	_ [96 - unsafe.Sizeof(ioUringZcrxIfqReg{})]struct{}
+	_ [unsafe.Sizeof(ioUringCloneBuffers{}) - 32]struct{}
+	_ [32 - unsafe.Sizeof(ioUringCloneBuffers{})]struct{}
+	_ [unsafe.Sizeof(ioUringRsrcRegister{}) - 32]struct{}
+	_ [32 - unsafe.Sizeof(ioUringRsrcRegister{})]struct{}
+	_ [unsafe.Sizeof(ioUringRsrcUpdate2{}) - 32]struct{}
+	_ [32 - unsafe.Sizeof(ioUringRsrcUpdate2{})]struct{}
+)
+
+// ioUringRegister calls io_uring_register on the ring ringFd.
+func ioUringRegister(ringFd int, opcode uint32, arg unsafe.Pointer, nrArgs uint32) error {
+	_, _, errno := syscall.Syscall6(sysIoUringRegister, uintptr(ringFd), uintptr(opcode), uintptr(arg), uintptr(nrArgs), 0, 0)
+	if errno != 0 {
+		return os.NewSyscallError("io_uring_register", errno)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && !mips64 && !mips64le
+
+package iobuf_test
+
+import (
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+// sysIoUringSetup is the io_uring_setup system call number.
+const sysIoUringSetup = 425
+
+// newTestRing creates a small io_uring instance for registration tests and
+// skips the test if io_uring is unavailable.
+func newTestRing(t *testing.T) int {
+	t.Helper()
+	var params [120]byte // struct io_uring_params
+	fd, _, errno := syscall.Syscall(sysIoUringSetup, 4, uintptr(unsafe.Pointer(&params[0])), 0)
+	if errno != 0 {
+		t.Skipf("io_uring not available: %v", errno)
+	}
+	t.Cleanup(func() { syscall.Close(int(fd)) })
+	return int(fd)
+}
@@ -0,0 +1,134 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+
+	"code.hybscloud.com/iox"
+)
+
+// Flags from linux/splice.h.
+const (
+	spliceFNonblock = 0x2
+	spliceFGift     = 0x8
+)
+
+// Vmsplice maps the memory described by vec into the pipe fd (vmsplice)
+// and returns the number of bytes transferred, which may be short.
+//
+// Without gift the pipe references the caller's pages, which must not be
+// modified until the reader has consumed them. With gift the pages are
+// given to the kernel, which may move them into the page cache; the
+// memory must never be touched again, and every element must be
+// page-aligned and a whole number of pages. GiftPool manages buffers for
+// gifting.
+//
+// Vmsplice never waits for room in the pipe: it returns iox.ErrWouldBlock
+// when the pipe is full, whether or not fd is in non-blocking mode, since
+// vmsplice ignores O_NONBLOCK on the pipe.
+func Vmsplice(fd int, vec []IoVec, gift bool) (int, error) {
+	var flags uintptr = spliceFNonblock
+	if gift {
+		flags |= spliceFGift
+	}
+	r, _, errno := syscall.Syscall6(syscall.SYS_VMSPLICE, uintptr(fd),
+		uintptr(unsafe.Pointer(unsafe.SliceData(vec))), uintptr(len(vec)), flags, 0, 0)
+	if errno == syscall.EAGAIN {
+		return 0, iox.ErrWouldBlock
+	}
+	if errno != 0 {
+		return 0, os.NewSyscallError("vmsplice", errno)
+	}
+	return int(r), nil
+}
+
+// NewGiftPool returns a filled pool of capacity buffers of size bytes,
+// rounded up to whole pages. Release it with Close.
+// Panics if size < 1 or capacity < 1.
+func NewGiftPool(size int, capacity int) (*GiftPool, error) {
+	if size < 1 || capacity < 1 {
+		panic("iobuf.NewGiftPool: size and capacity must be positive")
+	}
+	ps := syscall.Getpagesize()
+	size = (size + ps - 1) &^ (ps - 1)
+	pool := NewBoundedPool[[]byte](capacity)
+	bufs := make([][]byte, pool.Cap())
+	for i := range bufs {
+		b, err := mapRegion(size, 0)
+		if err != nil {
+			for _, b := range bufs[:i] {
+				_ = unmapRegion(b)
+			}
+			return nil, err
+		}
+		bufs[i] = b
+	}
+	i := 0
+	pool.Fill(func() []byte {
+		b := bufs[i]
+		i++
+		return b
+	})
+	return &GiftPool{pool: pool, size: size, sent: make([]int, pool.Cap())}, nil
+}
+
+// Gift splices the first n bytes of the buffer at indirect into the pipe
+// fd with SPLICE_F_GIFT, then replaces the buffer with fresh memory and
+// returns the index to the pool. The caller must hold the index and must
+// not use the old buffer afterwards.
+//
+// If the pipe fills up, Gift returns the bytes transferred by this call
+// and iox.ErrWouldBlock, and the caller keeps holding the index; calling
+// Gift again with the same arguments resumes where it stopped. Other
+// errors also leave the index with the caller, and a retry likewise
+// resumes without splicing any byte twice.
+// Panics if n is not in (0, BufferSize()].
+func (p *GiftPool) Gift(fd int, indirect int, n int) (int, error) {
+	if n < 1 || n > p.size {
+		panic("iobuf.GiftPool.Gift: length out of range")
+	}
+	buf := p.pool.Value(indirect)
+	done := 0
+	for p.sent[indirect] < n {
+		off := p.sent[indirect]
+		vec := []IoVec{{Base: &buf[off], Len: uint64(n - off)}}
+		m, err := Vmsplice(fd, vec, true)
+		p.sent[indirect] += m
+		done += m
+		if err != nil {
+			return done, err
+		}
+	}
+	fresh, err := mapRegion(p.size, 0)
+	if err != nil {
+		return done, err
+	}
+	p.sent[indirect] = 0
+	old := buf[:cap(buf)]
+	p.pool.SetValue(indirect, fresh)
+	if err := unmapRegion(old); err != nil {
+		return done, err
+	}
+	return done, p.pool.Put(indirect)
+}
+
+// Close unmaps the buffers of all indices currently in the pool. Buffers
+// held by callers are not released. The pool must not be used afterwards.
+func (p *GiftPool) Close() error {
+	p.pool.SetNonblock(true)
+	var err error
+	for {
+		indirect, gerr := p.pool.Get()
+		if gerr != nil {
+			return err
+		}
+		if uerr := unmapRegion(p.pool.Value(indirect)); err == nil {
+			err = uerr
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+	"code.hybscloud.com/iox"
+)
+
+func TestVmsplice(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	a, b := []byte("zero-"), []byte("copy")
+	vec := []iobuf.IoVec{{Base: &a[0], Len: 5}, {Base: &b[0], Len: 4}}
+	n, err := iobuf.Vmsplice(int(w.Fd()), vec, false)
+	if err != nil || n != 9 {
+		t.Fatalf("Vmsplice() = %d, %v", n, err)
+	}
+	got := make([]byte, 9)
+	if _, err := io.ReadFull(r, got); err != nil || string(got) != "zero-copy" {
+		t.Errorf("read %q, %v", got, err)
+	}
+}
+
+func TestGiftPool_Gift(t *testing.T) {
+	p, err := iobuf.NewGiftPool(1, 2)
+	if err != nil {
+		t.Fatalf("NewGiftPool() failed: %v", err)
+	}
+	defer p.Close()
+	if p.BufferSize() != int(iobuf.PageSize) {
+		t.Errorf("BufferSize() = %d, want one page", p.BufferSize())
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	idx, _ := p.Get()
+	old := p.Bytes(idx)
+	payload := bytes.Repeat([]byte("gift"), p.BufferSize()/4)
+	copy(old, payload)
+	n, err := p.Gift(int(w.Fd()), idx, p.BufferSize())
+	if err != nil || n != p.BufferSize() {
+		t.Fatalf("Gift() = %d, %v", n, err)
+	}
+	if &p.Bytes(idx)[0] == &old[0] {
+		t.Error("gifted buffer was not replaced")
+	}
+	got := make([]byte, p.BufferSize())
+	if _, err := io.ReadFull(r, got); err != nil || !bytes.Equal(got, payload) {
+		t.Errorf("pipe contents differ from the gifted buffer: %v", err)
+	}
+
+	// The index went back to the pool with its replacement buffer.
+	p.Pool().SetNonblock(true)
+	seen := 0
+	for {
+		i, err := p.Get()
+		if errors.Is(err, iox.ErrWouldBlock) {
+			break
+		}
+		seen++
+		p.Bytes(i)[0] = 1
+		defer p.Put(i)
+	}
+	if seen != 2 {
+		t.Errorf("pool holds %d buffers after Gift, want 2", seen)
+	}
+}
+
+func TestGiftPool_GiftWouldBlock(t *testing.T) {
+	p, err := iobuf.NewGiftPool(1<<16, 1)
+	if err != nil {
+		t.Fatalf("NewGiftPool() failed: %v", err)
+	}
+	defer p.Close()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	fd := int(w.Fd())
+
+	// A 64 KiB buffer overflows a pipe shrunk to one page, so Gift must
+	// stop and resume without splicing any byte twice.
+	const fSetPipeSz = 1031
+	if _, _, errno := syscall.Syscall(syscall.SYS_FCNTL, uintptr(fd), fSetPipeSz, uintptr(iobuf.PageSize)); errno != 0 {
+		t.Skipf("F_SETPIPE_SZ: %v", errno)
+	}
+	idx, _ := p.Get()
+	buf := p.Bytes(idx)
+	for i := range buf {
+		buf[i] = byte(i / int(iobuf.PageSize))
+	}
+	want := bytes.Clone(buf)
+
+	var got []byte
+	chunk := make([]byte, p.BufferSize())
+	total := 0
+	for total < p.BufferSize() {
+		n, err := p.Gift(fd, idx, p.BufferSize())
+		total += n
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, iox.ErrWouldBlock) {
+			t.Fatalf("Gift() failed: %v", err)
+		}
+		m, _ := r.Read(chunk)
+		got = append(got, chunk[:m]...)
+	}
+	w.Close()
+	rest, _ := io.ReadAll(r)
+	got = append(got, rest...)
+	if total != p.BufferSize() || !bytes.Equal(got, want) {
+		t.Errorf("received %d bytes after %d gifted, want the buffer once", len(got), total)
+	}
+}
@@ -0,0 +1,34 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package iobuf
+
+import "errors"
+
+// Vmsplice maps memory into a pipe. It is only supported on Linux;
+// elsewhere it returns errors.ErrUnsupported.
+func Vmsplice(fd int, vec []IoVec, gift bool) (int, error) {
+	return 0, errors.ErrUnsupported
+}
+
+// NewGiftPool returns a pool of buffers for gifting to a pipe. It is only
+// supported on Linux; elsewhere it returns errors.ErrUnsupported.
+// Panics if size < 1 or capacity < 1.
+func NewGiftPool(size int, capacity int) (*GiftPool, error) {
+	if size < 1 || capacity < 1 {
+		panic("iobuf.NewGiftPool: size and capacity must be positive")
+	}
+	return nil, errors.ErrUnsupported
+}
+
+// Gift splices a buffer into a pipe. It is only supported on Linux;
+// elsewhere it returns errors.ErrUnsupported.
+func (p *GiftPool) Gift(fd int, indirect int, n int) (int, error) {
+	return 0, errors.ErrUnsupported
+}
+
+// Close releases the pool.
+func (p *GiftPool) Close() error { return nil }
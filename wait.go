@@ -0,0 +1,171 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"code.hybscloud.com/iox"
+)
+
+// WaitStrategy decides how a blocking Get waits while the pool is empty,
+// and a blocking Put while it is full.
+//
+// Pools without a strategy use the adaptive iox.Backoff, which suits
+// waits on external I/O. Low-latency loops may prefer SpinWait or
+// YieldWait, and batch workloads ParkWait, which sleeps until the pool
+// changes. Strategies are shared by every goroutine using a pool, so they
+// keep per-call state in the attempt count and must be safe for
+// concurrent use.
+type WaitStrategy interface {
+	// Wait is called each time a blocking call of op has found the pool
+	// empty (PoolGet) or full (PoolPut), before it tries again. attempt
+	// counts the waits of the call, starting at 1. ready reports whether
+	// the pool may have become usable for op; a strategy that sleeps until
+	// Wake must check it after arranging to be woken, so a Wake in between
+	// is not lost.
+	Wait(op PoolOp, attempt int, ready func() bool)
+
+	// Wake is called when calls of op can proceed again: for PoolGet when
+	// the pool goes from empty to non-empty, for PoolPut when it goes from
+	// full to non-full. It runs on the goroutine making the transition and
+	// must not block.
+	Wake(op PoolOp)
+}
+
+// poolWaiter is a WaitStrategy installed on a pool, with the readiness
+// checks passed to it.
+type poolWaiter struct {
+	strategy WaitStrategy
+	ready    [2]func() bool // Indexed by PoolOp
+}
+
+// SetWaitStrategy sets the strategy blocking Get and Put calls wait with,
+// or restores the default iox.Backoff if ws is nil.
+//
+// The strategy is part of setting a pool up: SetWaitStrategy must be
+// called before the pool is shared between goroutines. A strategy that
+// sleeps until Wake delays stall reports of SetStallHandler and SetLogger
+// until the call wakes.
+func (pool *BoundedPool[T]) SetWaitStrategy(ws WaitStrategy) {
+	if ws == nil {
+		pool.waiter = nil
+	} else {
+		pool.waiter = &poolWaiter{strategy: ws, ready: [2]func() bool{
+			PoolGet: func() bool { return pool.count.Load() > 0 },
+			PoolPut: func() bool { return pool.count.Load() < int64(pool.capacity) },
+		}}
+	}
+	pool.updateSignal()
+}
+
+// wait makes a blocking call of op wait before its next attempt, with the
+// pool's strategy or with aw.
+func (pool *BoundedPool[T]) wait(op PoolOp, aw *iox.Backoff, attempt int) {
+	if w := pool.waiter; w != nil {
+		w.strategy.Wait(op, attempt, w.ready[op])
+		return
+	}
+	aw.Wait()
+}
+
+// SpinWait is a WaitStrategy retrying immediately, keeping the processor
+// busy for the lowest wake-up latency. It only suits pools whose waits
+// are short and whose goroutines have processors to themselves.
+type SpinWait struct{}
+
+// Wait returns immediately.
+func (SpinWait) Wait(op PoolOp, attempt int, ready func() bool) {}
+
+// Wake does nothing.
+func (SpinWait) Wake(op PoolOp) {}
+
+// YieldWait is a WaitStrategy yielding the processor to other goroutines
+// between attempts, with runtime.Gosched.
+type YieldWait struct{}
+
+// Wait yields the processor.
+func (YieldWait) Wait(op PoolOp, attempt int, ready func() bool) { runtime.Gosched() }
+
+// Wake does nothing.
+func (YieldWait) Wake(op PoolOp) {}
+
+// SleepWait is a WaitStrategy sleeping between attempts, for Min on the
+// first wait and twice as long on every further one, up to Max. A zero
+// Min sleeps for a microsecond at first, and a zero Max caps the sleep at
+// a millisecond.
+type SleepWait struct {
+	Min, Max time.Duration
+}
+
+// Wait sleeps for the backoff of attempt.
+func (s SleepWait) Wait(op PoolOp, attempt int, ready func() bool) {
+	lo, hi := s.Min, s.Max
+	if lo <= 0 {
+		lo = time.Microsecond
+	}
+	if hi <= 0 {
+		hi = time.Millisecond
+	}
+	d := hi
+	if shift := attempt - 1; shift < 32 && lo<<shift < hi {
+		d = lo << shift
+	}
+	time.Sleep(d)
+}
+
+// Wake does nothing.
+func (SleepWait) Wake(op PoolOp) {}
+
+// ParkWait is a WaitStrategy parking the waiting goroutine until the pool
+// changes, so waits cost no processor time at all. Waking takes a mutex,
+// which Get and Put only pay on the transitions a parked call waits for.
+//
+// The zero value is ready to use. A ParkWait may be shared by several
+// pools, at the cost of waking calls for changes of pools they do not
+// wait on. It must not be copied after first use.
+type ParkWait struct {
+	_ noCopy
+
+	parked atomic.Int32
+	mu     sync.Mutex
+	ch     [2]chan struct{} // Closed by Wake, indexed by PoolOp
+}
+
+// Wait parks the calling goroutine until Wake(op), unless ready reports
+// the pool usable first.
+func (p *ParkWait) Wait(op PoolOp, attempt int, ready func() bool) {
+	// Announce the wait before checking ready: a Wake that misses the
+	// announcement follows a change that ready then observes.
+	p.parked.Add(1)
+	defer p.parked.Add(-1)
+	p.mu.Lock()
+	if ready() {
+		p.mu.Unlock()
+		return
+	}
+	if p.ch[op] == nil {
+		p.ch[op] = make(chan struct{})
+	}
+	ch := p.ch[op]
+	p.mu.Unlock()
+	<-ch
+}
+
+// Wake unparks every goroutine waiting for op.
+func (p *ParkWait) Wake(op PoolOp) {
+	if p.parked.Load() == 0 {
+		return
+	}
+	p.mu.Lock()
+	if ch := p.ch[op]; ch != nil {
+		close(ch)
+		p.ch[op] = nil
+	}
+	p.mu.Unlock()
+}
@@ -0,0 +1,130 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"code.hybscloud.com/iobuf"
+)
+
+// countingWait is a user-defined WaitStrategy counting its calls.
+type countingWait struct {
+	waits, wakes atomic.Int32
+}
+
+func (w *countingWait) Wait(op iobuf.PoolOp, attempt int, ready func() bool) {
+	w.waits.Add(1)
+	time.Sleep(time.Millisecond)
+}
+
+func (w *countingWait) Wake(op iobuf.PoolOp) { w.wakes.Add(1) }
+
+func waitStrategies() map[string]iobuf.WaitStrategy {
+	return map[string]iobuf.WaitStrategy{
+		"default": nil,
+		"spin":    iobuf.SpinWait{},
+		"yield":   iobuf.YieldWait{},
+		"sleep":   iobuf.SleepWait{Min: time.Microsecond, Max: 100 * time.Microsecond},
+		"park":    &iobuf.ParkWait{},
+		"custom":  &countingWait{},
+	}
+}
+
+func TestBoundedPool_WaitStrategy(t *testing.T) {
+	for name, ws := range waitStrategies() {
+		t.Run(name, func(t *testing.T) {
+			pool := iobuf.NewBoundedPool[int](1)
+			pool.Fill(func() int { return 0 })
+			pool.SetWaitStrategy(ws)
+			idx, _ := pool.Get()
+
+			// Get waits on the empty pool until the Put.
+			got := make(chan int)
+			go func() {
+				i, _ := pool.Get()
+				got <- i
+			}()
+			time.Sleep(5 * time.Millisecond)
+			_ = pool.Put(idx)
+			idx = <-got
+
+			// Put waits on the full pool until the Get.
+			_ = pool.Put(idx)
+			done := make(chan struct{})
+			go func() {
+				i, _ := pool.Get()
+				_ = pool.Put(i)
+				close(done)
+			}()
+			<-done
+		})
+	}
+
+	ws := &countingWait{}
+	pool := iobuf.NewBoundedPool[int](1)
+	pool.Fill(func() int { return 0 })
+	pool.SetWaitStrategy(ws)
+	idx, _ := pool.Get()
+	time.AfterFunc(10*time.Millisecond, func() { _ = pool.Put(idx) })
+	_, _ = pool.Get()
+	if ws.waits.Load() == 0 || ws.wakes.Load() == 0 {
+		t.Errorf("custom strategy: %d waits, %d wakes, want both called", ws.waits.Load(), ws.wakes.Load())
+	}
+}
+
+func TestParkWait_Stress(t *testing.T) {
+	const (
+		goroutines = 8
+		iterations = 2000
+	)
+	pool := iobuf.NewBoundedPool[int](2)
+	pool.Fill(func() int { return 0 })
+	pool.SetWaitStrategy(&iobuf.ParkWait{})
+
+	// More goroutines than items keep Gets parking; a lost wake-up hangs
+	// the test.
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Go(func() {
+			for range iterations {
+				idx, err := pool.Get()
+				if err != nil {
+					t.Errorf("Get() failed: %v", err)
+					return
+				}
+				_ = pool.Put(idx)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestCachedPool_ParkWait(t *testing.T) {
+	const goroutines = 8
+	pool := iobuf.NewBoundedPool[int](2)
+	pool.Fill(func() int { return 0 })
+	pool.SetWaitStrategy(&iobuf.ParkWait{})
+	cached := iobuf.NewCachedPool(pool, 4)
+
+	// Indices cached in shards wake Gets parked on the empty shared pool.
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Go(func() {
+			for range 1000 {
+				idx, err := cached.Get()
+				if err != nil {
+					t.Errorf("Get() failed: %v", err)
+					return
+				}
+				_ = cached.Put(idx)
+			}
+		})
+	}
+	wg.Wait()
+}
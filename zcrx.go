@@ -0,0 +1,165 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"sync/atomic"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf/internal"
+	"code.hybscloud.com/iox"
+)
+
+// zcrxAreaShift is IORING_ZCRX_AREA_SHIFT: the bits of a zero-copy receive
+// offset above it identify the area, the bits below it locate the data.
+const zcrxAreaShift = 48
+
+// zcrxRqe matches struct io_uring_zcrx_rqe, a refill ring entry.
+type zcrxRqe struct {
+	off uint64
+	len uint32
+	_   uint32
+}
+
+// zcrxRingHeader is the room reserved ahead of the refill ring entries for
+// the head and tail, each on its own cache line.
+const zcrxRingHeader = 256
+
+// ZcrxArea is the memory of an io_uring zero-copy receive interface queue
+// (IORING_REGISTER_ZCRX_IFQ, Linux 6.15+): the area the NIC receives
+// packet payloads into, and the refill ring through which the application
+// gives consumed chunks back.
+//
+// Zero-copy receive completions report an offset into the area instead of
+// a buffer ID. Received turns that offset into a view of the data, and
+// Recycle queues the chunk on the refill ring once the application is done
+// with it; Flush makes queued chunks visible to the kernel. The offset
+// arithmetic and the ring protocol stay inside iobuf.
+//
+// A ZcrxArea must not be used from multiple goroutines concurrently.
+type ZcrxArea struct {
+	alloc  *HugePageAllocator
+	area   []byte
+	region []byte
+	head   *uint32
+	tail   *uint32
+	rqes   []zcrxRqe
+	mask   uint32
+	local  uint32 // Tail including entries not yet flushed
+	token  uint64 // Area token from registration, or'ed into refill offsets
+	id     uint32
+}
+
+// NewZcrxArea allocates an area of areaSize bytes, rounded up to whole
+// pages, and a refill ring of rqEntries entries. The area comes from
+// alloc, or from 2 MiB huge pages falling back to regular pages if alloc
+// is nil. Register it with an io_uring instance before use.
+// Panics if areaSize < 1 or rqEntries is not a power of two up to 32768.
+func NewZcrxArea(areaSize int, rqEntries int, alloc *HugePageAllocator) (*ZcrxArea, error) {
+	if areaSize < 1 {
+		panic("iobuf.NewZcrxArea: area size must be positive")
+	}
+	if rqEntries < 1 || rqEntries > 1<<15 || rqEntries&(rqEntries-1) != 0 {
+		panic("iobuf.NewZcrxArea: entries must be a power of two up to 32768")
+	}
+	if alloc == nil {
+		alloc = NewHugePageAllocator(HugePageSize2M, HugePageFallback)
+	}
+	page := int(PageSize)
+	area, err := alloc.Alloc((areaSize + page - 1) &^ (page - 1))
+	if err != nil {
+		return nil, err
+	}
+	size := zcrxRingHeader + rqEntries*int(unsafe.Sizeof(zcrxRqe{}))
+	region, err := mapRegion((size+page-1)&^(page-1), 0)
+	if err != nil {
+		_ = alloc.Free(area)
+		return nil, err
+	}
+	z := &ZcrxArea{alloc: alloc, area: area, region: region}
+	z.setRing(0, zcrxRingHeader/4, zcrxRingHeader/2, uint32(rqEntries))
+	return z, nil
+}
+
+// setRing locates the refill ring head, tail and entries at the given
+// offsets into the ring region.
+func (z *ZcrxArea) setRing(head, tail, rqes, entries uint32) {
+	z.head = (*uint32)(unsafe.Pointer(&z.region[head]))
+	z.tail = (*uint32)(unsafe.Pointer(&z.region[tail]))
+	z.rqes = unsafe.Slice((*zcrxRqe)(unsafe.Pointer(&z.region[rqes])), entries)
+	z.mask = entries - 1
+}
+
+// Area returns the receive area.
+func (z *ZcrxArea) Area() []byte { return z.area }
+
+// ID returns the zero-copy receive instance ID assigned at registration,
+// to be placed in the zcrx_ifq_idx field of IORING_OP_RECV_ZC submissions.
+func (z *ZcrxArea) ID() uint32 { return z.id }
+
+// Register registers the area as a zero-copy receive interface queue of
+// the io_uring instance ringFd for hardware receive queue rxQueue of the
+// network interface ifIndex. The ring must use 32-byte CQEs
+// (IORING_SETUP_CQE32), and registering usually requires CAP_NET_ADMIN.
+// Returns errors.ErrUnsupported on platforms without io_uring.
+func (z *ZcrxArea) Register(ringFd int, ifIndex, rxQueue uint32) error {
+	var l internal.ZcrxLayout
+	var err error
+	if f := internal.FakeRingOf(ringFd); f != nil {
+		l, err = f.RegisterZcrx(z.area, z.region, z.mask+1)
+	} else {
+		l, err = registerZcrx(ringFd, z.area, z.region, z.mask+1, ifIndex, rxQueue)
+	}
+	if err != nil {
+		return err
+	}
+	z.setRing(l.Head, l.Tail, l.Rqes, l.Entries)
+	z.token = l.Token
+	z.id = l.ID
+	z.local = 0
+	return nil
+}
+
+// Received returns the n bytes of received data at off, the offset
+// reported by a zero-copy receive completion (struct io_uring_zcrx_cqe).
+// The view is valid until the chunk is passed to Recycle.
+// Panics if the data lies outside the area.
+func (z *ZcrxArea) Received(off uint64, n int) []byte {
+	o := off & (1<<zcrxAreaShift - 1)
+	if n < 0 || o > uint64(len(z.area)) || uint64(n) > uint64(len(z.area))-o {
+		panic("iobuf.ZcrxArea.Received: data outside the area")
+	}
+	return z.area[o : o+uint64(n) : o+uint64(n)]
+}
+
+// Recycle queues the n-byte chunk at completion offset off on the refill
+// ring, returning it to the kernel for future receives at the next Flush.
+// Returns iox.ErrWouldBlock if the refill ring is full; Flush and retry
+// once the kernel has caught up.
+func (z *ZcrxArea) Recycle(off uint64, n int) error {
+	if z.local-atomic.LoadUint32(z.head) > z.mask {
+		return iox.ErrWouldBlock
+	}
+	z.rqes[z.local&z.mask] = zcrxRqe{off: off&(1<<zcrxAreaShift-1) | z.token, len: uint32(n)}
+	z.local++
+	return nil
+}
+
+// Flush publishes the chunks queued by Recycle to the kernel.
+func (z *ZcrxArea) Flush() {
+	atomic.StoreUint32(z.tail, z.local)
+}
+
+// Close releases the area and the refill ring. The kernel keeps both
+// pinned while the interface queue is registered, so call it only after
+// the io_uring instance has been closed.
+func (z *ZcrxArea) Close() error {
+	err := z.alloc.Free(z.area)
+	if uerr := unmapRegion(z.region); err == nil {
+		err = uerr
+	}
+	*z = ZcrxArea{}
+	return err
+}
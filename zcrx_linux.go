@@ -0,0 +1,47 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+import (
+	"runtime"
+	"unsafe"
+
+	"code.hybscloud.com/iobuf/internal"
+)
+
+// registerZcrx registers area and the refill ring region as a zero-copy
+// receive interface queue and returns the layout the kernel chose.
+func registerZcrx(ringFd int, area, region []byte, entries uint32, ifIndex, rxQueue uint32) (internal.ZcrxLayout, error) {
+	areaReg := ioUringZcrxAreaReg{
+		addr: uint64(uintptr(unsafe.Pointer(unsafe.SliceData(area)))),
+		len:  uint64(len(area)),
+	}
+	desc := ioUringRegionDesc{
+		userAddr: uint64(uintptr(unsafe.Pointer(unsafe.SliceData(region)))),
+		size:     uint64(len(region)),
+		flags:    ioringMemRegionTypeUser,
+	}
+	reg := ioUringZcrxIfqReg{
+		ifIdx:     ifIndex,
+		ifRxq:     rxQueue,
+		rqEntries: entries,
+		areaPtr:   uint64(uintptr(unsafe.Pointer(&areaReg))),
+		regionPtr: uint64(uintptr(unsafe.Pointer(&desc))),
+	}
+	err := ioUringRegister(ringFd, ioringRegisterZcrxIfq, unsafe.Pointer(&reg), 1)
+	runtime.KeepAlive(&areaReg)
+	runtime.KeepAlive(&desc)
+	if err != nil {
+		return internal.ZcrxLayout{}, err
+	}
+	return internal.ZcrxLayout{
+		Head:    reg.offsets.head,
+		Tail:    reg.offsets.tail,
+		Rqes:    reg.offsets.rqes,
+		Entries: reg.rqEntries,
+		Token:   areaReg.rqAreaToken,
+		ID:      reg.zcrxID,
+	}, nil
+}
@@ -0,0 +1,18 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package iobuf
+
+import (
+	"errors"
+
+	"code.hybscloud.com/iobuf/internal"
+)
+
+// registerZcrx is not supported without io_uring.
+func registerZcrx(ringFd int, area, region []byte, entries uint32, ifIndex, rxQueue uint32) (internal.ZcrxLayout, error) {
+	return internal.ZcrxLayout{}, errors.ErrUnsupported
+}
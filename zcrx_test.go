@@ -0,0 +1,63 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"errors"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+	"code.hybscloud.com/iox"
+)
+
+func TestZcrxArea_ReceiveRecycle(t *testing.T) {
+	z, err := iobuf.NewZcrxArea(10000, 4, nil)
+	if err != nil {
+		t.Fatalf("NewZcrxArea() failed: %v", err)
+	}
+	defer z.Close()
+	if len(z.Area())%int(iobuf.PageSize) != 0 || len(z.Area()) < 10000 {
+		t.Fatalf("Area() len = %d, want whole pages covering 10000", len(z.Area()))
+	}
+
+	copy(z.Area()[4096:], "payload")
+	// The area bits above the offset must be ignored.
+	data := z.Received(3<<48|4096, 7)
+	if string(data) != "payload" || cap(data) != 7 {
+		t.Errorf("Received() = %q (cap %d)", data, cap(data))
+	}
+
+	for i := range 4 {
+		if err := z.Recycle(uint64(i)*4096, 4096); err != nil {
+			t.Fatalf("Recycle(%d) failed: %v", i, err)
+		}
+	}
+	if err := z.Recycle(0, 4096); !errors.Is(err, iox.ErrWouldBlock) {
+		t.Errorf("Recycle() on a full refill ring = %v, want ErrWouldBlock", err)
+	}
+	z.Flush()
+}
+
+func TestZcrxArea_Panics(t *testing.T) {
+	z, err := iobuf.NewZcrxArea(4096, 2, nil)
+	if err != nil {
+		t.Fatalf("NewZcrxArea() failed: %v", err)
+	}
+	defer z.Close()
+	for name, f := range map[string]func(){
+		"outside area": func() { z.Received(4000, 200) },
+		"entries":      func() { _, _ = iobuf.NewZcrxArea(4096, 3, nil) },
+		"size":         func() { _, _ = iobuf.NewZcrxArea(0, 2, nil) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s should panic", name)
+				}
+			}()
+			f()
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf
+
+// MemZero sets every byte of b to zero.
+//
+// The clear builtin compiles to the runtime's memclr, which is already
+// written in assembly for each architecture: AVX2 and non-temporal stores
+// for large blocks on amd64, DC ZVA cache-line zeroing on arm64. MemZero
+// names that fast path for tier buffers and is what the zeroing policies
+// of SetZeroOnGet and SetZeroOnPut use, so hand-rolled byte loops can be
+// replaced with a single call.
+func MemZero(b []byte) {
+	clear(b)
+}
+
+// SetZeroOnGet enables or disables zeroing every buffer before Get returns
+// it, so callers never observe a previous holder's data.
+//
+// Zeroing is only available for byte-array and []byte items, and must be
+// configured before the pool is shared between goroutines. Panics for
+// other item types.
+func (pool *BoundedPool[T]) SetZeroOnGet(enabled bool) {
+	if enabled {
+		requireByteItems[T]("zero on get")
+		pool.debug |= poolZeroOnGet
+	} else {
+		pool.debug &^= poolZeroOnGet
+	}
+}
+
+// SetZeroOnPut enables or disables zeroing every buffer as Put releases it,
+// so sensitive payloads do not linger in idle buffers. A Put returning
+// iox.ErrWouldBlock leaves the buffer intact.
+//
+// Zeroing is only available for byte-array and []byte items, and must be
+// configured before the pool is shared between goroutines. Panics for
+// other item types.
+func (pool *BoundedPool[T]) SetZeroOnPut(enabled bool) {
+	if enabled {
+		requireByteItems[T]("zero on put")
+		pool.debug |= poolZeroOnPut
+	} else {
+		pool.debug &^= poolZeroOnPut
+	}
+}
@@ -0,0 +1,71 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iobuf_test
+
+import (
+	"bytes"
+	"testing"
+
+	"code.hybscloud.com/iobuf"
+)
+
+func TestMemZero(t *testing.T) {
+	for _, n := range []int{0, 1, 31, 64, 4096, iobuf.BufferSizeHuge} {
+		b := bytes.Repeat([]byte{0xFF}, n+2)
+		iobuf.MemZero(b[1 : n+1])
+		if b[0] != 0xFF || b[n+1] != 0xFF {
+			t.Errorf("MemZero(%d) wrote outside the slice", n)
+		}
+		if !bytes.Equal(b[1:n+1], make([]byte, n)) {
+			t.Errorf("MemZero(%d) left non-zero bytes", n)
+		}
+	}
+}
+
+func TestBoundedPool_ZeroOnGet(t *testing.T) {
+	pool := iobuf.NewMicroBufferPool(1)
+	pool.Fill(iobuf.NewMicroBuffer)
+	pool.SetZeroOnGet(true)
+	idx, _ := pool.Get()
+	copy(pool.Bytes(idx), "secret")
+	_ = pool.Put(idx)
+	if string(pool.Bytes(idx)[:6]) != "secret" {
+		t.Error("zero on get cleared the buffer on Put")
+	}
+	idx, _ = pool.Get()
+	if !bytes.Equal(pool.Bytes(idx), make([]byte, iobuf.BufferSizeMicro)) {
+		t.Error("buffer not zeroed on Get")
+	}
+}
+
+func TestBoundedPool_ZeroOnPut(t *testing.T) {
+	pool := iobuf.NewBoundedPool[[]byte](2)
+	pool.Fill(func() []byte { return make([]byte, 64) })
+	pool.SetZeroOnPut(true)
+	cached := iobuf.NewCachedPool(pool, 2)
+	idx, _ := cached.Get()
+	copy(pool.Bytes(idx), "secret")
+	_ = cached.Put(idx)
+	if !bytes.Equal(pool.Bytes(idx), make([]byte, 64)) {
+		t.Error("buffer not zeroed on Put")
+	}
+
+	pool.SetZeroOnPut(false)
+	idx, _ = cached.Get()
+	copy(pool.Bytes(idx), "kept")
+	_ = cached.Put(idx)
+	if string(pool.Bytes(idx)[:4]) != "kept" {
+		t.Error("zero on put still active after disabling")
+	}
+}
+
+func TestBoundedPool_ZeroOnGetPanicsForNonBytes(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("SetZeroOnGet on a non-byte pool should panic")
+		}
+	}()
+	iobuf.NewBoundedPool[int](1).SetZeroOnGet(true)
+}